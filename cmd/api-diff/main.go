@@ -0,0 +1,53 @@
+package main
+
+import (
+	goflag "flag"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog"
+
+	diffargs "yunion.io/x/code-generator/pkg/api-diff/args"
+	"yunion.io/x/code-generator/pkg/api-diff/diff"
+	swaggergen "yunion.io/x/code-generator/pkg/swagger-gen/generators"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	arguments, customArgs := diffargs.NewDefaults()
+
+	arguments.AddFlags(pflag.CommandLine)
+	customArgs.AddFlags(pflag.CommandLine)
+	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
+	pflag.Parse()
+
+	if err := diffargs.Validate(arguments); err != nil {
+		klog.Errorf("Error: %v", err)
+		os.Exit(1)
+	}
+
+	var (
+		report *diff.Report
+		err    error
+	)
+	if customArgs.IsSpec() {
+		report, err = diff.CompareSpecs(customArgs.Old, customArgs.New)
+	} else {
+		var patternConf *swaggergen.PatternConfig
+		patternConf, err = swaggergen.LoadPatternConfig(customArgs.PatternConfig)
+		if err == nil {
+			report, err = diff.CompareTrees(arguments, customArgs.Old, customArgs.New, patternConf)
+		}
+	}
+	if err != nil {
+		klog.Errorf("Error: %v", err)
+		os.Exit(1)
+	}
+	if err := report.Write(os.Stdout, customArgs.Format); err != nil {
+		klog.Errorf("Error: %v", err)
+		os.Exit(1)
+	}
+	if report.Breaking() > 0 {
+		os.Exit(1)
+	}
+}
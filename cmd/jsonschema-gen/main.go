@@ -0,0 +1,42 @@
+package main
+
+import (
+	goflag "flag"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+	"k8s.io/gengo/args"
+	"k8s.io/klog"
+
+	schemaargs "yunion.io/x/code-generator/pkg/jsonschema-gen/args"
+	"yunion.io/x/code-generator/pkg/jsonschema-gen/generators"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	arguments, customArgs := schemaargs.NewDefaults()
+
+	// Override defaults.
+	arguments.GoHeaderFilePath = filepath.Join(args.DefaultSourceTree(), "yunion.io/x/code-generator/boilerplate/boilerplate.go.txt")
+
+	arguments.AddFlags(pflag.CommandLine)
+	customArgs.AddFlags(pflag.CommandLine)
+	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
+	pflag.Parse()
+
+	if err := schemaargs.Validate(arguments); err != nil {
+		klog.Errorf("Error: %v", err)
+		os.Exit(1)
+	}
+
+	if err := arguments.Execute(
+		generators.NameSystems(),
+		generators.DefaultNameSystem(),
+		generators.Packages,
+	); err != nil {
+		klog.Errorf("Error: %v", err)
+		os.Exit(1)
+	}
+	klog.V(2).Info("Completed successfully.")
+}
@@ -1,28 +1,81 @@
 package main
 
 import (
+	goflag "flag"
+	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/spf13/pflag"
 	"k8s.io/gengo/args"
 	"k8s.io/klog"
 
+	"yunion.io/x/code-generator/pkg/common/cache"
+	"yunion.io/x/code-generator/pkg/common/diagnostics"
+	apiargs "yunion.io/x/code-generator/pkg/model-api-gen/args"
 	"yunion.io/x/code-generator/pkg/model-api-gen/generators"
 )
 
 func main() {
 	klog.InitFlags(nil)
-	arguments := args.Default()
+	arguments, customArgs := apiargs.NewDefaults()
 
 	// Override defaults.
-	arguments.OutputFileBaseName = "zz_generated.model"
 	arguments.GoHeaderFilePath = filepath.Join(args.DefaultSourceTree(), "yunion.io/x/code-generator/boilerplate/boilerplate.go.txt")
 
-	if err := arguments.Execute(
-		generators.NameSystems(),
-		generators.DefaultNameSystem(),
-		generators.Packages,
-	); err != nil {
+	arguments.AddFlags(pflag.CommandLine)
+	customArgs.AddFlags(pflag.CommandLine)
+	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
+	pflag.Parse()
+
+	if err := apiargs.Validate(arguments); err != nil {
+		klog.Errorf("Error: %v", err)
+		os.Exit(1)
+	}
+
+	if customArgs.DebugModels {
+		if err := generators.DebugModels(arguments, os.Stdout); err != nil {
+			klog.Errorf("Error: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	generate := func(inputDirs []string) error {
+		arguments.InputDirs = inputDirs
+		err := arguments.Execute(
+			generators.NameSystems(),
+			generators.DefaultNameSystem(),
+			generators.Packages,
+		)
+		customArgs.Diagnostics.Report(os.Stderr)
+		if customArgs.DiagnosticsReport != "" {
+			if err := customArgs.Diagnostics.WriteJSON(customArgs.DiagnosticsReport); err != nil {
+				return fmt.Errorf("write diagnostics report: %v", err)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		failOn, _ := diagnostics.ParseSeverity(customArgs.FailOn)
+		if customArgs.Diagnostics.Failed(failOn) {
+			return fmt.Errorf("generation has diagnostics of %s or above", failOn)
+		}
+		return nil
+	}
+	run := &cache.Run{
+		Tool:        "model-api-gen",
+		Args:        cache.FlagArgs(pflag.CommandLine),
+		ConfigFiles: []string{arguments.GoHeaderFilePath, customArgs.MappingFile},
+		InputDirs:   arguments.InputDirs,
+		OutputFiles: []string{customArgs.DiagnosticsReport},
+	}
+	cacheOpts := customArgs.Cache
+	if arguments.VerifyOnly {
+		// verifying writes nothing to record
+		cacheOpts.CacheDir = ""
+	}
+	if err := cacheOpts.Do(run, generate); err != nil {
 		klog.Errorf("Error: %v", err)
 		os.Exit(1)
 	}
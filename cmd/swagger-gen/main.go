@@ -1,28 +1,82 @@
 package main
 
 import (
+	goflag "flag"
 	"os"
 	"path/filepath"
 
+	"github.com/spf13/pflag"
 	"k8s.io/gengo/args"
 	"k8s.io/klog"
 
+	"yunion.io/x/code-generator/pkg/common/cache"
+	swaggerargs "yunion.io/x/code-generator/pkg/swagger-gen/args"
 	"yunion.io/x/code-generator/pkg/swagger-gen/generators"
 )
 
+const (
+	// lintTagsCmd reports the misuse of comment tags of input packages
+	// instead of generating
+	lintTagsCmd = "lint-tags"
+)
+
 func main() {
 	klog.InitFlags(nil)
-	arguments := args.Default()
+	arguments, customArgs := swaggerargs.NewDefaults()
+
+	lintTags := len(os.Args) > 1 && os.Args[1] == lintTagsCmd
+	if lintTags {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
 
 	// Override defaults.
-	arguments.OutputFileBaseName = "zz_generated.swagger_spec"
 	arguments.GoHeaderFilePath = filepath.Join(args.DefaultSourceTree(), "yunion.io/x/onecloud/scripts/copyright.txt")
 
-	if err := arguments.Execute(
-		generators.NameSystems(),
-		generators.DefaultNameSystem(),
-		generators.Packages,
-	); err != nil {
+	arguments.AddFlags(pflag.CommandLine)
+	customArgs.AddFlags(pflag.CommandLine)
+	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
+	pflag.Parse()
+
+	if lintTags {
+		errs, err := generators.LintTags(arguments, os.Stdout)
+		if err != nil {
+			klog.Errorf("Error: %v", err)
+			os.Exit(1)
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := swaggerargs.Validate(arguments); err != nil {
+		klog.Errorf("Error: %v", err)
+		os.Exit(1)
+	}
+
+	generate := func(inputDirs []string) error {
+		arguments.InputDirs = inputDirs
+		return arguments.Execute(
+			generators.NameSystems(),
+			generators.DefaultNameSystem(),
+			generators.Packages,
+		)
+	}
+	run := &cache.Run{
+		Tool:        "swagger-gen",
+		Args:        cache.FlagArgs(pflag.CommandLine),
+		ConfigFiles: []string{arguments.GoHeaderFilePath, customArgs.MetaConfig, customArgs.PatternConfig},
+		InputDirs:   arguments.InputDirs,
+		// a merged spec is generated of all input packages
+		Together:    customArgs.Merge,
+		OutputFiles: []string{customArgs.ConflictReport},
+	}
+	cacheOpts := customArgs.Cache
+	if arguments.VerifyOnly {
+		// verifying writes nothing to record
+		cacheOpts.CacheDir = ""
+	}
+	if err := cacheOpts.Do(run, generate); err != nil {
 		klog.Errorf("Error: %v", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// The pinned swagger-ui and redoc bundles listed in assets/VERSIONS are
+// fetched by hack/fetch-swagger-serve-assets.sh when swagger-serve is built
+// by make or go generate, assetsFS embeds them, see assets_embed.go.
+//
+//go:generate ../../../hack/fetch-swagger-serve-assets.sh
+
+const (
+	assetsDir = "assets"
+
+	offlineSwaggerUIDir = "swagger-ui"
+	offlineRedocDir     = "redoc"
+	offlineRedocJS      = "redoc.standalone.js"
+
+	// noOfflineAssetsTag builds swagger-serve without the bundles
+	noOfflineAssetsTag = "noofflineassets"
+)
+
+// offlineAssets returns the embedded files required by flavor, the paths
+// are relative to assets dir.
+func offlineAssets(flavor string) []string {
+	switch flavor {
+	case "swagger":
+		return []string{
+			path.Join(offlineSwaggerUIDir, "swagger-ui.css"),
+			path.Join(offlineSwaggerUIDir, "swagger-ui-bundle.js"),
+			path.Join(offlineSwaggerUIDir, "swagger-ui-standalone-preset.js"),
+		}
+	case "redoc":
+		return []string{
+			path.Join(offlineRedocDir, offlineRedocJS),
+		}
+	}
+	return nil
+}
+
+// copyOfflineAssets copies the bundles of flavor in assets dir of fsys into
+// outputDir, fsys is assetsFS except in tests.
+func copyOfflineAssets(fsys fs.FS, flavor string, outputDir string) error {
+	for _, asset := range offlineAssets(flavor) {
+		data, err := fs.ReadFile(fsys, path.Join(assetsDir, asset))
+		if err != nil {
+			return errors.Wrapf(err, "offline asset %s is not embedded, swagger-serve is built with tag %s", asset, noOfflineAssetsTag)
+		}
+		dstPath := filepath.Join(outputDir, filepath.FromSlash(asset))
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(dstPath, data, 0644); err != nil {
+			return errors.Wrapf(err, "write %s", dstPath)
+		}
+	}
+	return nil
+}
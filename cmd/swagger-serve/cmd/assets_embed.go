@@ -0,0 +1,17 @@
+//go:build !noofflineassets
+
+package cmd
+
+import "embed"
+
+// assetsFS embeds the bundles of offlineAssets, they are named one by one so
+// that swagger-serve fails to build if they aren't fetched. Build with tag
+// noofflineassets to leave them out, --offline fails then.
+//
+//go:embed assets/VERSIONS
+//go:embed assets/swagger-ui/swagger-ui.css assets/swagger-ui/swagger-ui-bundle.js assets/swagger-ui/swagger-ui-standalone-preset.js
+//go:embed assets/redoc/redoc.standalone.js
+var assetsFS embed.FS
+
+// offlineAssetsEmbedded is true if assetsFS has the bundles.
+const offlineAssetsEmbedded = true
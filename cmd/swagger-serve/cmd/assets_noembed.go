@@ -0,0 +1,13 @@
+//go:build noofflineassets
+
+package cmd
+
+import "embed"
+
+// assetsFS only embeds assets/VERSIONS when built with tag noofflineassets.
+//
+//go:embed assets/VERSIONS
+var assetsFS embed.FS
+
+// offlineAssetsEmbedded is true if assetsFS has the bundles.
+const offlineAssetsEmbedded = false
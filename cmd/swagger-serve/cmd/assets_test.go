@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCopyOfflineAssets(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for _, flavor := range []string{"swagger", "redoc"} {
+		for _, asset := range offlineAssets(flavor) {
+			fsys[path.Join(assetsDir, asset)] = &fstest.MapFile{Data: []byte("// " + asset)}
+		}
+	}
+	for _, flavor := range []string{"swagger", "redoc"} {
+		outputDir := t.TempDir()
+		if err := copyOfflineAssets(fsys, flavor, outputDir); err != nil {
+			t.Fatalf("copy %s assets: %v", flavor, err)
+		}
+		for _, asset := range offlineAssets(flavor) {
+			data, err := ioutil.ReadFile(filepath.Join(outputDir, filepath.FromSlash(asset)))
+			if err != nil {
+				t.Errorf("read copied %s: %v", asset, err)
+			} else if string(data) != "// "+asset {
+				t.Errorf("copied %s = %q", asset, data)
+			}
+		}
+	}
+
+	if err := copyOfflineAssets(fstest.MapFS{}, "redoc", t.TempDir()); err == nil {
+		t.Errorf("expect error of missing assets")
+	}
+}
+
+// TestEmbeddedOfflineAssets checks the bundles embedded into swagger-serve
+// match their sha256 in assets/VERSIONS.
+func TestEmbeddedOfflineAssets(t *testing.T) {
+	if !offlineAssetsEmbedded {
+		t.Skipf("offline assets aren't embedded with tag %s", noOfflineAssetsTag)
+	}
+	versions, err := fs.ReadFile(assetsFS, path.Join(assetsDir, "VERSIONS"))
+	if err != nil {
+		t.Fatalf("read VERSIONS: %v", err)
+	}
+	digests := make(map[string]string)
+	for _, line := range strings.Split(string(versions), "\n") {
+		if fields := strings.Fields(line); len(fields) == 3 && fields[0] == "sha256" {
+			digests[fields[1]] = fields[2]
+		}
+	}
+	for _, flavor := range []string{"swagger", "redoc"} {
+		for _, asset := range offlineAssets(flavor) {
+			data, err := fs.ReadFile(assetsFS, path.Join(assetsDir, asset))
+			if err != nil {
+				t.Fatalf("read embedded %s: %v", asset, err)
+			}
+			if got := fmt.Sprintf("%x", sha256.Sum256(data)); got != digests[asset] {
+				t.Errorf("sha256 of embedded %s = %s, VERSIONS has %q", asset, got, digests[asset])
+			}
+		}
+		if err := copyOfflineAssets(assetsFS, flavor, t.TempDir()); err != nil {
+			t.Errorf("copy embedded %s assets: %v", flavor, err)
+		}
+	}
+}
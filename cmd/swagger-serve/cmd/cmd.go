@@ -11,8 +11,6 @@ import (
 	"path/filepath"
 	"sort"
 
-	"github.com/go-openapi/loads"
-	"github.com/go-openapi/loads/fmts"
 	"github.com/pkg/errors"
 	"github.com/skratchdot/open-golang/open"
 	"github.com/spf13/cobra"
@@ -27,6 +25,7 @@ func NewRootCmd() *cobra.Command {
 		Short: "swagger serve for onecloud project",
 	}
 	cmds.AddCommand(newGenerateCmd())
+	cmds.AddCommand(newWatchCmd())
 	return cmds
 }
 
@@ -36,11 +35,14 @@ type generateOption struct {
 	Flavor    string
 	RedocURL  string
 	UIVersion string
+	Offline   bool
 	OutputDir string
 	Serve     bool
 	NoOpen    bool
 	ServeAddr string
 	ServePort int
+	// LiveReload injects the script reloading page on spec changes
+	LiveReload bool
 }
 
 func urlJoin(prefix string, suffix ...string) string {
@@ -55,9 +57,19 @@ func urlJoin(prefix string, suffix ...string) string {
 }
 
 func (o generateOption) urlJoin(filePath string) string {
+	if o.Offline {
+		return path.Join(offlineSwaggerUIDir, filePath)
+	}
 	return urlJoin(o.CDNPrefix, o.UIVersion, filePath)
 }
 
+func (o generateOption) redocURL() string {
+	if o.Offline {
+		return path.Join(offlineRedocDir, offlineRedocJS)
+	}
+	return o.RedocURL
+}
+
 func (o generateOption) StandalonePresetJS() string {
 	return o.urlJoin("swagger-ui-standalone-preset.js")
 }
@@ -76,8 +88,8 @@ func (o generateOption) newUIIndexHTMLConfig() (UITemplateConfig, error) {
 		BundleJS:           o.BundleJS(),
 		StandalonePresetJS: o.StandalonePresetJS(),
 		URLs:               make([]*SwaggerFile, 0),
+		LiveReload:         o.LiveReload,
 	}
-	loads.AddLoader(fmts.YAMLMatcher, fmts.YAMLDoc)
 	for _, spec := range o.SpecFiles {
 		u, err := newSwaggerFile(spec)
 		if err != nil {
@@ -90,11 +102,11 @@ func (o generateOption) newUIIndexHTMLConfig() (UITemplateConfig, error) {
 
 func (o generateOption) newRedocUIIndexHTMLConfig() (UITemplateConfig, error) {
 	config := &RedocUIIndexConfig{
-		RedocURL: o.RedocURL,
-		URLs:     make([]*SwaggerFile, 0),
+		RedocURL:   o.redocURL(),
+		URLs:       make([]*SwaggerFile, 0),
+		LiveReload: o.LiveReload,
 	}
 	urls := make([]*SwaggerFile, 0)
-	loads.AddLoader(fmts.YAMLMatcher, fmts.YAMLDoc)
 	for _, spec := range o.SpecFiles {
 		u, err := newSwaggerFile(spec)
 		if err != nil {
@@ -136,6 +148,7 @@ func initGenerateCmdOpts(flagSet *flag.FlagSet, cfg *generateOption) {
 	flagSet.StringVar(&cfg.CDNPrefix, "cdn", "https://cdnjs.cloudflare.com/ajax/libs/swagger-ui", "swagger-ui cdn prefix")
 	flagSet.StringVar(&cfg.RedocURL, "redoc-url", "https://cdn.jsdelivr.net/npm/redoc/bundles/redoc.standalone.js", "redoc url")
 	flagSet.StringVar(&cfg.UIVersion, "ui-version", "3.23.11", "swagger ui version")
+	flagSet.BoolVar(&cfg.Offline, "offline", false, "copy the embedded UI bundles into output dir instead of using --cdn and --redoc-url")
 	flagSet.BoolVarP(&cfg.Serve, "serve", "s", false, "serve as http static server and open browser view site")
 	flagSet.BoolVar(&cfg.NoOpen, "no-open", false, "Not open UI in browser")
 	flagSet.StringVar(&cfg.ServeAddr, "serve-addr", "", "server listen address")
@@ -143,11 +156,10 @@ func initGenerateCmdOpts(flagSet *flag.FlagSet, cfg *generateOption) {
 }
 
 func newSwaggerFile(specPath string) (*SwaggerFile, error) {
-	spec, err := loads.Spec(specPath)
+	info, err := loadSpec(specPath)
 	if err != nil {
-		return nil, errors.Wrapf(err, "load swagger spec %s", specPath)
+		return nil, err
 	}
-	info := spec.Spec().Info
 	name := info.Title
 	if name == "" {
 		name = info.Description
@@ -173,12 +185,15 @@ type UITemplateConfig interface {
 	Generate() ([]byte, error)
 }
 
-func doGenerate(cfg *generateOption) error {
+func doGenerate(input *generateOption) error {
+	// copy option to keep input spec files for regenerating
+	cfg := *input
+	cfg.SpecFiles = make([]string, len(input.SpecFiles))
 	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
 		return err
 	}
-	for i := range cfg.SpecFiles {
-		srcPath := cfg.SpecFiles[i]
+	for i := range input.SpecFiles {
+		srcPath := input.SpecFiles[i]
 		dstPath := filepath.Join(cfg.OutputDir, filepath.Base(srcPath))
 		if err := cp(srcPath, dstPath); err != nil {
 			return errors.Wrapf(err, "copy %s to %s", srcPath, dstPath)
@@ -204,6 +219,11 @@ func doGenerate(cfg *generateOption) error {
 	if err != nil {
 		return err
 	}
+	if cfg.Offline {
+		if err := copyOfflineAssets(assetsFS, cfg.Flavor, cfg.OutputDir); err != nil {
+			return err
+		}
+	}
 	if err := ioutil.WriteFile(filepath.Join(cfg.OutputDir, "index.html"), index, 0644); err != nil {
 		return err
 	}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/loads/fmts"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"yunion.io/x/log"
+
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+// specInfo is the info of a spec file shown by the UI.
+type specInfo struct {
+	Title       string
+	Description string
+}
+
+// specVersion is the version key of a Swagger 2.0 or OpenAPI 3 document,
+// JSON documents are parsed as YAML too.
+type specVersion struct {
+	Swagger string `yaml:"swagger"`
+	OpenAPI string `yaml:"openapi"`
+}
+
+// loadSpec loads and validates spec file path. Swagger 2.0 documents are
+// loaded by go-openapi, OpenAPI 3.0 documents are checked by
+// openapi.Validate, and documents of other OpenAPI 3 versions are loaded
+// without validation, which is logged.
+func loadSpec(path string) (*specInfo, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	version := &specVersion{}
+	if err := yaml.Unmarshal(data, version); err != nil {
+		return nil, errors.Wrapf(err, "parse spec %s", path)
+	}
+	if version.OpenAPI == "" {
+		loads.AddLoader(fmts.YAMLMatcher, fmts.YAMLDoc)
+		spec, err := loads.Spec(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "load swagger spec %s", path)
+		}
+		info := spec.Spec().Info
+		if info == nil {
+			return &specInfo{}, nil
+		}
+		return &specInfo{Title: info.Title, Description: info.Description}, nil
+	}
+	if !strings.HasPrefix(version.OpenAPI, "3.") {
+		return nil, errors.Errorf("spec %s: unsupported openapi version %q", path, version.OpenAPI)
+	}
+	doc, err := openapi.Unmarshal(data, openapi.FormatOfFile(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "load openapi spec %s", path)
+	}
+	if openapi.IsVersion3_0(version.OpenAPI) {
+		if err := openapi.Validate(doc); err != nil {
+			return nil, errors.Wrapf(err, "invalid openapi spec %s", path)
+		}
+	} else {
+		log.Warningf("Openapi spec %s of version %s isn't validated, only 3.0 is supported", path, version.OpenAPI)
+	}
+	if doc.Info == nil {
+		return &specInfo{}, nil
+	}
+	return &specInfo{Title: doc.Info.Title, Description: doc.Info.Description}, nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSpec(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		name  string
+		spec  string
+		title string
+		err   string
+	}{
+		{
+			name:  "swagger.yaml",
+			spec:  "swagger: \"2.0\"\ninfo:\n  title: compute\n  version: v1\npaths: {}\n",
+			title: "compute",
+		},
+		{
+			name:  "openapi.yaml",
+			spec:  "openapi: 3.0.3\ninfo:\n  title: compute\n  version: v1\npaths:\n  /servers:\n    get:\n      responses:\n        \"200\":\n          description: ok\n",
+			title: "compute",
+		},
+		{
+			name: "invalid.json",
+			spec: `{"openapi": "3.0.3", "info": {"title": "compute", "version": "v1"}, "paths": {"/servers/{id}": {"get": {"responses": {"200": {"description": "ok"}}}}}}`,
+			err:  "GET /servers/{id}: path parameter id isn't declared",
+		},
+		{
+			name:  "openapi31.yaml",
+			spec:  "openapi: 3.1.0\ninfo:\n  title: image\n  version: v1\n",
+			title: "image",
+		},
+		{
+			name: "openapi4.yaml",
+			spec: "openapi: 4.0.0\n",
+			err:  `unsupported openapi version "4.0.0"`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(dir, c.name)
+			if err := ioutil.WriteFile(path, []byte(c.spec), 0644); err != nil {
+				t.Fatal(err)
+			}
+			info, err := loadSpec(path)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Errorf("error = %v, want %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Title != c.title {
+				t.Errorf("title = %q, want %q", info.Title, c.title)
+			}
+		})
+	}
+}
@@ -71,10 +71,18 @@ const (
       window.ui = ui
     }
   </script>
+  {{if .LiveReload}}` + liveReloadScript + `{{end}}
   </body>
 </html>
 `
 
+	// liveReloadScript reloads page when swagger-serve watch regenerates site
+	liveReloadScript = `<script>
+    new EventSource("` + reloadPath + `").onmessage = function() {
+      window.location.reload();
+    };
+  </script>`
+
 	RedocUIIndexTemplate = `<!DOCTYPE html>
 <html>
   <head>
@@ -188,6 +196,7 @@ const (
         $list.appendChild($listitem);
       });
     </script>
+    {{if .LiveReload}}` + liveReloadScript + `{{end}}
   </body>
 </html>
 `
@@ -221,6 +230,7 @@ type UIIndexHTMLConfig struct {
 	BundleJS           string
 	StandalonePresetJS string
 	URLs               []*SwaggerFile
+	LiveReload         bool
 }
 
 func (cfg UIIndexHTMLConfig) Generate() ([]byte, error) {
@@ -233,8 +243,9 @@ func (cfg UIIndexHTMLConfig) Generate() ([]byte, error) {
 }
 
 type RedocUIIndexConfig struct {
-	RedocURL string
-	URLs     []*SwaggerFile
+	RedocURL   string
+	URLs       []*SwaggerFile
+	LiveReload bool
 }
 
 func (cfg RedocUIIndexConfig) Generate() ([]byte, error) {
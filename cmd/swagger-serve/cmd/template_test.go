@@ -3,6 +3,7 @@ package cmd
 import (
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 )
 
@@ -28,3 +29,40 @@ func TestSwaggerFilesSort(t *testing.T) {
 		t.Errorf("sort names %v != %v", sortNames, expected)
 	}
 }
+
+func TestOfflineURLs(t *testing.T) {
+	o := generateOption{
+		CDNPrefix: "https://cdnjs.cloudflare.com/ajax/libs/swagger-ui",
+		RedocURL:  "https://cdn.jsdelivr.net/npm/redoc/bundles/redoc.standalone.js",
+		UIVersion: "3.23.11",
+	}
+	if got := o.BundleJS(); got != "https://cdnjs.cloudflare.com/ajax/libs/swagger-ui/3.23.11/swagger-ui-bundle.js" {
+		t.Errorf("online BundleJS() = %s", got)
+	}
+	o.Offline = true
+	if got := o.BundleJS(); got != "swagger-ui/swagger-ui-bundle.js" {
+		t.Errorf("offline BundleJS() = %s", got)
+	}
+	if got := o.redocURL(); got != "redoc/redoc.standalone.js" {
+		t.Errorf("offline redocURL() = %s", got)
+	}
+}
+
+func TestLiveReloadScript(t *testing.T) {
+	for _, cfg := range []UITemplateConfig{
+		UIIndexHTMLConfig{LiveReload: true},
+		RedocUIIndexConfig{LiveReload: true},
+	} {
+		out, err := cfg.Generate()
+		if err != nil {
+			t.Fatalf("generate %T: %v", cfg, err)
+		}
+		if !strings.Contains(string(out), `new EventSource("/_reload")`) {
+			t.Errorf("%T doesn't contain live reload script", cfg)
+		}
+	}
+	out, _ := RedocUIIndexConfig{}.Generate()
+	if strings.Contains(string(out), "EventSource") {
+		t.Errorf("live reload script is injected without LiveReload")
+	}
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"yunion.io/x/log"
+)
+
+const (
+	// reloadPath is the server-sent events endpoint of live reload
+	reloadPath = "/_reload"
+)
+
+type watchOption struct {
+	generateOption
+	Interval time.Duration
+}
+
+func newWatchCmd() *cobra.Command {
+	cfg := new(watchOption)
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "serve swagger static web site and regenerate it when input spec files change",
+		Run: func(_ *cobra.Command, _ []string) {
+			checkErr(doWatch(cfg))
+		},
+	}
+	initGenerateCmdOpts(cmd.PersistentFlags(), &cfg.generateOption)
+	cmd.PersistentFlags().DurationVar(&cfg.Interval, "interval", time.Second, "interval of polling input spec files")
+	return cmd
+}
+
+func doWatch(cfg *watchOption) error {
+	cfg.Serve = true
+	cfg.LiveReload = true
+	if err := doGenerate(&cfg.generateOption); err != nil {
+		return err
+	}
+	broker := newReloadBroker()
+	http.Handle(reloadPath, broker)
+
+	w := newSpecWatcher(cfg.SpecFiles)
+	go func() {
+		pending := false
+		for range time.Tick(cfg.Interval) {
+			// wait the files being stable for an interval, so half
+			// written files are not loaded
+			if w.changed() {
+				pending = true
+				continue
+			}
+			if !pending {
+				continue
+			}
+			pending = false
+			if err := validateSpecs(cfg.SpecFiles); err != nil {
+				log.Errorf("Skip regenerating: %v", err)
+				continue
+			}
+			if err := doGenerate(&cfg.generateOption); err != nil {
+				log.Errorf("Regenerate swagger ui site: %v", err)
+				continue
+			}
+			broker.notify()
+		}
+	}()
+	return serveHTTP(&cfg.generateOption)
+}
+
+// validateSpecs checks the spec files are loadable, see loadSpec.
+func validateSpecs(specFiles []string) error {
+	for _, spec := range specFiles {
+		if _, err := loadSpec(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type specFileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// specWatcher polls the spec files, inotify isn't used so that it works on
+// network file systems too.
+type specWatcher struct {
+	files  []string
+	states map[string]specFileState
+}
+
+func newSpecWatcher(files []string) *specWatcher {
+	w := &specWatcher{
+		files:  files,
+		states: make(map[string]specFileState),
+	}
+	w.changed()
+	return w
+}
+
+// changed returns true if any file is modified since last call.
+func (w *specWatcher) changed() bool {
+	changed := false
+	for _, f := range w.files {
+		var state specFileState
+		if fi, err := os.Stat(f); err == nil {
+			state = specFileState{modTime: fi.ModTime(), size: fi.Size()}
+		}
+		if w.states[f] != state {
+			w.states[f] = state
+			changed = true
+		}
+	}
+	return changed
+}
+
+// reloadBroker pushes reload events to the opened browsers.
+type reloadBroker struct {
+	lock    sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+func newReloadBroker() *reloadBroker {
+	return &reloadBroker{
+		clients: make(map[chan struct{}]struct{}),
+	}
+}
+
+func (b *reloadBroker) notify() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for ch := range b.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *reloadBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	ch := make(chan struct{}, 1)
+	b.lock.Lock()
+	b.clients[ch] = struct{}{}
+	b.lock.Unlock()
+	defer func() {
+		b.lock.Lock()
+		delete(b.clients, ch)
+		b.lock.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+	for {
+		select {
+		case <-ch:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"k8s.io/gengo/args"
+	"k8s.io/klog"
+
+	"yunion.io/x/code-generator/pkg/ts-gen/generators"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	arguments := args.Default()
+
+	// Override defaults.
+	arguments.GoHeaderFilePath = filepath.Join(args.DefaultSourceTree(), "yunion.io/x/code-generator/boilerplate/boilerplate.go.txt")
+	arguments.GeneratedByCommentTemplate = "// Code generated by GENERATOR_NAME. DO NOT EDIT."
+
+	if err := arguments.Execute(
+		generators.NameSystems(),
+		generators.DefaultNameSystem(),
+		generators.Packages,
+	); err != nil {
+		klog.Errorf("Error: %v", err)
+		os.Exit(1)
+	}
+	klog.V(2).Info("Completed successfully.")
+}
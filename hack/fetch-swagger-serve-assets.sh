@@ -0,0 +1,70 @@
+#!/bin/bash
+
+# Fetch the swagger-ui and redoc bundles pinned in
+# cmd/swagger-serve/cmd/assets/VERSIONS, they are embedded into swagger-serve
+# for `swagger-serve generate --offline`. `make swagger-serve` and
+# `go generate ./cmd/swagger-serve/...` run it, rebuild swagger-serve after
+# running it by hand.
+#
+# Every bundle must match its sha256 line in VERSIONS, a bundle failing the
+# check is not written. After bumping a version, run it with --pin to record
+# the sha256 of the new bundles into VERSIONS, review and commit them.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+ASSETS_DIR=$(dirname "${BASH_SOURCE[0]}")/../cmd/swagger-serve/cmd/assets
+VERSIONS="$ASSETS_DIR/VERSIONS"
+
+PIN=false
+if [[ "${1:-}" == "--pin" ]]; then
+    PIN=true
+fi
+
+sha256() {
+    if command -v sha256sum >/dev/null; then
+        sha256sum "$1" | awk '{ print $1 }'
+    else
+        shasum -a 256 "$1" | awk '{ print $1 }'
+    fi
+}
+
+SWAGGER_UI_VERSION=$(awk '$1 == "swagger-ui" { print $2 }' "$VERSIONS")
+REDOC_VERSION=$(awk '$1 == "redoc" { print $2 }' "$VERSIONS")
+
+TMP_DIR=$(mktemp -d)
+trap 'rm -rf "$TMP_DIR"' EXIT
+
+# fetch downloads url as asset, the path relative to assets dir, and checks
+# it against VERSIONS
+fetch() {
+    local asset=$1 url=$2
+    local tmp="$TMP_DIR/$asset"
+    mkdir -p "$(dirname "$tmp")"
+    curl -fsSL -o "$tmp" "$url"
+
+    local got want
+    got=$(sha256 "$tmp")
+    want=$(awk -v asset="$asset" '$1 == "sha256" && $2 == asset { print $3 }' "$VERSIONS")
+    if [[ "$PIN" == true ]]; then
+        awk -v asset="$asset" '!($1 == "sha256" && $2 == asset)' "$VERSIONS" > "$TMP_DIR/VERSIONS"
+        echo "sha256 $asset $got" >> "$TMP_DIR/VERSIONS"
+        cp "$TMP_DIR/VERSIONS" "$VERSIONS"
+    elif [[ -z "$want" ]]; then
+        echo "no sha256 of $asset in $VERSIONS, run $0 --pin to record it" >&2
+        exit 1
+    elif [[ "$got" != "$want" ]]; then
+        echo "sha256 of $asset from $url is $got, expect $want" >&2
+        exit 1
+    fi
+
+    mkdir -p "$(dirname "$ASSETS_DIR/$asset")"
+    mv "$tmp" "$ASSETS_DIR/$asset"
+}
+
+for f in swagger-ui.css swagger-ui-bundle.js swagger-ui-standalone-preset.js; do
+    fetch "swagger-ui/$f" "https://cdnjs.cloudflare.com/ajax/libs/swagger-ui/$SWAGGER_UI_VERSION/$f"
+done
+
+fetch "redoc/redoc.standalone.js" "https://cdn.jsdelivr.net/npm/redoc@$REDOC_VERSION/bundles/redoc.standalone.js"
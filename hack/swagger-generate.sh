@@ -9,3 +9,12 @@
     --input-dirs yunion.io/x/onecloud/pkg/keystone/tokens \
     --input-dirs yunion.io/x/onecloud/pkg/keystone/models \
     --output-package yunion.io/x/onecloud/pkg/generated/swagger/identity
+
+# one merged OpenAPI spec of identity service, conflicts of tokens and
+# models are reported
+./_output/bin/swagger-gen \
+    --input-dirs yunion.io/x/onecloud/pkg/keystone/tokens \
+    --input-dirs yunion.io/x/onecloud/pkg/keystone/models \
+    --output-package yunion.io/x/onecloud/pkg/generated/swagger/identity \
+    --spec-format yaml --merge \
+    --conflict-report _output/identity-conflicts.json
@@ -0,0 +1,90 @@
+package args
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/gengo/args"
+
+	"yunion.io/x/code-generator/pkg/api-diff/diff"
+)
+
+// CustomArgs is the arguments specific to api-diff.
+type CustomArgs struct {
+	// Old and New are either the GOPATH roots of two source trees, whose
+	// --input-dirs packages are compared, or two OpenAPI spec files
+	// generated by swagger-gen --spec-format.
+	Old string
+	New string
+	// Format is the report format, text, json or markdown
+	Format string
+	// PatternConfig is the YAML or JSON file enabling or disabling method
+	// patterns of swagger-gen when routes of source trees are compared.
+	PatternConfig string
+}
+
+// NewDefaults returns default arguments for api-diff.
+func NewDefaults() (*args.GeneratorArgs, *CustomArgs) {
+	genericArgs := args.Default().WithoutDefaultFlagParsing()
+	customArgs := &CustomArgs{
+		Format: diff.FormatText,
+	}
+	genericArgs.CustomArgs = customArgs
+	return genericArgs, customArgs
+}
+
+// AddFlags add the api-diff flags to the flag set.
+func (ca *CustomArgs) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&ca.Old, "old", ca.Old, "GOPATH root of the old source tree, or the old OpenAPI spec file")
+	fs.StringVar(&ca.New, "new", ca.New, "GOPATH root of the new source tree, or the new OpenAPI spec file")
+	fs.StringVar(&ca.Format, "format", ca.Format, fmt.Sprintf("Report format, %q, %q or %q", diff.FormatText, diff.FormatJSON, diff.FormatMarkdown))
+	fs.StringVar(&ca.PatternConfig, "pattern-config", ca.PatternConfig, "YAML or JSON file enabling or disabling method patterns of swagger-gen when comparing source trees")
+}
+
+// IsSpec returns true if two spec files are compared instead of source
+// trees.
+func (ca *CustomArgs) IsSpec() bool {
+	fi, err := os.Stat(ca.Old)
+	return err == nil && !fi.IsDir()
+}
+
+// Validate checks the given arguments.
+func Validate(genericArgs *args.GeneratorArgs) error {
+	customArgs, ok := genericArgs.CustomArgs.(*CustomArgs)
+	if !ok {
+		return fmt.Errorf("custom args type %T is not *CustomArgs", genericArgs.CustomArgs)
+	}
+	if customArgs.Old == "" || customArgs.New == "" {
+		return fmt.Errorf("both --old and --new must be set")
+	}
+	switch customArgs.Format {
+	case diff.FormatText, diff.FormatJSON, diff.FormatMarkdown:
+	default:
+		return fmt.Errorf("unsupported report format %q", customArgs.Format)
+	}
+	oldInfo, err := os.Stat(customArgs.Old)
+	if err != nil {
+		return err
+	}
+	newInfo, err := os.Stat(customArgs.New)
+	if err != nil {
+		return err
+	}
+	if oldInfo.IsDir() != newInfo.IsDir() {
+		return fmt.Errorf("--old and --new must be both source trees or both spec files")
+	}
+	if oldInfo.IsDir() && len(genericArgs.InputDirs) == 0 {
+		return fmt.Errorf("--input-dirs must be set to compare source trees")
+	}
+	return nil
+}
+
+// GetCustomArgs returns the CustomArgs of genericArgs, defaults are used if
+// the caller doesn't set it.
+func GetCustomArgs(genericArgs *args.GeneratorArgs) *CustomArgs {
+	if customArgs, ok := genericArgs.CustomArgs.(*CustomArgs); ok {
+		return customArgs
+	}
+	return &CustomArgs{Format: diff.FormatText}
+}
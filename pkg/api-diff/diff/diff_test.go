@@ -0,0 +1,181 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+func kinds(changes []Change) []string {
+	ret := make([]string, 0, len(changes))
+	for _, c := range changes {
+		ret = append(ret, string(c.Kind)+" "+c.Subject)
+	}
+	return ret
+}
+
+func TestDiffTypes(t *testing.T) {
+	pkg := "yunion.io/x/onecloud/pkg/apis/compute"
+	universe := func(members []types.Member, extra ...*types.Type) types.Universe {
+		p := &types.Package{
+			Path: pkg,
+			Types: map[string]*types.Type{
+				"ServerCreateInput": {
+					Name:    types.Name{Package: pkg, Name: "ServerCreateInput"},
+					Kind:    types.Struct,
+					Members: members,
+				},
+			},
+		}
+		for _, t := range extra {
+			p.Types[t.Name.Name] = t
+		}
+		return types.Universe{pkg: p}
+	}
+	status := &types.Type{Name: types.Name{Package: pkg, Name: "ServerStatus"}, Kind: types.Alias, Underlying: types.String}
+	old := universe([]types.Member{
+		{Name: "Name", Type: types.String, Tags: `json:"name"`},
+		{Name: "VcpuCount", Type: types.Int, Tags: `json:"vcpu_count"`},
+		{Name: "Hypervisor", Type: types.String},
+		{Name: "Secret", Type: types.String, Tags: `json:"-"`},
+	}, status)
+	new := universe([]types.Member{
+		{Name: "Name", Type: types.String, Tags: `json:"generate_name"`},
+		{Name: "VcpuCount", Type: types.Int64, Tags: `json:"vcpu_count"`},
+		{Name: "Zone", Type: types.String, Tags: `json:"zone"`},
+	})
+
+	got := kinds(DiffTypes(old, new, []string{pkg}))
+	want := []string{
+		"json-name-changed " + pkg + ".ServerCreateInput.Name",
+		"field-type-changed " + pkg + ".ServerCreateInput.VcpuCount",
+		"field-removed " + pkg + ".ServerCreateInput.Hypervisor",
+		"field-added " + pkg + ".ServerCreateInput.Zone",
+		"type-removed " + pkg + ".ServerStatus",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffTypes = %v, want %v", got, want)
+	}
+}
+
+func TestDiffDocuments(t *testing.T) {
+	newDoc := func() *openapi.Document {
+		doc := openapi.NewDocument(&openapi.Info{Title: "Compute API", Version: "1.0"})
+		doc.Components.Parameters["ListLimit"] = &openapi.Parameter{
+			Name: "limit", In: openapi.ParamInQuery, Schema: &openapi.Schema{Type: openapi.TypeInteger},
+		}
+		return doc
+	}
+	old := newDoc()
+	old.Paths["/servers"] = &openapi.PathItem{
+		Get: &openapi.Operation{
+			OperationID: "server_List",
+			Parameters: []*openapi.Parameter{
+				{Ref: openapi.ComponentParametersPrefix + "ListLimit"},
+				{Name: "host", In: openapi.ParamInQuery, Schema: &openapi.Schema{Type: openapi.TypeString}},
+			},
+		},
+		Post: &openapi.Operation{OperationID: "server_Create"},
+	}
+	old.Paths["/servers/{id}/vnc"] = &openapi.PathItem{Get: &openapi.Operation{OperationID: "server_GetDetailsVnc"}}
+	old.Components.Schemas["ServerDetails"] = &openapi.Schema{
+		AllOf: []*openapi.Schema{
+			openapi.RefSchema("SServer"),
+			openapi.ObjectSchema(map[string]*openapi.Schema{"host": {Type: openapi.TypeString}}),
+		},
+	}
+	old.Components.Schemas["SServer"] = openapi.ObjectSchema(map[string]*openapi.Schema{
+		"vcpu_count": {Type: openapi.TypeInteger, Format: "int64"},
+	})
+
+	new := newDoc()
+	new.Paths["/servers"] = &openapi.PathItem{
+		Get: &openapi.Operation{
+			OperationID: "server_List",
+			Parameters: []*openapi.Parameter{
+				{Ref: openapi.ComponentParametersPrefix + "ListLimit"},
+				{Name: "host", In: openapi.ParamInQuery, Required: true, Schema: &openapi.Schema{Type: openapi.TypeString}},
+				{Name: "zone", In: openapi.ParamInQuery, Schema: &openapi.Schema{Type: openapi.TypeString}},
+			},
+		},
+		Post: &openapi.Operation{OperationID: "server_CreateServer"},
+	}
+	new.Components.Schemas["ServerDetails"] = old.Components.Schemas["ServerDetails"]
+	new.Components.Schemas["SServer"] = openapi.ObjectSchema(map[string]*openapi.Schema{
+		"vcpu_count": {Type: openapi.TypeString},
+		"zone":       {Type: openapi.TypeString},
+	})
+
+	got := kinds(DiffDocuments(old, new))
+	want := []string{
+		"param-required GET /servers",
+		"param-added GET /servers",
+		"route-removed GET /servers/{id}/vnc",
+		"operation-id-changed POST /servers",
+		"field-type-changed SServer.vcpu_count",
+		"field-added SServer.zone",
+		"field-type-changed ServerDetails.vcpu_count",
+		"field-added ServerDetails.zone",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffDocuments = %v, want %v", got, want)
+	}
+}
+
+func TestReportWrite(t *testing.T) {
+	r := NewReport([]Change{
+		newChange(KindFieldAdded, "SServer.zone", "property %q is added", "zone"),
+		newChange(KindRouteRemoved, "GET /servers/{id}/vnc", "route server_GetDetailsVnc is removed"),
+	})
+	if r.Breaking() != 1 || !r.Changes[0].Breaking {
+		t.Fatalf("breaking changes aren't sorted first: %v", r.Changes)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := r.Write(buf, FormatText); err != nil {
+		t.Fatal(err)
+	}
+	wantText := `Breaking changes:
+  route-removed: GET /servers/{id}/vnc: route server_GetDetailsVnc is removed
+Non-breaking changes:
+  field-added: SServer.zone: property "zone" is added
+1 breaking change(s), 1 non-breaking change(s)
+`
+	if buf.String() != wantText {
+		t.Errorf("text report:\n%s\nwant:\n%s", buf, wantText)
+	}
+
+	buf.Reset()
+	if err := r.Write(buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	out := struct {
+		Breaking    int
+		NonBreaking int
+		Changes     []Change
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Breaking != 1 || out.NonBreaking != 1 || len(out.Changes) != 2 {
+		t.Errorf("json report: %s", buf)
+	}
+
+	buf.Reset()
+	if err := r.Write(buf, FormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "| route-removed | `GET /servers/{id}/vnc` | route server_GetDetailsVnc is removed |") {
+		t.Errorf("markdown report:\n%s", buf)
+	}
+
+	if err := r.Write(buf, "html"); err == nil {
+		t.Errorf("html format isn't rejected")
+	}
+}
@@ -0,0 +1,118 @@
+package diff
+
+import (
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"k8s.io/gengo/args"
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/code-generator/pkg/common"
+	swaggergen "yunion.io/x/code-generator/pkg/swagger-gen/generators"
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+// LoadUniverse parses the input packages of arguments from source tree
+// root as model-api-gen and swagger-gen do, root is a GOPATH whose src dir
+// holds the packages, e.g. a checkout of a revision. Packages are resolved
+// in GOPATH mode, GO111MODULE is off while parsing, otherwise go/build looks
+// them up in the module of the working directory and ignores root.
+func LoadUniverse(arguments *args.GeneratorArgs, root string) (types.Universe, error) {
+	gopath := build.Default.GOPATH
+	// the builder copies build.Default when it's created
+	build.Default.GOPATH = root
+	defer func() {
+		build.Default.GOPATH = gopath
+	}()
+	mode, modeSet := os.LookupEnv("GO111MODULE")
+	os.Setenv("GO111MODULE", "off")
+	defer func() {
+		if modeSet {
+			os.Setenv("GO111MODULE", mode)
+		} else {
+			os.Unsetenv("GO111MODULE")
+		}
+	}()
+	b, err := arguments.NewBuilder()
+	if err != nil {
+		return nil, err
+	}
+	return b.FindTypes()
+}
+
+// LoadDocument loads an OpenAPI spec file generated by swagger-gen.
+func LoadDocument(file string) (*openapi.Document, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.Unmarshal(data, openapi.FormatOfFile(file))
+}
+
+// CompareSpecs compares two OpenAPI spec files.
+func CompareSpecs(oldFile, newFile string) (*Report, error) {
+	old, err := LoadDocument(oldFile)
+	if err != nil {
+		return nil, err
+	}
+	new, err := LoadDocument(newFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewReport(DiffDocuments(old, new)), nil
+}
+
+// tree is a parsed source tree.
+type tree struct {
+	universe types.Universe
+	doc      *openapi.Document
+}
+
+func loadTree(arguments *args.GeneratorArgs, root string, pkgPaths func(types.Universe) []string, patternConf *swaggergen.PatternConfig) (*tree, error) {
+	u, err := LoadUniverse(arguments, root)
+	if err != nil {
+		return nil, err
+	}
+	// memoised model classification of the other tree must be dropped
+	common.DefaultModelDetector.SetUniverse(u)
+	meta := swaggergen.NewDefaultMetaConfig(filepath.Base(root))
+	return &tree{
+		universe: u,
+		doc:      swaggergen.BuildOpenAPIDocument(u, pkgPaths(u), meta, patternConf),
+	}, nil
+}
+
+// CompareTrees compares the exported types and swagger routes of the input
+// packages of arguments between the old and new source tree.
+func CompareTrees(arguments *args.GeneratorArgs, oldRoot, newRoot string, patternConf *swaggergen.PatternConfig) (*Report, error) {
+	inputs := func(u types.Universe) []string {
+		ret := make([]string, 0)
+		for path, pkg := range u {
+			if arguments.InputIncludes(pkg) {
+				ret = append(ret, path)
+			}
+		}
+		sort.Strings(ret)
+		return ret
+	}
+	old, err := loadTree(arguments, oldRoot, inputs, patternConf)
+	if err != nil {
+		return nil, err
+	}
+	new, err := loadTree(arguments, newRoot, inputs, patternConf)
+	if err != nil {
+		return nil, err
+	}
+	pkgPaths := inputs(old.universe)
+	for _, path := range inputs(new.universe) {
+		if old.universe[path] == nil {
+			pkgPaths = append(pkgPaths, path)
+		}
+	}
+	sort.Strings(pkgPaths)
+	// component schemas repeat the type changes, only routes are compared
+	return NewReport(DiffTypes(old.universe, new.universe, pkgPaths), DiffRoutes(old.doc, new.doc)), nil
+}
@@ -0,0 +1,84 @@
+package diff
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/gengo/args"
+
+	"yunion.io/x/code-generator/pkg/common/golden"
+	swaggergen "yunion.io/x/code-generator/pkg/swagger-gen/generators"
+)
+
+// copyTree copies the regular files under dir src into dir dst.
+func copyTree(t *testing.T, src, dst string) {
+	t.Helper()
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0755)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(dst, rel), data, 0644)
+	})
+	if err != nil {
+		t.Fatalf("copy %s: %v", src, err)
+	}
+}
+
+func TestCompareTrees(t *testing.T) {
+	oldRoot := golden.FixtureRoot()
+	newRoot := t.TempDir()
+	copyTree(t, oldRoot, newRoot)
+	file := filepath.Join(newRoot, "src", "yunion.io", "x", "onecloud", "pkg", "compute", "models", "servers.go")
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := strings.NewReplacer(
+		"Description string `json:\"description\"`", "Description string `json:\"desc\"`",
+		"func (server *SServer) GetDetailsVnc(", "func (server *SServer) getDetailsVnc(",
+	).Replace(string(data))
+	if err := ioutil.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	arguments := args.Default().WithoutDefaultFlagParsing()
+	arguments.InputDirs = []string{"yunion.io/x/onecloud/pkg/compute/models"}
+	report, err := CompareTrees(arguments, oldRoot, oldRoot, &swaggergen.PatternConfig{})
+	if err != nil {
+		t.Fatalf("CompareTrees of the same tree: %v", err)
+	}
+	if len(report.Changes) != 0 {
+		t.Errorf("changes of the same tree = %v", kinds(report.Changes))
+	}
+
+	report, err = CompareTrees(arguments, oldRoot, newRoot, &swaggergen.PatternConfig{})
+	if err != nil {
+		t.Fatalf("CompareTrees: %v", err)
+	}
+	got := strings.Join(kinds(report.Changes), "\n")
+	for _, want := range []string{
+		string(KindJSONNameChanged) + " yunion.io/x/onecloud/pkg/compute/models.ServerUpdateInput.Description",
+		string(KindRouteRemoved) + " GET /servers/{id}/vnc",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("changes = \n%s\nwant %s", got, want)
+		}
+	}
+	if report.Breaking() == 0 {
+		t.Errorf("no breaking changes")
+	}
+}
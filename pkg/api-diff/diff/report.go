@@ -0,0 +1,210 @@
+// Package diff compares the api types and routes of two revisions and
+// classifies the changes as breaking or not, so incompatible changes are
+// found before upgrading.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// Kind is the kind of a change.
+type Kind string
+
+const (
+	KindTypeRemoved       Kind = "type-removed"
+	KindTypeAdded         Kind = "type-added"
+	KindTypeChanged       Kind = "type-changed"
+	KindFieldRemoved      Kind = "field-removed"
+	KindFieldAdded        Kind = "field-added"
+	KindFieldTypeChanged  Kind = "field-type-changed"
+	KindJSONNameChanged   Kind = "json-name-changed"
+	KindFieldRequired     Kind = "field-required"
+	KindRouteRemoved      Kind = "route-removed"
+	KindRouteAdded        Kind = "route-added"
+	KindOperationRenamed  Kind = "operation-id-changed"
+	KindParamRemoved      Kind = "param-removed"
+	KindParamAdded        Kind = "param-added"
+	KindParamRequired     Kind = "param-required"
+	KindParamTypeChanged  Kind = "param-type-changed"
+	KindSchemaRemoved     Kind = "schema-removed"
+	KindSchemaAdded       Kind = "schema-added"
+	KindSchemaTypeChanged Kind = "schema-type-changed"
+)
+
+// breakingKinds are the kinds breaking the existing clients.
+var breakingKinds = map[Kind]bool{
+	KindTypeRemoved:       true,
+	KindTypeChanged:       true,
+	KindFieldRemoved:      true,
+	KindFieldTypeChanged:  true,
+	KindJSONNameChanged:   true,
+	KindFieldRequired:     true,
+	KindRouteRemoved:      true,
+	KindOperationRenamed:  true,
+	KindParamRemoved:      true,
+	KindParamRequired:     true,
+	KindParamTypeChanged:  true,
+	KindSchemaRemoved:     true,
+	KindSchemaTypeChanged: true,
+}
+
+// Change is an api change between the old and new revision.
+type Change struct {
+	Kind     Kind `json:"kind"`
+	Breaking bool `json:"breaking"`
+	// Subject is what's changed, e.g. the full name of a type or field, or
+	// the method and path of a route
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
+func newChange(kind Kind, subject string, format string, args ...interface{}) Change {
+	return Change{
+		Kind:     kind,
+		Breaking: breakingKinds[kind],
+		Subject:  subject,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s: %s", c.Kind, c.Subject, c.Message)
+}
+
+// Report is the changes sorted with breaking ones first.
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+// NewReport merges and sorts changes.
+func NewReport(changes ...[]Change) *Report {
+	r := &Report{
+		Changes: make([]Change, 0),
+	}
+	for _, cs := range changes {
+		r.Changes = append(r.Changes, cs...)
+	}
+	sort.SliceStable(r.Changes, func(i, j int) bool {
+		ci, cj := r.Changes[i], r.Changes[j]
+		if ci.Breaking != cj.Breaking {
+			return ci.Breaking
+		}
+		if ci.Subject != cj.Subject {
+			return ci.Subject < cj.Subject
+		}
+		return ci.Kind < cj.Kind
+	})
+	return r
+}
+
+// Breaking returns the count of breaking changes.
+func (r *Report) Breaking() int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Report) filter(breaking bool) []Change {
+	ret := make([]Change, 0)
+	for _, c := range r.Changes {
+		if c.Breaking == breaking {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// Write writes the report of format text, json or markdown.
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatText:
+		r.writeText(w)
+		return nil
+	case FormatJSON:
+		return r.writeJSON(w)
+	case FormatMarkdown:
+		r.writeMarkdown(w)
+		return nil
+	default:
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+}
+
+func (r *Report) summary() string {
+	return fmt.Sprintf("%d breaking change(s), %d non-breaking change(s)", r.Breaking(), len(r.Changes)-r.Breaking())
+}
+
+func (r *Report) writeText(w io.Writer) {
+	for _, group := range []struct {
+		title    string
+		breaking bool
+	}{
+		{"Breaking changes", true},
+		{"Non-breaking changes", false},
+	} {
+		changes := r.filter(group.breaking)
+		if len(changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s:\n", group.title)
+		for _, c := range changes {
+			fmt.Fprintf(w, "  %s\n", c)
+		}
+	}
+	fmt.Fprintf(w, "%s\n", r.summary())
+}
+
+func (r *Report) writeJSON(w io.Writer) error {
+	out := struct {
+		Breaking    int      `json:"breaking"`
+		NonBreaking int      `json:"nonBreaking"`
+		Changes     []Change `json:"changes"`
+	}{
+		Breaking:    r.Breaking(),
+		NonBreaking: len(r.Changes) - r.Breaking(),
+		Changes:     r.Changes,
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+func (r *Report) writeMarkdown(w io.Writer) {
+	fmt.Fprintf(w, "# API changes\n\n%s\n", r.summary())
+	for _, group := range []struct {
+		title    string
+		breaking bool
+	}{
+		{"Breaking changes", true},
+		{"Non-breaking changes", false},
+	} {
+		changes := r.filter(group.breaking)
+		if len(changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n## %s\n\n", group.title)
+		fmt.Fprintf(w, "| Kind | Subject | Change |\n| --- | --- | --- |\n")
+		for _, c := range changes {
+			fmt.Fprintf(w, "| %s | `%s` | %s |\n", c.Kind, markdownEscaper.Replace(c.Subject), markdownEscaper.Replace(c.Message))
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+// DiffDocuments compares the routes and component schemas of the old and
+// new OpenAPI document.
+func DiffDocuments(old, new *openapi.Document) []Change {
+	return append(DiffRoutes(old, new), DiffSchemas(old, new)...)
+}
+
+// route is an operation of a document keyed by method and path.
+type route struct {
+	doc *openapi.Document
+	op  *openapi.Operation
+}
+
+func documentRoutes(doc *openapi.Document) map[string]route {
+	ret := make(map[string]route)
+	for path, item := range doc.Paths {
+		if item == nil {
+			continue
+		}
+		for method, op := range item.Operations() {
+			ret[fmt.Sprintf("%s %s", strings.ToUpper(method), path)] = route{doc: doc, op: op}
+		}
+	}
+	return ret
+}
+
+func sortedRouteKeys(m map[string]route) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DiffRoutes compares the operations of the old and new document, they
+// are matched by http method and path.
+func DiffRoutes(old, new *openapi.Document) []Change {
+	changes := make([]Change, 0)
+	oldRoutes := documentRoutes(old)
+	newRoutes := documentRoutes(new)
+	for _, key := range sortedRouteKeys(oldRoutes) {
+		or := oldRoutes[key]
+		nr, ok := newRoutes[key]
+		if !ok {
+			changes = append(changes, newChange(KindRouteRemoved, key, "route %s is removed", or.op.OperationID))
+			continue
+		}
+		if or.op.OperationID != nr.op.OperationID {
+			changes = append(changes, newChange(KindOperationRenamed, key, "operationId is changed from %q to %q", or.op.OperationID, nr.op.OperationID))
+		}
+		changes = append(changes, diffParameters(key, or, nr)...)
+	}
+	for _, key := range sortedRouteKeys(newRoutes) {
+		if _, ok := oldRoutes[key]; !ok {
+			changes = append(changes, newChange(KindRouteAdded, key, "route %s is added", newRoutes[key].op.OperationID))
+		}
+	}
+	return changes
+}
+
+// parameters returns the parameters of route keyed by location and name,
+// references to component parameters are resolved.
+func (r route) parameters() (map[string]*openapi.Parameter, []string) {
+	ret := make(map[string]*openapi.Parameter)
+	keys := make([]string, 0)
+	for _, p := range r.op.Parameters {
+		if p.Ref != "" {
+			name := strings.TrimPrefix(p.Ref, openapi.ComponentParametersPrefix)
+			if r.doc.Components == nil || r.doc.Components.Parameters[name] == nil {
+				continue
+			}
+			p = r.doc.Components.Parameters[name]
+		}
+		key := fmt.Sprintf("%s %s", p.In, p.Name)
+		ret[key] = p
+		keys = append(keys, key)
+	}
+	return ret, keys
+}
+
+func diffParameters(subject string, or, nr route) []Change {
+	changes := make([]Change, 0)
+	oldParams, oldKeys := or.parameters()
+	newParams, newKeys := nr.parameters()
+	for _, key := range oldKeys {
+		op := oldParams[key]
+		np, ok := newParams[key]
+		if !ok {
+			changes = append(changes, newChange(KindParamRemoved, subject, "%s parameter %q is removed", op.In, op.Name))
+			continue
+		}
+		if !op.Required && np.Required {
+			changes = append(changes, newChange(KindParamRequired, subject, "%s parameter %q becomes required", np.In, np.Name))
+		}
+		if ot, nt := schemaType(op.Schema), schemaType(np.Schema); ot != nt {
+			changes = append(changes, newChange(KindParamTypeChanged, subject, "type of %s parameter %q is changed from %s to %s", np.In, np.Name, ot, nt))
+		}
+	}
+	for _, key := range newKeys {
+		if _, ok := oldParams[key]; ok {
+			continue
+		}
+		np := newParams[key]
+		if np.Required {
+			changes = append(changes, newChange(KindParamRequired, subject, "required %s parameter %q is added", np.In, np.Name))
+		} else {
+			changes = append(changes, newChange(KindParamAdded, subject, "optional %s parameter %q is added", np.In, np.Name))
+		}
+	}
+	return changes
+}
+
+// schemaType describes the type of schema s, e.g. string/int64, []Server
+// or map[string]string, component schemas are described by name.
+func schemaType(s *openapi.Schema) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Ref != "" {
+		return strings.TrimPrefix(s.Ref, openapi.ComponentSchemasPrefix)
+	}
+	if s.Type == "" && len(s.AllOf) == 1 {
+		// nullable reference
+		return schemaType(s.AllOf[0])
+	}
+	switch s.Type {
+	case "":
+		if len(s.AllOf) > 0 {
+			return "object"
+		}
+		return "any"
+	case openapi.TypeArray:
+		return "[]" + schemaType(s.Items)
+	case openapi.TypeObject:
+		if s.AdditionalProperties != nil {
+			return "map[string]" + schemaType(s.AdditionalProperties)
+		}
+		return s.Type
+	}
+	if s.Format != "" {
+		return s.Type + "/" + s.Format
+	}
+	return s.Type
+}
+
+// objectProperties flattens the properties and required names of object
+// schema s, members of allOf are merged.
+func objectProperties(doc *openapi.Document, s *openapi.Schema, visiting map[string]bool) (map[string]*openapi.Schema, map[string]bool) {
+	props := make(map[string]*openapi.Schema)
+	required := make(map[string]bool)
+	if s == nil {
+		return props, required
+	}
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, openapi.ComponentSchemasPrefix)
+		if visiting[name] || doc.Components == nil {
+			return props, required
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		return objectProperties(doc, doc.Components.Schemas[name], visiting)
+	}
+	for _, sub := range s.AllOf {
+		subProps, subRequired := objectProperties(doc, sub, visiting)
+		for k, v := range subProps {
+			props[k] = v
+		}
+		for k := range subRequired {
+			required[k] = true
+		}
+	}
+	for k, v := range s.Properties {
+		props[k] = v
+	}
+	for _, k := range s.Required {
+		required[k] = true
+	}
+	return props, required
+}
+
+func schemaNames(doc *openapi.Document) []string {
+	names := make([]string, 0)
+	if doc.Components == nil {
+		return names
+	}
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DiffSchemas compares the component schemas of the old and new document.
+// A renamed property is reported as removed and added since the schemas
+// don't tell go field names.
+func DiffSchemas(old, new *openapi.Document) []Change {
+	changes := make([]Change, 0)
+	newNames := make(map[string]bool)
+	for _, name := range schemaNames(new) {
+		newNames[name] = true
+	}
+	oldNames := make(map[string]bool)
+	for _, name := range schemaNames(old) {
+		oldNames[name] = true
+		if !newNames[name] {
+			changes = append(changes, newChange(KindSchemaRemoved, name, "schema is removed"))
+			continue
+		}
+		changes = append(changes, diffSchema(name, old, new)...)
+	}
+	for _, name := range schemaNames(new) {
+		if !oldNames[name] {
+			changes = append(changes, newChange(KindSchemaAdded, name, "schema is added"))
+		}
+	}
+	return changes
+}
+
+func diffSchema(name string, old, new *openapi.Document) []Change {
+	oldSchema, newSchema := old.Components.Schemas[name], new.Components.Schemas[name]
+	if !isObjectSchema(oldSchema) || !isObjectSchema(newSchema) {
+		if ot, nt := schemaType(oldSchema), schemaType(newSchema); ot != nt {
+			return []Change{newChange(KindSchemaTypeChanged, name, "type is changed from %s to %s", ot, nt)}
+		}
+		return nil
+	}
+	changes := make([]Change, 0)
+	oldProps, oldRequired := objectProperties(old, oldSchema, map[string]bool{name: true})
+	newProps, newRequired := objectProperties(new, newSchema, map[string]bool{name: true})
+	for _, prop := range sortedSchemaKeys(oldProps) {
+		subject := name + "." + prop
+		np, ok := newProps[prop]
+		if !ok {
+			changes = append(changes, newChange(KindFieldRemoved, subject, "property %q is removed", prop))
+			continue
+		}
+		if ot, nt := schemaType(oldProps[prop]), schemaType(np); ot != nt {
+			changes = append(changes, newChange(KindFieldTypeChanged, subject, "type is changed from %s to %s", ot, nt))
+		}
+		if !oldRequired[prop] && newRequired[prop] {
+			changes = append(changes, newChange(KindFieldRequired, subject, "property %q becomes required", prop))
+		}
+	}
+	for _, prop := range sortedSchemaKeys(newProps) {
+		if _, ok := oldProps[prop]; ok {
+			continue
+		}
+		subject := name + "." + prop
+		if newRequired[prop] {
+			changes = append(changes, newChange(KindFieldRequired, subject, "required property %q is added", prop))
+		} else {
+			changes = append(changes, newChange(KindFieldAdded, subject, "property %q is added", prop))
+		}
+	}
+	return changes
+}
+
+// isObjectSchema returns true if s is an object of properties, including
+// the composition of embedded structs by allOf.
+func isObjectSchema(s *openapi.Schema) bool {
+	if s == nil {
+		return false
+	}
+	if len(s.AllOf) > 0 || len(s.Properties) > 0 {
+		return true
+	}
+	return s.Type == openapi.TypeObject && s.AdditionalProperties == nil
+}
+
+func sortedSchemaKeys(m map[string]*openapi.Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,158 @@
+package diff
+
+import (
+	"reflect"
+	"sort"
+
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/pkg/util/reflectutils"
+
+	"yunion.io/x/code-generator/pkg/common"
+)
+
+// DiffTypes compares the exported types of packages pkgPaths between the
+// old and new universe. Struct fields are matched by go name, so renaming
+// the json name of a field is told from removing it.
+func DiffTypes(old, new types.Universe, pkgPaths []string) []Change {
+	changes := make([]Change, 0)
+	for _, path := range pkgPaths {
+		oldTypes := exportedTypes(old[path])
+		newTypes := exportedTypes(new[path])
+		for _, name := range sortedKeys(oldTypes) {
+			ot := oldTypes[name]
+			nt, ok := newTypes[name]
+			if !ok {
+				changes = append(changes, newChange(KindTypeRemoved, ot.String(), "type is removed"))
+				continue
+			}
+			changes = append(changes, diffType(ot, nt)...)
+		}
+		for _, name := range sortedKeys(newTypes) {
+			if _, ok := oldTypes[name]; !ok {
+				changes = append(changes, newChange(KindTypeAdded, newTypes[name].String(), "type is added"))
+			}
+		}
+	}
+	return changes
+}
+
+func exportedTypes(pkg *types.Package) map[string]*types.Type {
+	ret := make(map[string]*types.Type)
+	if pkg == nil {
+		return ret
+	}
+	for name, t := range pkg.Types {
+		if common.IsPrivateStruct(name) {
+			continue
+		}
+		ret[name] = t
+	}
+	return ret
+}
+
+func sortedKeys(m map[string]*types.Type) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func diffType(ot, nt *types.Type) []Change {
+	if ot.Kind != nt.Kind {
+		return []Change{newChange(KindTypeChanged, ot.String(), "kind is changed from %s to %s", ot.Kind, nt.Kind)}
+	}
+	switch ot.Kind {
+	case types.Struct:
+		return diffMembers(ot, nt)
+	case types.Alias:
+		if ot.Underlying.String() != nt.Underlying.String() {
+			return []Change{newChange(KindTypeChanged, ot.String(), "underlying type is changed from %s to %s", ot.Underlying, nt.Underlying)}
+		}
+	}
+	return nil
+}
+
+// jsonMember is a struct member marshalled by jsonutils.
+type jsonMember struct {
+	types.Member
+	// jsonName is empty if the embedded member is inlined
+	jsonName string
+}
+
+func jsonMembers(t *types.Type) (map[string]jsonMember, []string) {
+	ret := make(map[string]jsonMember)
+	names := make([]string, 0)
+	for _, m := range t.Members {
+		if common.IsPrivateStruct(m.Name) && !m.Embedded {
+			continue
+		}
+		info := reflectutils.ParseFieldJsonInfo(m.Name, reflect.StructTag(m.Tags))
+		if info.Ignore {
+			continue
+		}
+		jm := jsonMember{Member: m}
+		if !isInlineEmbedded(m) {
+			jm.jsonName = info.MarshalName()
+		}
+		ret[m.Name] = jm
+		names = append(names, m.Name)
+	}
+	return ret, names
+}
+
+func diffMembers(ot, nt *types.Type) []Change {
+	changes := make([]Change, 0)
+	oldMembers, oldNames := jsonMembers(ot)
+	newMembers, newNames := jsonMembers(nt)
+	for _, name := range oldNames {
+		om := oldMembers[name]
+		subject := ot.String() + "." + name
+		nm, ok := newMembers[name]
+		if !ok {
+			if om.jsonName == "" {
+				changes = append(changes, newChange(KindFieldRemoved, subject, "embedded %s is removed", om.Type))
+			} else {
+				changes = append(changes, newChange(KindFieldRemoved, subject, "field %q is removed", om.jsonName))
+			}
+			continue
+		}
+		if om.jsonName != nm.jsonName {
+			changes = append(changes, newChange(KindJSONNameChanged, subject, "json name is changed from %q to %q", om.jsonName, nm.jsonName))
+		}
+		if om.Type.String() != nm.Type.String() {
+			changes = append(changes, newChange(KindFieldTypeChanged, subject, "type is changed from %s to %s", om.Type, nm.Type))
+		}
+	}
+	for _, name := range newNames {
+		if _, ok := oldMembers[name]; ok {
+			continue
+		}
+		nm := newMembers[name]
+		subject := nt.String() + "." + name
+		if nm.jsonName == "" {
+			changes = append(changes, newChange(KindFieldAdded, subject, "embedded %s is added", nm.Type))
+		} else {
+			changes = append(changes, newChange(KindFieldAdded, subject, "field %q is added", nm.jsonName))
+		}
+	}
+	return changes
+}
+
+// isInlineEmbedded returns true if fields of the embedded struct member are
+// promoted to the parent json object.
+func isInlineEmbedded(m types.Member) bool {
+	if !m.Embedded {
+		return false
+	}
+	t := m.Type
+	if t.Kind == types.Pointer {
+		t = t.Elem
+	}
+	for t.Kind == types.Alias {
+		t = t.Underlying
+	}
+	return t.Kind == types.Struct && t.String() != "time.Time"
+}
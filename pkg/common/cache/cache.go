@@ -0,0 +1,439 @@
+// Package cache skips the generator runs of input packages whose inputs and
+// outputs are unchanged since the last run. Every input package has its own
+// entry, so an invocation over many packages only passes the stale ones to
+// gengo, and only the files the generator wrote are checked as outputs.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/build"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/pflag"
+	"k8s.io/gengo/generator"
+	"k8s.io/klog"
+)
+
+const (
+	forceFlag    = "force"
+	cacheDirFlag = "cache-dir"
+	// inputDirsFlag is the flag of gengo input packages, they are keyed by
+	// their own entries instead of the args
+	inputDirsFlag = "input-dirs"
+)
+
+// Options are the cache flags shared by the generators.
+type Options struct {
+	// CacheDir is the directory of cache entries, caching is disabled if
+	// empty.
+	CacheDir string
+	// Force regenerates even if the run is cached.
+	Force bool
+
+	// written records the files written by the run, see RegisterFileTypes
+	written *writtenFiles
+}
+
+// NewOptions returns the default options, caching is opt-in by setting
+// CacheDir.
+func NewOptions() Options {
+	return Options{written: &writtenFiles{}}
+}
+
+// DefaultDir returns yunion-code-generator of the user cache directory,
+// empty if it's unknown.
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "yunion-code-generator")
+}
+
+// AddFlags add the cache flags to the flag set.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.CacheDir, cacheDirFlag, o.CacheDir, fmt.Sprintf("Directory of the cache skipping runs whose inputs and outputs are unchanged, e.g. %q, disabled if empty", DefaultDir()))
+	fs.BoolVar(&o.Force, forceFlag, o.Force, "Generate even if inputs and outputs are unchanged since the cached run")
+}
+
+// Run describes the inputs and outputs of a generator run.
+type Run struct {
+	// Tool is the name of the generator
+	Tool string
+	// Args are the flags of the run, see FlagArgs
+	Args []string
+	// ConfigFiles are the files read by the run besides Go files, e.g.
+	// the boilerplate and mapping file
+	ConfigFiles []string
+	// InputDirs are the input packages, each one is cached by its own
+	// entry hashing its Go files and the Go files of the packages it
+	// imports outside GOROOT
+	InputDirs []string
+	// Together if true, the input packages are generated together, e.g.
+	// into a merged spec, and cached by one entry
+	Together bool
+	// OutputFiles are the files written by the run outside gengo, e.g. the
+	// diagnostics report
+	OutputFiles []string
+	// Context resolves the packages, build.Default is used if nil
+	Context *build.Context
+}
+
+// FlagArgs returns the flags set in fs except the cache flags and input
+// packages.
+func FlagArgs(fs *pflag.FlagSet) []string {
+	args := make([]string, 0)
+	fs.Visit(func(f *pflag.Flag) {
+		if f.Name == forceFlag || f.Name == cacheDirFlag || f.Name == inputDirsFlag {
+			return
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+	return args
+}
+
+// writtenFiles are the files written by a run.
+type writtenFiles struct {
+	lock  sync.Mutex
+	files []string
+}
+
+func (w *writtenFiles) add(file string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.files = append(w.files, file)
+}
+
+// take returns the recorded files and forgets them.
+func (w *writtenFiles) take() []string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	files := w.files
+	w.files = nil
+	return files
+}
+
+// recordFileType records the files assembled by FileType.
+type recordFileType struct {
+	generator.FileType
+	written *writtenFiles
+}
+
+func (ft recordFileType) AssembleFile(f *generator.File, pathname string) error {
+	if err := ft.FileType.AssembleFile(f, pathname); err != nil {
+		return err
+	}
+	ft.written.add(pathname)
+	return nil
+}
+
+// RegisterFileTypes wraps the registered file types of context to record the
+// files they write as the outputs of the run, it should be called after all
+// file types registered. It does nothing unless o is made by NewOptions.
+func (o Options) RegisterFileTypes(c *generator.Context) {
+	if o.written == nil {
+		return
+	}
+	for name, ft := range c.FileTypes {
+		c.FileTypes[name] = recordFileType{FileType: ft, written: o.written}
+	}
+}
+
+// entry is the cache file of an input package.
+type entry struct {
+	// Key is the hash of the inputs
+	Key string `json:"key"`
+	// Outputs are the hashes of the files written for it keyed by path
+	Outputs map[string]string `json:"outputs"`
+}
+
+// unit is the input packages cached by one entry.
+type unit struct {
+	inputDirs []string
+	// file is the path of entry without extension
+	file string
+	key  string
+	// outputs are the written files recorded by entry
+	outputs []string
+	fresh   bool
+}
+
+// Do calls generate with the input packages whose entries are missing or
+// stale, generate isn't called if all of them are fresh and not forced. The
+// entries are recorded after generate succeeds, runs of the same input
+// packages are serialized and errors of the cache itself only disable it.
+func (o Options) Do(r *Run, generate func(inputDirs []string) error) error {
+	if o.CacheDir == "" || o.written == nil {
+		return generate(r.InputDirs)
+	}
+	if err := os.MkdirAll(o.CacheDir, 0755); err != nil {
+		klog.Warningf("cache disabled: %v", err)
+		return generate(r.InputDirs)
+	}
+	units := r.units(o.CacheDir)
+	// locks are taken in order of entry files
+	files := make([]string, 0, len(units))
+	for _, u := range units {
+		files = append(files, u.file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		unlock, err := lockFile(file + ".lock")
+		if err != nil {
+			klog.Warningf("cache disabled: %v", err)
+			return generate(r.InputDirs)
+		}
+		defer unlock()
+	}
+
+	stale := make([]string, 0)
+	for _, u := range units {
+		key, err := r.key(u.inputDirs)
+		if err != nil {
+			klog.Warningf("cache disabled: %v", err)
+			return generate(r.InputDirs)
+		}
+		u.key = key
+		u.outputs, u.fresh = freshOutputs(u.file+".json", key)
+		if o.Force || !u.fresh {
+			u.fresh = false
+			stale = append(stale, u.inputDirs...)
+		}
+	}
+	if len(stale) == 0 {
+		klog.Infof("Inputs and outputs of %s are unchanged, skipped", r.Tool)
+		return nil
+	}
+	klog.V(2).Infof("Generating stale packages %v", stale)
+
+	o.written.take()
+	if err := generate(stale); err != nil {
+		return err
+	}
+	written := o.written.take()
+	for _, f := range r.OutputFiles {
+		if f != "" {
+			written = append(written, f)
+		}
+	}
+	for _, u := range units {
+		outputs := written
+		if u.fresh {
+			// the outputs shared with stale packages may be rewritten
+			outputs = u.outputs
+		}
+		if err := store(u.file+".json", u.key, outputs); err != nil {
+			klog.Warningf("write cache entry: %v", err)
+		}
+	}
+	return nil
+}
+
+// units returns the cache units of the run in dir in order of InputDirs.
+func (r *Run) units(dir string) []*unit {
+	groups := make([][]string, 0, len(r.InputDirs))
+	if r.Together {
+		groups = append(groups, r.InputDirs)
+	} else {
+		for _, d := range r.InputDirs {
+			groups = append(groups, []string{d})
+		}
+	}
+	units := make([]*unit, 0, len(groups))
+	for _, g := range groups {
+		units = append(units, &unit{inputDirs: g, file: filepath.Join(dir, r.id(g))})
+	}
+	return units
+}
+
+// id returns the name of cache entry of input packages inputDirs.
+func (r *Run) id(inputDirs []string) string {
+	h := sha256.New()
+	fmt.Fprintln(h, r.Tool)
+	for _, d := range inputDirs {
+		fmt.Fprintln(h, "input", d)
+	}
+	for _, a := range r.Args {
+		fmt.Fprintln(h, "arg", a)
+	}
+	return r.Tool + "-" + hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// key returns the hash of the generator binary, args, config files and Go
+// files of input packages inputDirs.
+func (r *Run) key(inputDirs []string) (string, error) {
+	h := sha256.New()
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if err := hashFile(h, "executable", exe); err != nil {
+		return "", err
+	}
+	for _, a := range r.Args {
+		fmt.Fprintln(h, "arg", a)
+	}
+	for _, f := range r.ConfigFiles {
+		if f == "" {
+			continue
+		}
+		if err := hashFile(h, "config "+f, f); err != nil {
+			return "", err
+		}
+	}
+	pkgs, err := r.packages(inputDirs)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range pkgs {
+		fmt.Fprintln(h, "package", p.ImportPath)
+		files := append(append([]string{}, p.GoFiles...), p.CgoFiles...)
+		sort.Strings(files)
+		for _, f := range files {
+			if err := hashFile(h, f, filepath.Join(p.Dir, f)); err != nil {
+				return "", err
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// packages returns input packages inputDirs and the packages they import
+// outside GOROOT, sorted by import path.
+func (r *Run) packages(inputDirs []string) ([]*build.Package, error) {
+	ctx := r.Context
+	if ctx == nil {
+		ctx = &build.Default
+	}
+	seen := make(map[string]*build.Package)
+	var walk func(path, srcDir string) error
+	walk = func(path, srcDir string) error {
+		if path == "C" {
+			return nil
+		}
+		p, err := ctx.Import(path, srcDir, 0)
+		if err != nil {
+			return fmt.Errorf("import %s: %v", path, err)
+		}
+		if p.Goroot || seen[p.ImportPath] != nil {
+			return nil
+		}
+		seen[p.ImportPath] = p
+		for _, i := range p.Imports {
+			if err := walk(i, p.Dir); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, d := range inputDirs {
+		if err := walk(d, ""); err != nil {
+			return nil, err
+		}
+	}
+	pkgs := make([]*build.Package, 0, len(seen))
+	for _, p := range seen {
+		pkgs = append(pkgs, p)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].ImportPath < pkgs[j].ImportPath })
+	return pkgs, nil
+}
+
+// hashOutputs returns the hashes of files keyed by path, missing ones are
+// left out.
+func hashOutputs(files []string) (map[string]string, error) {
+	ret := make(map[string]string)
+	for _, f := range files {
+		if _, err := os.Stat(f); os.IsNotExist(err) {
+			continue
+		}
+		h := sha256.New()
+		if err := hashFile(h, "", f); err != nil {
+			return nil, err
+		}
+		ret[f] = hex.EncodeToString(h.Sum(nil))
+	}
+	return ret, nil
+}
+
+// freshOutputs returns the outputs recorded by the entry file, and whether
+// it records key and the outputs are unchanged since.
+func freshOutputs(file, key string) ([]string, bool) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+	e := &entry{}
+	if err := json.Unmarshal(data, e); err != nil {
+		klog.Warningf("invalid cache entry %s: %v", file, err)
+		return nil, false
+	}
+	files := make([]string, 0, len(e.Outputs))
+	for f := range e.Outputs {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	if e.Key != key || len(e.Outputs) == 0 {
+		return files, false
+	}
+	outputs, err := hashOutputs(files)
+	if err != nil || len(outputs) != len(e.Outputs) {
+		return files, false
+	}
+	for f, h := range e.Outputs {
+		if outputs[f] != h {
+			klog.V(2).Infof("output %s changed", f)
+			return files, false
+		}
+	}
+	return files, true
+}
+
+// store writes the entry of key and the current hashes of outputs to file,
+// it's replaced atomically so readers never see a partial entry.
+func store(file, key string, outputs []string) error {
+	hashes, err := hashOutputs(outputs)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(&entry{Key: key, Outputs: hashes}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// hashFile writes name and the content of file to h.
+func hashFile(h io.Writer, name, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if name != "" {
+		fmt.Fprintln(h, "file", strings.TrimSpace(name))
+	}
+	_, err = io.Copy(h, f)
+	return err
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/gengo/generator"
+)
+
+func writeFile(t *testing.T, file, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDo(t *testing.T) {
+	// packages are resolved in GOPATH mode as gengo does
+	t.Setenv("GO111MODULE", "off")
+	gopath := t.TempDir()
+	src := filepath.Join(gopath, "src")
+	writeFile(t, filepath.Join(src, "example.com/svc/db/db.go"), "package db\n\ntype SModelBase struct{}\n")
+	writeFile(t, filepath.Join(src, "example.com/svc/models/models.go"), "package models\n\nimport \"example.com/svc/db\"\n\ntype SServer struct{ db.SModelBase }\n")
+	writeFile(t, filepath.Join(src, "example.com/svc/tokens/tokens.go"), "package tokens\n")
+	ctx := build.Default
+	ctx.GOPATH = gopath
+	outDir := filepath.Join(src, "example.com/svc/apis")
+	handWritten := filepath.Join(outDir, "doc.go")
+	writeFile(t, handWritten, "package apis\n")
+	report := filepath.Join(gopath, "diagnostics.json")
+
+	opts := NewOptions()
+	opts.CacheDir = t.TempDir()
+	run := &Run{
+		Tool:        "model-api-gen",
+		Args:        []string{"--output-package=example.com/svc/apis"},
+		InputDirs:   []string{"example.com/svc/models", "example.com/svc/tokens"},
+		OutputFiles: []string{report},
+		Context:     &ctx,
+	}
+	output := func(inputDir string) string {
+		return filepath.Join(outDir, "zz_generated."+filepath.Base(inputDir)+".go")
+	}
+	var generated []string
+	generate := func(inputDirs []string) error {
+		generated = inputDirs
+		for _, d := range inputDirs {
+			writeFile(t, output(d), "package apis\n")
+			opts.written.add(output(d))
+		}
+		writeFile(t, report, "{}\n")
+		return nil
+	}
+	all := []string{"example.com/svc/models", "example.com/svc/tokens"}
+	steps := []struct {
+		name   string
+		change func()
+		want   []string
+	}{
+		{name: "first run", want: all},
+		{name: "unchanged"},
+		{name: "input changed", change: func() {
+			writeFile(t, filepath.Join(src, "example.com/svc/tokens/tokens.go"), "package tokens // edited\n")
+		}, want: []string{"example.com/svc/tokens"}},
+		{name: "imported package changed", change: func() {
+			writeFile(t, filepath.Join(src, "example.com/svc/db/db.go"), "package db\n\ntype SModelBase struct{ Id string }\n")
+		}, want: []string{"example.com/svc/models"}},
+		{name: "output changed", change: func() {
+			writeFile(t, output("example.com/svc/models"), "package apis // edited\n")
+		}, want: []string{"example.com/svc/models"}},
+		{name: "hand-written file changed", change: func() {
+			writeFile(t, handWritten, "package apis // edited\n")
+		}},
+		{name: "output file removed", change: func() {
+			os.Remove(report)
+		}, want: all},
+		{name: "forced", change: func() {
+			opts.Force = true
+		}, want: all},
+		{name: "together", change: func() {
+			opts.Force = false
+			run.Together = true
+			opts.Do(run, generate)
+			writeFile(t, filepath.Join(src, "example.com/svc/tokens/tokens.go"), "package tokens\n")
+		}, want: all},
+	}
+	for _, s := range steps {
+		if s.change != nil {
+			s.change()
+		}
+		generated = nil
+		if err := opts.Do(run, generate); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		if !reflect.DeepEqual(generated, s.want) {
+			t.Errorf("%s: generated %v, want %v", s.name, generated, s.want)
+		}
+	}
+}
+
+type fakeFileType struct {
+	generator.DefaultFileType
+}
+
+func (fakeFileType) AssembleFile(f *generator.File, pathname string) error {
+	return nil
+}
+
+func TestRegisterFileTypes(t *testing.T) {
+	opts := NewOptions()
+	c := &generator.Context{FileTypes: map[string]generator.FileType{"go": fakeFileType{}}}
+	opts.RegisterFileTypes(c)
+	if err := c.FileTypes["go"].AssembleFile(&generator.File{}, "out/zz_generated.go"); err != nil {
+		t.Fatal(err)
+	}
+	if got := opts.written.take(); !reflect.DeepEqual(got, []string{"out/zz_generated.go"}) {
+		t.Errorf("written = %v", got)
+	}
+	// options not made by NewOptions record nothing
+	Options{}.RegisterFileTypes(c)
+}
@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package cache
+
+// lockFile doesn't lock on platforms without flock, the entries are still
+// replaced atomically.
+func lockFile(file string) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,192 @@
+// Package diagnostics collects the problems found while generating code, so
+// one odd field doesn't abort the whole run and all of them can be reported
+// at the end.
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+var severityLevels = map[Severity]int{
+	SeverityWarning: 1,
+	SeverityError:   2,
+}
+
+// ParseSeverity parses the value of --fail-on flag.
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(s)
+	if _, ok := severityLevels[sev]; !ok {
+		return "", fmt.Errorf("invalid severity %q, must be %s or %s", s, SeverityError, SeverityWarning)
+	}
+	return sev, nil
+}
+
+// Diagnostic is a problem of a type or a field of it.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Package  string   `json:"package"`
+	Type     string   `json:"type"`
+	Field    string   `json:"field,omitempty"`
+	Position string   `json:"position,omitempty"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) subject() string {
+	if d.Field == "" {
+		return d.Type
+	}
+	return d.Type + "." + d.Field
+}
+
+func (d Diagnostic) String() string {
+	s := fmt.Sprintf("%s: %s: %s", d.Severity, d.subject(), d.Message)
+	if d.Position != "" {
+		s = d.Position + ": " + s
+	}
+	return s
+}
+
+// Collector records diagnostics, it's safe for concurrent use and a nil
+// Collector only logs them.
+type Collector struct {
+	lock      sync.Mutex
+	positions *positions
+	items     []Diagnostic
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		positions: newPositions(),
+	}
+}
+
+// SetUniverse sets the universe used to resolve source positions.
+func (c *Collector) SetUniverse(u types.Universe) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.positions.universe = u
+}
+
+func (c *Collector) add(sev Severity, t *types.Type, field string, format string, args ...interface{}) {
+	d := Diagnostic{
+		Severity: sev,
+		Package:  t.Name.Package,
+		Type:     t.Name.Name,
+		Field:    field,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if c == nil {
+		if sev == SeverityError {
+			klog.Error(d.String())
+		} else {
+			klog.Warning(d.String())
+		}
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	d.Position = c.positions.lookup(d.Package, d.Type, d.Field)
+	klog.V(1).Info(d.String())
+	c.items = append(c.items, d)
+}
+
+// Errorf records an error of type t, field is empty if it's about the type
+// itself.
+func (c *Collector) Errorf(t *types.Type, field string, format string, args ...interface{}) {
+	c.add(SeverityError, t, field, format, args...)
+}
+
+// Warningf records a warning of type t, field is empty if it's about the
+// type itself.
+func (c *Collector) Warningf(t *types.Type, field string, format string, args ...interface{}) {
+	c.add(SeverityWarning, t, field, format, args...)
+}
+
+// Diagnostics returns the records sorted by package, type and field.
+func (c *Collector) Diagnostics() []Diagnostic {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ret := make([]Diagnostic, len(c.items))
+	copy(ret, c.items)
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].Package != ret[j].Package {
+			return ret[i].Package < ret[j].Package
+		}
+		if ret[i].Type != ret[j].Type {
+			return ret[i].Type < ret[j].Type
+		}
+		return ret[i].Field < ret[j].Field
+	})
+	return ret
+}
+
+func (c *Collector) count(sev Severity) int {
+	n := 0
+	for _, d := range c.Diagnostics() {
+		if d.Severity == sev {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns true if any diagnostic is as severe as failOn.
+func (c *Collector) Failed(failOn Severity) bool {
+	for _, d := range c.Diagnostics() {
+		if severityLevels[d.Severity] >= severityLevels[failOn] {
+			return true
+		}
+	}
+	return false
+}
+
+// Report writes the diagnostics grouped by package.
+func (c *Collector) Report(w io.Writer) {
+	items := c.Diagnostics()
+	if len(items) == 0 {
+		return
+	}
+	pkg := ""
+	for _, d := range items {
+		if d.Package != pkg {
+			pkg = d.Package
+			fmt.Fprintf(w, "%s:\n", pkg)
+		}
+		fmt.Fprintf(w, "  %s\n", d)
+	}
+	fmt.Fprintf(w, "%d error(s), %d warning(s)\n", c.count(SeverityError), c.count(SeverityWarning))
+}
+
+// WriteJSON writes the diagnostics as JSON array to file.
+func (c *Collector) WriteJSON(file string) error {
+	items := c.Diagnostics()
+	if items == nil {
+		items = []Diagnostic{}
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, append(data, '\n'), 0644)
+}
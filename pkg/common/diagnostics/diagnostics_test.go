@@ -0,0 +1,37 @@
+package diagnostics
+
+import (
+	"bytes"
+	"testing"
+
+	"k8s.io/gengo/types"
+)
+
+func TestCollector(t *testing.T) {
+	server := &types.Type{Name: types.Name{Package: "models", Name: "SServer"}}
+	disk := &types.Type{Name: types.Name{Package: "models", Name: "SDisk"}}
+
+	c := NewCollector()
+	if c.Failed(SeverityWarning) {
+		t.Errorf("empty collector should not fail")
+	}
+	c.Warningf(server, "Tags", "map element refers to source package")
+	if c.Failed(SeverityError) || !c.Failed(SeverityWarning) {
+		t.Errorf("warning should only fail on warning")
+	}
+	c.Errorf(disk, "", "unsupported type kind Chan")
+	if !c.Failed(SeverityError) {
+		t.Errorf("error should fail on error")
+	}
+
+	out := new(bytes.Buffer)
+	c.Report(out)
+	want := `models:
+  error: SDisk: unsupported type kind Chan
+  warning: SServer.Tags: map element refers to source package
+1 error(s), 1 warning(s)
+`
+	if out.String() != want {
+		t.Errorf("Report() = %q, want %q", out.String(), want)
+	}
+}
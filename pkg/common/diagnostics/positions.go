@@ -0,0 +1,111 @@
+package diagnostics
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+// positions resolves source positions of types and fields, gengo doesn't
+// keep them so the package sources are parsed again on demand.
+type positions struct {
+	universe types.Universe
+	// packages maps package path to positions keyed by Type or Type.Field
+	packages map[string]map[string]token.Position
+}
+
+func newPositions() *positions {
+	return &positions{
+		packages: make(map[string]map[string]token.Position),
+	}
+}
+
+func (p *positions) lookup(pkgPath, typeName, field string) string {
+	pos, ok := p.load(pkgPath)[positionKey(typeName, field)]
+	if !ok && field != "" {
+		pos, ok = p.load(pkgPath)[typeName]
+	}
+	if !ok {
+		return ""
+	}
+	return pos.String()
+}
+
+func positionKey(typeName, field string) string {
+	if field == "" {
+		return typeName
+	}
+	return typeName + "." + field
+}
+
+func (p *positions) load(pkgPath string) map[string]token.Position {
+	if ret, ok := p.packages[pkgPath]; ok {
+		return ret
+	}
+	ret := make(map[string]token.Position)
+	p.packages[pkgPath] = ret
+	pkg := p.universe[pkgPath]
+	if p.universe == nil || pkg == nil || pkg.SourcePath == "" {
+		return ret
+	}
+	fset := token.NewFileSet()
+	filter := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(fset, pkg.SourcePath, filter, 0)
+	if err != nil {
+		klog.Warningf("parse %s for positions: %v", pkg.SourcePath, err)
+		return ret
+	}
+	for _, astPkg := range pkgs {
+		for _, f := range astPkg.Files {
+			for _, decl := range f.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					ret[ts.Name.Name] = fset.Position(ts.Pos())
+					st, ok := ts.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					for _, field := range st.Fields.List {
+						for _, name := range fieldNames(field) {
+							ret[positionKey(ts.Name.Name, name)] = fset.Position(field.Pos())
+						}
+					}
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// fieldNames returns names of field, the type name is used for embedded one.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, len(field.Names))
+		for i, n := range field.Names {
+			names[i] = n.Name
+		}
+		return names
+	}
+	t := field.Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch x := t.(type) {
+	case *ast.Ident:
+		return []string{x.Name}
+	case *ast.SelectorExpr:
+		return []string{x.Sel.Name}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package common
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"strings"
+
+	"k8s.io/gengo/types"
+)
+
+const (
+	// TagFieldOptions shapes a field of the apis types, the values are
+	// skip, include, rename:<Name>, type:<go type> and omitempty. Put on a
+	// type, type:<go type> and omitempty apply to all fields of the type.
+	TagFieldOptions = "onecloud:model-api-gen-field"
+
+	fieldOptSkip      = "skip"
+	fieldOptInclude   = "include"
+	fieldOptRename    = "rename:"
+	fieldOptType      = "type:"
+	fieldOptOmitEmpty = "omitempty"
+)
+
+// FieldOptions are the values of +onecloud:model-api-gen-field tags of a
+// field or type.
+type FieldOptions struct {
+	// Skip drops the field
+	Skip bool
+	// Include keeps the struct field of model without get or list tag
+	Include bool
+	// Rename is the go name of the field in apis type, the json name is
+	// kept
+	Rename string
+	// Type is the go type of the field in apis type, see ParseGoType
+	Type      string
+	OmitEmpty bool
+}
+
+// ParseFieldOptions parses the field tags of comment lines, errors of
+// invalid values are returned with the options of valid ones.
+func ParseFieldOptions(comments []string) (*FieldOptions, []error) {
+	opts := &FieldOptions{}
+	errs := make([]error, 0)
+	for _, val := range types.ExtractCommentTags("+", comments)[TagFieldOptions] {
+		val = strings.TrimSpace(val)
+		switch {
+		case val == fieldOptSkip:
+			opts.Skip = true
+		case val == fieldOptInclude:
+			opts.Include = true
+		case val == fieldOptOmitEmpty:
+			opts.OmitEmpty = true
+		case strings.HasPrefix(val, fieldOptRename):
+			name := strings.TrimSpace(strings.TrimPrefix(val, fieldOptRename))
+			if !token.IsIdentifier(name) || !token.IsExported(name) {
+				errs = append(errs, fmt.Errorf("+%s=%s: %q isn't an exported go identifier", TagFieldOptions, val, name))
+				continue
+			}
+			opts.Rename = name
+		case strings.HasPrefix(val, fieldOptType):
+			typ := strings.TrimSpace(strings.TrimPrefix(val, fieldOptType))
+			if _, err := ParseGoType(typ); err != nil {
+				errs = append(errs, fmt.Errorf("+%s=%s: %v", TagFieldOptions, val, err))
+				continue
+			}
+			opts.Type = typ
+		default:
+			errs = append(errs, fmt.Errorf("+%s=%s: unknown value, expect skip, include, rename:<Name>, type:<type> or omitempty", TagFieldOptions, val))
+		}
+	}
+	if opts.Skip && (opts.Include || opts.Rename != "" || opts.Type != "" || opts.OmitEmpty) {
+		errs = append(errs, fmt.Errorf("+%s=%s can't be used with other values", TagFieldOptions, fieldOptSkip))
+	}
+	return opts, errs
+}
+
+// GetFieldOptions returns the field options of comment lines, errors are
+// dropped as model-api-gen reports them.
+func GetFieldOptions(comments []string) *FieldOptions {
+	opts, _ := ParseFieldOptions(comments)
+	return opts
+}
+
+// ParseGoType parses the go type expression typ of a type option, e.g.
+// *bool or map[string][]int64. Only predeclared types and the composite
+// types of them are accepted, the expression is written to the apis package
+// as is, so a package qualified type can't be imported there.
+func ParseGoType(typ string) (*types.Type, error) {
+	if typ == "" {
+		return nil, fmt.Errorf("empty type")
+	}
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %q: %v", typ, err)
+	}
+	return goType(typ, expr)
+}
+
+func goType(typ string, expr ast.Expr) (*types.Type, error) {
+	name := types.Name{Name: gotypes.ExprString(expr)}
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if obj, ok := gotypes.Universe.Lookup(e.Name).(*gotypes.TypeName); ok {
+			if _, basic := obj.Type().(*gotypes.Basic); basic {
+				return &types.Type{Name: name, Kind: types.Builtin}, nil
+			}
+			if e.Name == "any" {
+				return &types.Type{Name: name, Kind: types.Interface}, nil
+			}
+		}
+		return nil, fmt.Errorf("type %q: %s isn't a predeclared type", typ, e.Name)
+	case *ast.SelectorExpr:
+		return nil, fmt.Errorf("type %q: package qualified type %s isn't supported", typ, name.Name)
+	case *ast.ParenExpr:
+		return goType(typ, e.X)
+	case *ast.StarExpr:
+		elem, err := goType(typ, e.X)
+		if err != nil {
+			return nil, err
+		}
+		return &types.Type{Name: name, Kind: types.Pointer, Elem: elem}, nil
+	case *ast.ArrayType:
+		elem, err := goType(typ, e.Elt)
+		if err != nil {
+			return nil, err
+		}
+		if e.Len == nil {
+			return &types.Type{Name: name, Kind: types.Slice, Elem: elem}, nil
+		}
+		if _, ok := e.Len.(*ast.BasicLit); !ok {
+			return nil, fmt.Errorf("type %q: array length must be a literal", typ)
+		}
+		return &types.Type{Name: name, Kind: types.Array, Elem: elem}, nil
+	case *ast.MapType:
+		key, err := goType(typ, e.Key)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := goType(typ, e.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &types.Type{Name: name, Kind: types.Map, Key: key, Elem: elem}, nil
+	case *ast.InterfaceType:
+		if e.Methods != nil && len(e.Methods.List) > 0 {
+			return nil, fmt.Errorf("type %q: only the empty interface is supported", typ)
+		}
+		return &types.Type{Name: name, Kind: types.Interface}, nil
+	}
+	return nil, fmt.Errorf("type %q isn't supported", typ)
+}
+
+// MemberType returns the type of member m in the apis types: the type of
+// its type option, or the one of the type option of its named type, or the
+// type of m otherwise.
+func MemberType(m types.Member) *types.Type {
+	if t := optionType(m.CommentLines); t != nil {
+		return t
+	}
+	if !m.Embedded && m.Type.Name.Package != "" && (m.Type.Kind == types.Alias || m.Type.Kind == types.Struct) {
+		if t := optionType(m.Type.CommentLines); t != nil {
+			return t
+		}
+	}
+	return m.Type
+}
+
+func optionType(comments []string) *types.Type {
+	opts := GetFieldOptions(comments)
+	if opts.Type == "" {
+		return nil
+	}
+	t, _ := ParseGoType(opts.Type)
+	return t
+}
@@ -0,0 +1,59 @@
+package common
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFieldOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments []string
+		want     FieldOptions
+		errors   int
+	}{
+		{
+			name: "rename, type and omitempty",
+			comments: []string{
+				"Id of the backup host",
+				"+onecloud:model-api-gen-field=rename:BackupHostID",
+				"+onecloud:model-api-gen-field=type:*bool",
+				"+onecloud:model-api-gen-field=omitempty",
+			},
+			want: FieldOptions{Rename: "BackupHostID", Type: "*bool", OmitEmpty: true},
+		},
+		{
+			name:     "skip",
+			comments: []string{"+onecloud:model-api-gen-field=skip"},
+			want:     FieldOptions{Skip: true},
+		},
+		{
+			name:     "skip with other values",
+			comments: []string{"+onecloud:model-api-gen-field=skip", "+onecloud:model-api-gen-field=include"},
+			want:     FieldOptions{Skip: true, Include: true},
+			errors:   1,
+		},
+		{
+			name:     "invalid values",
+			comments: []string{"+onecloud:model-api-gen-field=rename:hostId", "+onecloud:model-api-gen-field=type:", "+onecloud:model-api-gen-field=hide"},
+			errors:   3,
+		},
+		{
+			name:     "qualified or unknown types",
+			comments: []string{"+onecloud:model-api-gen-field=type:time.Time", "+onecloud:model-api-gen-field=type:[]SDisk", "+onecloud:model-api-gen-field=type:map[string]*int64"},
+			want:     FieldOptions{Type: "map[string]*int64"},
+			errors:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := ParseFieldOptions(tt.comments)
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ParseFieldOptions() = %+v, want %+v", *got, tt.want)
+			}
+			if len(errs) != tt.errors {
+				t.Errorf("errors = %v, want %d errors", errs, tt.errors)
+			}
+		})
+	}
+}
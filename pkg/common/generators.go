@@ -26,6 +26,15 @@ import (
 	return false
 }*/
 
+var (
+	// ModelBaseType is the full name of the base type of all resource
+	// models, the default ModelBase of ModelDetector
+	ModelBaseType = "yunion.io/x/onecloud/pkg/cloudcommon/db.SModelBase"
+	// SkipModelPackages are the packages whose types never make resource
+	// models, e.g. quotas, the default SkipPackages of ModelDetector
+	SkipModelPackages = sets.NewString("yunion.io/x/onecloud/pkg/cloudcommon/db/quotas")
+)
+
 func IsJSONObject(t *types.Type) bool {
 	return strings.Contains(t.String(), "yunion.io/x/jsonutils")
 }
@@ -34,39 +43,9 @@ func IsPrivateStruct(name string) bool {
 	return unicode.IsLower([]rune(name)[0])
 }
 
+// IsResourceModel returns true if t is a resource model, see ModelDetector.
 func IsResourceModel(t *types.Type) bool {
-	// ignore SModelBase itself
-	if t.Name.Package == "yunion.io/x/onecloud/pkg/cloudcommon/db" && t.Name.Name == "SModelBase" {
-		return false
-	}
-	return isResourceModelInternal(t)
-}
-
-func isResourceModelInternal(t *types.Type) bool {
-	/*endWithResBase := EndWithResourceBase(t)
-	log.Debugf("%s isCommonDBPkg: %v endWithResBase: %v", t.Name, isCommonDBPkg, endWithResBase)
-	if isCommonDBPkg && endWithResBase {
-		return true
-	} else if endWithResBase {
-		// service models pkg not generate cloudcommon/db models
-		return false
-	}*/
-
-	// ignore quotas
-	if t.Name.Package == "yunion.io/x/onecloud/pkg/cloudcommon/db/quotas" {
-		return false
-	}
-
-	if t.Name.Package == "yunion.io/x/onecloud/pkg/cloudcommon/db" && t.Name.Name == "SModelBase" {
-		return true
-	}
-
-	for _, m := range t.Members {
-		if isResourceModelInternal(m.Type) {
-			return true
-		}
-	}
-	return false
+	return DefaultModelDetector.Classify(t).IsModel
 }
 
 func InSourcePackage(t *types.Type, srcPkg string) bool {
@@ -77,34 +56,10 @@ func IsSamePackage(t *types.Type, pkgPath string) bool {
 	return t.Name.Package == pkgPath
 }
 
+// CollectModelManager collects the models and managers of srcPkg by
+// DefaultModelDetector, see ModelDetector.CollectModelManager.
 func CollectModelManager(srcPkg string, pkgTypes []*types.Type, modelTypes sets.String, modelManagers map[string]*types.Type) {
-	restTypes := make([]*types.Type, 0)
-	for _, t := range pkgTypes {
-		if t.Kind != types.Struct {
-			continue
-		}
-		if !InSourcePackage(t, srcPkg) {
-			continue
-		}
-
-		if IsPrivateStruct(t.Name.Name) {
-			continue
-		}
-
-		if IsResourceModel(t) {
-			modelTypes.Insert(t.String())
-		} else {
-			restTypes = append(restTypes, t)
-		}
-	}
-	for _, t := range restTypes {
-		if strings.HasSuffix(t.Name.Name, "Manager") {
-			modelName := strings.TrimSuffix(t.String(), "Manager")
-			if modelTypes.Has(modelName) {
-				modelManagers[modelName] = t
-			}
-		}
-	}
+	DefaultModelDetector.CollectModelManager(srcPkg, pkgTypes, modelTypes, modelManagers)
 }
 
 func GetArgs(t *types.Type) interface{} {
@@ -0,0 +1,138 @@
+// Package golden runs the generators over the fixture source tree in
+// testdata/ of the repository and compares the generated files with golden
+// files, so regressions of emitted code are caught without a onecloud
+// checkout. Run the tests with -update to rewrite the golden files.
+package golden
+
+import (
+	"flag"
+	"go/build"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"k8s.io/gengo/args"
+	"k8s.io/gengo/generator"
+	"k8s.io/gengo/namer"
+
+	"yunion.io/x/code-generator/pkg/common"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the generated files")
+
+// repoRoot returns the root of code-generator repository.
+func repoRoot() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..")
+}
+
+// FixtureRoot returns the GOPATH root of the fixture source tree, it holds
+// trimmed yunion.io/x/onecloud packages.
+func FixtureRoot() string {
+	return filepath.Join(repoRoot(), "testdata")
+}
+
+// Run executes the generator packages of arguments over the fixture source
+// tree and returns the dir generated files are written to.
+func Run(t *testing.T, arguments *args.GeneratorArgs, nameSystems namer.NameSystems, defaultSystem string, pkgs func(*generator.Context, *args.GeneratorArgs) generator.Packages) string {
+	t.Helper()
+	t.Setenv("GO111MODULE", "off")
+	gopath := build.Default.GOPATH
+	// the builder copies build.Default when it's created
+	build.Default.GOPATH = FixtureRoot()
+	defer func() {
+		build.Default.GOPATH = gopath
+	}()
+
+	arguments.OutputBase = t.TempDir()
+	arguments.GoHeaderFilePath = filepath.Join(repoRoot(), "boilerplate", "boilerplate.go.txt")
+	arguments.GeneratedByCommentTemplate = "// Code generated by GENERATOR_NAME. DO NOT EDIT."
+	if err := arguments.Execute(nameSystems, defaultSystem, pkgs); err != nil {
+		t.Fatalf("execute generator: %v", err)
+	}
+	return arguments.OutputBase
+}
+
+// listFiles returns the relative paths of regular files under dir.
+func listFiles(dir string) ([]string, error) {
+	files := make([]string, 0)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return files, nil
+	}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, rel)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// Compare compares the files under dir with the golden files under
+// goldenDir, a unified diff is reported for each stale golden file. With
+// -update goldenDir is replaced by the files of dir.
+func Compare(t *testing.T, dir, goldenDir string) {
+	t.Helper()
+	files, err := listFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.RemoveAll(goldenDir); err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range files {
+			data, err := ioutil.ReadFile(filepath.Join(dir, f))
+			if err != nil {
+				t.Fatal(err)
+			}
+			dst := filepath.Join(goldenDir, f)
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(dst, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+
+	goldenFiles, err := listFiles(goldenDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := make(map[string]bool)
+	for _, f := range files {
+		generated[f] = true
+		got, err := ioutil.ReadFile(filepath.Join(dir, f))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := ioutil.ReadFile(filepath.Join(goldenDir, f))
+		if os.IsNotExist(err) {
+			t.Errorf("%s is generated but has no golden file, run the test with -update", f)
+			continue
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if diff := common.UnifiedDiff(filepath.Join(goldenDir, f), f, want, got); diff != "" {
+			t.Errorf("%s differs from golden file, run the test with -update if it's expected:\n%s", f, diff)
+		}
+	}
+	for _, f := range goldenFiles {
+		if !generated[f] {
+			t.Errorf("golden file %s is not generated any more, run the test with -update", f)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+
+	"yunion.io/x/pkg/util/sets"
+)
+
+// ModelClassification is the result of resource model detection of a type.
+type ModelClassification struct {
+	Type    string
+	IsModel bool
+	// Reason tells why the type is or isn't a model
+	Reason string
+}
+
+func (c *ModelClassification) String() string {
+	verdict := "not model"
+	if c.IsModel {
+		verdict = "model"
+	}
+	return fmt.Sprintf("%s: %s, %s", c.Type, verdict, c.Reason)
+}
+
+// ModelDetector classifies resource models and their managers. A struct is
+// a model if it embeds the model base type, directly or by pointer through
+// any depth, or if its method set covers the model interface. The results
+// are memoised so embedding chains are walked once.
+type ModelDetector struct {
+	lock sync.Mutex
+
+	universe types.Universe
+	// ModelBase is the full name of the base type of all resource models
+	ModelBase string
+	// SkipPackages are the packages whose types never make resource models
+	SkipPackages sets.String
+	// ModelInterface and ManagerInterface are the full names of interfaces
+	// models and managers implement, e.g. yunion.io/x/onecloud/pkg/cloudcommon/db.IModel.
+	// Method sets are not checked if empty.
+	ModelInterface   string
+	ManagerInterface string
+
+	models     map[string]*ModelClassification
+	methodSets map[string]sets.String
+}
+
+// NewModelDetector returns a detector using ModelBaseType and
+// SkipModelPackages.
+func NewModelDetector() *ModelDetector {
+	return &ModelDetector{
+		ModelBase:    ModelBaseType,
+		SkipPackages: SkipModelPackages,
+		models:       make(map[string]*ModelClassification),
+		methodSets:   make(map[string]sets.String),
+	}
+}
+
+// DefaultModelDetector is used by IsResourceModel and CollectModelManager.
+var DefaultModelDetector = NewModelDetector()
+
+// SetUniverse sets the universe interfaces are looked up from.
+func (d *ModelDetector) SetUniverse(u types.Universe) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.universe = u
+	d.reset()
+}
+
+// SetInterfaces sets the interfaces of models and managers.
+func (d *ModelDetector) SetInterfaces(model, manager string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.ModelInterface = model
+	d.ManagerInterface = manager
+	d.reset()
+}
+
+// Reset drops the memoised results, it must be called after ModelBase or
+// SkipPackages changes.
+func (d *ModelDetector) Reset() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.reset()
+}
+
+func (d *ModelDetector) reset() {
+	d.models = make(map[string]*ModelClassification)
+	d.methodSets = make(map[string]sets.String)
+}
+
+// Classify returns whether t is a resource model and why.
+func (d *ModelDetector) Classify(t *types.Type) *ModelClassification {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if t.String() == d.ModelBase {
+		return &ModelClassification{Type: t.String(), Reason: "it's the model base " + d.ModelBase}
+	}
+	c := d.classify(t)
+	if c.IsModel {
+		return c
+	}
+	if d.ModelInterface != "" && t.Kind == types.Struct {
+		ok, reason := d.implements(t, d.ModelInterface)
+		if ok {
+			return &ModelClassification{Type: t.String(), IsModel: true, Reason: reason}
+		}
+		return &ModelClassification{Type: t.String(), Reason: c.Reason + ", " + reason}
+	}
+	return c
+}
+
+// classify walks members of t for the model base, embedded pointers are
+// followed too.
+func (d *ModelDetector) classify(t *types.Type) *ModelClassification {
+	c, _ := d.walk(t, sets.NewString())
+	return c
+}
+
+// walk classifies t, the result isn't memoised if it's negative and depends
+// on a type in the visiting embedding chain, e.g. A embeds *B embeds *A.
+func (d *ModelDetector) walk(t *types.Type, visiting sets.String) (*ModelClassification, bool) {
+	key := t.String()
+	if c, ok := d.models[key]; ok {
+		return c, true
+	}
+	if visiting.Has(key) {
+		return &ModelClassification{Type: key, Reason: "recursive embedding"}, false
+	}
+	visiting.Insert(key)
+	defer visiting.Delete(key)
+
+	c := &ModelClassification{Type: key}
+	complete := true
+	switch {
+	case d.SkipPackages.Has(t.Name.Package):
+		c.Reason = fmt.Sprintf("package %s is skipped", t.Name.Package)
+	case key == d.ModelBase:
+		c.IsModel = true
+		c.Reason = "it's the model base " + d.ModelBase
+	default:
+		c.Reason = "doesn't embed " + d.ModelBase
+		for _, m := range t.Members {
+			mt := m.Type
+			if m.Embedded && mt.Kind == types.Pointer {
+				mt = mt.Elem
+			}
+			mc, ok := d.walk(mt, visiting)
+			if !ok {
+				complete = false
+			}
+			if mc.IsModel {
+				c.IsModel = true
+				if mt.String() == d.ModelBase {
+					c.Reason = "embeds " + d.ModelBase
+				} else {
+					c.Reason = fmt.Sprintf("%s %s", memberVia(m), mc.Reason)
+				}
+				break
+			}
+		}
+	}
+	if c.IsModel || complete {
+		d.models[key] = c
+		return c, true
+	}
+	return c, false
+}
+
+func memberVia(m types.Member) string {
+	if m.Embedded {
+		return fmt.Sprintf("via %s", m.Type.String())
+	}
+	return fmt.Sprintf("via field %s", m.Name)
+}
+
+// implements returns true if method set of t covers the methods of
+// interface iface, methods are matched by name.
+func (d *ModelDetector) implements(t *types.Type, iface string) (bool, string) {
+	it := d.lookupInterface(iface)
+	if it == nil {
+		return false, fmt.Sprintf("interface %s is not found", iface)
+	}
+	ms := d.methodSet(t)
+	missing := make([]string, 0)
+	for name := range it.Methods {
+		if !ms.Has(name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return true, "implements " + iface
+	}
+	sort.Strings(missing)
+	return false, fmt.Sprintf("doesn't implement %s, missing %s", iface, strings.Join(missing, ", "))
+}
+
+func (d *ModelDetector) lookupInterface(name string) *types.Type {
+	idx := strings.LastIndex(name, ".")
+	if idx <= 0 || d.universe == nil {
+		return nil
+	}
+	pkg := d.universe[name[:idx]]
+	if pkg == nil {
+		return nil
+	}
+	t := pkg.Types[name[idx+1:]]
+	if t == nil || t.Kind != types.Interface {
+		return nil
+	}
+	return t
+}
+
+// methodSet returns names of methods of t including the promoted ones.
+func (d *ModelDetector) methodSet(t *types.Type) sets.String {
+	if t.Kind == types.Pointer {
+		t = t.Elem
+	}
+	key := t.String()
+	if ms, ok := d.methodSets[key]; ok {
+		return ms
+	}
+	ms := sets.NewString()
+	d.methodSets[key] = ms
+	for name := range t.Methods {
+		ms.Insert(name)
+	}
+	for _, m := range t.Members {
+		if m.Embedded {
+			ms.Insert(d.methodSet(m.Type).List()...)
+		}
+	}
+	return ms
+}
+
+// IsModelManager returns true if t can be the manager of models, it must
+// implement ManagerInterface if it's set.
+func (d *ModelDetector) IsModelManager(t *types.Type) (bool, string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.ManagerInterface == "" {
+		return true, "manager interface is not set"
+	}
+	if d.lookupInterface(d.ManagerInterface) == nil {
+		return true, fmt.Sprintf("interface %s is not found, not checked", d.ManagerInterface)
+	}
+	return d.implements(t, d.ManagerInterface)
+}
+
+// CollectModelManager inserts the models of srcPkg into modelTypes and the
+// managers of them into modelManagers keyed by model name.
+func (d *ModelDetector) CollectModelManager(srcPkg string, pkgTypes []*types.Type, modelTypes sets.String, modelManagers map[string]*types.Type) {
+	restTypes := make([]*types.Type, 0)
+	for _, t := range pkgTypes {
+		if t.Kind != types.Struct {
+			continue
+		}
+		if !InSourcePackage(t, srcPkg) {
+			continue
+		}
+
+		if IsPrivateStruct(t.Name.Name) {
+			continue
+		}
+
+		if d.Classify(t).IsModel {
+			modelTypes.Insert(t.String())
+		} else {
+			restTypes = append(restTypes, t)
+		}
+	}
+	for _, t := range restTypes {
+		if strings.HasSuffix(t.Name.Name, "Manager") {
+			modelName := strings.TrimSuffix(t.String(), "Manager")
+			if !modelTypes.Has(modelName) {
+				continue
+			}
+			if ok, reason := d.IsModelManager(t); !ok {
+				klog.Warningf("%s isn't manager of model %s: %s", t.String(), modelName, reason)
+				continue
+			}
+			modelManagers[modelName] = t
+		}
+	}
+}
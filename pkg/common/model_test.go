@@ -0,0 +1,92 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/gengo/types"
+)
+
+func TestModelDetector(t *testing.T) {
+	const (
+		dbPkg    = "yunion.io/x/onecloud/pkg/cloudcommon/db"
+		modelPkg = "yunion.io/x/onecloud/pkg/compute/models"
+	)
+	fn := &types.Type{Kind: types.Func}
+	base := &types.Type{Name: types.Name{Package: dbPkg, Name: "SModelBase"}, Kind: types.Struct}
+	resBase := &types.Type{
+		Name:    types.Name{Package: dbPkg, Name: "SResourceBase"},
+		Kind:    types.Struct,
+		Members: []types.Member{{Name: "SModelBase", Type: base, Embedded: true}},
+	}
+	byPointer := &types.Type{
+		Name: types.Name{Package: modelPkg, Name: "SServer"},
+		Kind: types.Struct,
+		Members: []types.Member{
+			{Name: "SResourceBase", Type: &types.Type{Kind: types.Pointer, Elem: resBase, Name: types.Name{Name: "*" + resBase.String()}}, Embedded: true},
+		},
+	}
+	quota := &types.Type{
+		Name:    types.Name{Package: dbPkg + "/quotas", Name: "SQuota"},
+		Kind:    types.Struct,
+		Members: []types.Member{{Name: "SModelBase", Type: base, Embedded: true}},
+	}
+	iface := &types.Type{
+		Name:    types.Name{Package: dbPkg, Name: "IModel"},
+		Kind:    types.Interface,
+		Methods: map[string]*types.Type{"GetId": fn, "GetName": fn},
+	}
+	getter := &types.Type{
+		Name:    types.Name{Package: modelPkg, Name: "SNamed"},
+		Kind:    types.Struct,
+		Methods: map[string]*types.Type{"GetName": fn},
+	}
+	custom := &types.Type{
+		Name:    types.Name{Package: modelPkg, Name: "SCustom"},
+		Kind:    types.Struct,
+		Members: []types.Member{{Name: "SNamed", Type: getter, Embedded: true}},
+		Methods: map[string]*types.Type{"GetId": fn},
+	}
+	// A embeds *B embeds *A
+	recA := &types.Type{Name: types.Name{Package: modelPkg, Name: "A"}, Kind: types.Struct}
+	recB := &types.Type{Name: types.Name{Package: modelPkg, Name: "B"}, Kind: types.Struct}
+	recA.Members = []types.Member{{Name: "B", Type: &types.Type{Kind: types.Pointer, Elem: recB, Name: types.Name{Name: "*B"}}, Embedded: true}}
+	recB.Members = []types.Member{{Name: "A", Type: &types.Type{Kind: types.Pointer, Elem: recA, Name: types.Name{Name: "*A"}}, Embedded: true}}
+
+	d := NewModelDetector()
+	for _, c := range []struct {
+		t      *types.Type
+		want   bool
+		reason string
+	}{
+		{base, false, "it's the model base"},
+		{byPointer, true, "via *" + resBase.String()},
+		{quota, false, "is skipped"},
+		{custom, false, "doesn't embed"},
+		{recA, false, "doesn't embed"},
+		{recB, false, "doesn't embed"},
+	} {
+		got := d.Classify(c.t)
+		if got.IsModel != c.want || !strings.Contains(got.Reason, c.reason) {
+			t.Errorf("Classify(%s) = %s", c.t, got)
+		}
+	}
+
+	d.SetUniverse(types.Universe{
+		dbPkg: &types.Package{Path: dbPkg, Types: map[string]*types.Type{"IModel": iface}},
+	})
+	d.SetInterfaces(iface.String(), iface.String())
+	if got := d.Classify(custom); !got.IsModel {
+		t.Errorf("Classify(%s) = %s", custom, got)
+	}
+	if got := d.Classify(getter); got.IsModel || !strings.Contains(got.Reason, "missing GetId") {
+		t.Errorf("Classify(%s) = %s", getter, got)
+	}
+	if ok, _ := d.IsModelManager(getter); ok {
+		t.Errorf("%s isn't manager", getter)
+	}
+	d.SetInterfaces("", dbPkg+".IModelManager")
+	if ok, reason := d.IsModelManager(getter); !ok || !strings.Contains(reason, "not found") {
+		t.Errorf("unknown manager interface isn't skipped: %s", reason)
+	}
+}
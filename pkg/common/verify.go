@@ -0,0 +1,64 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"k8s.io/gengo/generator"
+	"k8s.io/klog"
+)
+
+// verifyFileType checks the generated file against the one on disk, the
+// unified diff is printed to out when they differ.
+type verifyFileType struct {
+	generator.DefaultFileType
+	out io.Writer
+}
+
+func (ft verifyFileType) VerifyFile(f *generator.File, pathname string) error {
+	klog.V(2).Infof("Verifying file %q", pathname)
+	friendlyName := filepath.Join(f.PackageName, f.Name)
+	b := &bytes.Buffer{}
+	et := generator.NewErrorTracker(b)
+	ft.Assemble(et, f)
+	if et.Error() != nil {
+		return et.Error()
+	}
+	formatted, err := ft.Format(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("unable to format the output for %q: %v", friendlyName, err)
+	}
+	existing, err := ioutil.ReadFile(pathname)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to read file %q for comparison: %v", friendlyName, err)
+	}
+	diff := UnifiedDiff(pathname, pathname+" (generated)", existing, formatted)
+	if diff == "" {
+		return nil
+	}
+	fmt.Fprint(ft.out, diff)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("output for %q does not exist", pathname)
+	}
+	return fmt.Errorf("output for %q is out of date", pathname)
+}
+
+// RegisterVerifyFileTypes replaces the registered file types of context with
+// the ones printing unified diff to out when verifying, it should be called
+// after all file types registered.
+func RegisterVerifyFileTypes(c *generator.Context, out io.Writer) {
+	for name, ft := range c.FileTypes {
+		switch dft := ft.(type) {
+		case generator.DefaultFileType:
+			c.FileTypes[name] = verifyFileType{DefaultFileType: dft, out: out}
+		case *generator.DefaultFileType:
+			c.FileTypes[name] = verifyFileType{DefaultFileType: *dft, out: out}
+		default:
+			klog.Warningf("file type %q can't be verified with diff", name)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package args
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/pflag"
+	"k8s.io/gengo/args"
+)
+
+// CustomArgs is used by the gengo framework to pass args specific to jsonschema-gen.
+type CustomArgs struct {
+	// NoAdditionalProperties if set, object schemas of structs forbid the
+	// properties not declared by their fields.
+	NoAdditionalProperties bool
+	// BaseID is the URI schema files are published under, the $id of each
+	// schema is the URI of its file. Schemas have no $id if empty.
+	BaseID string
+}
+
+// NewDefaults returns default arguments for the generator.
+func NewDefaults() (*args.GeneratorArgs, *CustomArgs) {
+	genericArgs := args.Default().WithoutDefaultFlagParsing()
+	customArgs := &CustomArgs{}
+	genericArgs.CustomArgs = customArgs
+	return genericArgs, customArgs
+}
+
+// AddFlags add the generator flags to the flag set.
+func (ca *CustomArgs) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&ca.NoAdditionalProperties, "no-additional-properties", ca.NoAdditionalProperties, "Emit additionalProperties: false for struct schemas, so undeclared properties are invalid")
+	fs.StringVar(&ca.BaseID, "base-id", ca.BaseID, "URI the schema files are published under, used as the base of $id, e.g. https://example.com/schemas")
+}
+
+// Validate checks the given arguments.
+func Validate(genericArgs *args.GeneratorArgs) error {
+	customArgs, ok := genericArgs.CustomArgs.(*CustomArgs)
+	if !ok {
+		return fmt.Errorf("custom args type %T is not *CustomArgs", genericArgs.CustomArgs)
+	}
+	if len(genericArgs.OutputPackagePath) == 0 {
+		return fmt.Errorf("output package cannot be empty")
+	}
+	if customArgs.BaseID != "" {
+		u, err := url.Parse(customArgs.BaseID)
+		if err != nil {
+			return fmt.Errorf("invalid base id %q: %v", customArgs.BaseID, err)
+		}
+		if !u.IsAbs() {
+			return fmt.Errorf("base id %q must be an absolute URI", customArgs.BaseID)
+		}
+	}
+	return nil
+}
+
+// GetCustomArgs returns the CustomArgs of genericArgs, defaults are used if
+// the caller doesn't set it.
+func GetCustomArgs(genericArgs *args.GeneratorArgs) *CustomArgs {
+	if customArgs, ok := genericArgs.CustomArgs.(*CustomArgs); ok {
+		return customArgs
+	}
+	return &CustomArgs{}
+}
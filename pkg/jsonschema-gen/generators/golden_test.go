@@ -0,0 +1,34 @@
+package generators
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"yunion.io/x/code-generator/pkg/common/golden"
+	schemaargs "yunion.io/x/code-generator/pkg/jsonschema-gen/args"
+)
+
+func TestGolden(t *testing.T) {
+	arguments, customArgs := schemaargs.NewDefaults()
+	arguments.InputDirs = []string{"yunion.io/x/onecloud/pkg/compute/models"}
+	arguments.OutputPackagePath = "yunion.io/x/onecloud/pkg/generated/jsonschema/compute"
+	customArgs.NoAdditionalProperties = true
+	dir := golden.Run(t, arguments, NameSystems(), DefaultNameSystem(), Packages)
+	golden.Compare(t, dir, filepath.Join("testdata", "golden"))
+
+	// TriState is a nullable boolean as the *bool of model-api-gen
+	data, err := ioutil.ReadFile(filepath.Join(dir, arguments.OutputPackagePath, "compute.SServer.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &Schema{}
+	if err := json.Unmarshal(data, server); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := server.Properties["disabled"].Type, []interface{}{"boolean", "null"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("type of disabled = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,171 @@
+package generators
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/gengo/args"
+	"k8s.io/gengo/generator"
+	"k8s.io/gengo/namer"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+
+	"yunion.io/x/pkg/util/sets"
+
+	"yunion.io/x/code-generator/pkg/common"
+	schemaargs "yunion.io/x/code-generator/pkg/jsonschema-gen/args"
+	apigen "yunion.io/x/code-generator/pkg/model-api-gen/generators"
+	"yunion.io/x/code-generator/pkg/swagger-gen/generators"
+)
+
+const (
+	// jsonSchemaFileType is the gengo file type of .schema.json files
+	jsonSchemaFileType = "jsonschema"
+)
+
+// payloadSuffixes are the name suffixes of the request and response types
+// of input packages, each of them gets a schema file.
+var payloadSuffixes = []string{"Input", "Output", "Details"}
+
+// NameSystems returns the name system used by the generators in this package.
+func NameSystems() namer.NameSystems {
+	return namer.NameSystems{
+		"public": namer.NewPublicNamer(0),
+		"raw":    namer.NewRawNamer("", nil),
+	}
+}
+
+// DefaultNameSystem returns the default name system for ordering the types to be
+// processed by the generators in this package.
+func DefaultNameSystem() string {
+	return "public"
+}
+
+func registerJSONSchemaFileType(c *generator.Context) {
+	c.FileTypes[jsonSchemaFileType] = generator.DefaultFileType{
+		Format: func(src []byte) ([]byte, error) {
+			return src, nil
+		},
+		Assemble: func(w io.Writer, f *generator.File) {
+			w.Write(f.Body.Bytes())
+		},
+	}
+}
+
+// isPayloadType returns true if t is named as a request or response type.
+func isPayloadType(t *types.Type) bool {
+	for _, suffix := range payloadSuffixes {
+		if strings.HasSuffix(t.Name.Name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// rootTypes returns the types of input package pkg which get a schema file:
+// the models and types model-api-gen copies, and the input and output
+// types. Types they refer to are added by the schema builder.
+func rootTypes(pkg *types.Package, pkgTypes []*types.Type) []*types.Type {
+	modelTypes, dependTypes := apigen.CollectTypes(pkg.Path, pkgTypes)
+	names := make([]string, 0, len(pkg.Types))
+	for name := range pkg.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ret := make([]*types.Type, 0)
+	for _, name := range names {
+		t := pkg.Types[name]
+		if t.Kind != types.Struct && t.Kind != types.Alias {
+			continue
+		}
+		if common.IsPrivateStruct(t.Name.Name) || generators.IncludeIgnoreTag(t) {
+			continue
+		}
+		if modelTypes.Has(t.String()) || dependTypes.Has(t.String()) || isPayloadType(t) {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+// Packages makes the jsonschema-gen package definition.
+func Packages(ctx *generator.Context, arguments *args.GeneratorArgs) generator.Packages {
+	customArgs := schemaargs.GetCustomArgs(arguments)
+	registerJSONSchemaFileType(ctx)
+	if arguments.VerifyOnly {
+		common.RegisterVerifyFileTypes(ctx, os.Stdout)
+	}
+	common.DefaultModelDetector.SetUniverse(ctx.Universe)
+
+	inputs := sets.NewString(ctx.Inputs...)
+	typeMap := apigen.NewTypeMap(apigen.NewDefaultMappingConfig(), ctx.Order)
+	b := newSchemaBuilder(customArgs.NoAdditionalProperties, customArgs.BaseID, typeMap)
+	for _, path := range inputs.List() {
+		pkg := ctx.Universe[path]
+		if pkg == nil {
+			continue
+		}
+		klog.Infof("Considering pkg %q", pkg.Path)
+		for _, t := range rootTypes(pkg, ctx.Order) {
+			b.add(t)
+		}
+	}
+	files := b.build()
+
+	outPkgName := strings.Split(filepath.Base(arguments.OutputPackagePath), ".")[0]
+	return generator.Packages{
+		&generator.DefaultPackage{
+			PackageName: outPkgName,
+			PackagePath: arguments.OutputPackagePath,
+			GeneratorFunc: func(c *generator.Context) []generator.Generator {
+				gens := make([]generator.Generator, 0, len(files))
+				for _, f := range files {
+					gens = append(gens, newJSONSchemaGen(f))
+				}
+				return gens
+			},
+		},
+	}
+}
+
+// jsonSchemaGen writes the schema file of a type.
+type jsonSchemaGen struct {
+	generator.DefaultGen
+	file *schemaFile
+}
+
+// newJSONSchemaGen returns a generator writing schema file f.
+func newJSONSchemaGen(f *schemaFile) generator.Generator {
+	return &jsonSchemaGen{
+		DefaultGen: generator.DefaultGen{
+			OptionalName: f.name,
+		},
+		file: f,
+	}
+}
+
+func (g *jsonSchemaGen) Filename() string {
+	return g.OptionalName
+}
+
+func (g *jsonSchemaGen) FileType() string {
+	return jsonSchemaFileType
+}
+
+func (g *jsonSchemaGen) Filter(c *generator.Context, t *types.Type) bool {
+	return t == g.file.t
+}
+
+func (g *jsonSchemaGen) Finalize(c *generator.Context, w io.Writer) error {
+	klog.V(1).Infof("Generating json schema %s for type %s", g.file.name, g.file.t)
+	out, err := json.MarshalIndent(g.file.schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(out, '\n'))
+	return err
+}
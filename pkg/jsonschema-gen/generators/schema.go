@@ -0,0 +1,351 @@
+package generators
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+
+	"yunion.io/x/pkg/util/reflectutils"
+	"yunion.io/x/pkg/util/sets"
+
+	"yunion.io/x/code-generator/pkg/common"
+	apigen "yunion.io/x/code-generator/pkg/model-api-gen/generators"
+	"yunion.io/x/code-generator/pkg/swagger-gen/generators"
+)
+
+const (
+	// Draft is the JSON Schema dialect of generated schemas
+	Draft = "https://json-schema.org/draft/2020-12/schema"
+
+	// FileExtension is the extension of generated schema files
+	FileExtension = ".schema.json"
+)
+
+// Schema is a JSON Schema of draft 2020-12, only the keywords describing Go
+// types are supported.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Type is the type name, or the []string of type names of a nullable
+	// type, e.g. ["boolean", "null"]
+	Type            interface{} `json:"type,omitempty"`
+	Format          string      `json:"format,omitempty"`
+	ContentEncoding string      `json:"contentEncoding,omitempty"`
+
+	Items      *Schema            `json:"items,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is the *Schema of map values or false
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+
+	Enum       []interface{} `json:"enum,omitempty"`
+	Default    interface{}   `json:"default,omitempty"`
+	Examples   []interface{} `json:"examples,omitempty"`
+	Minimum    *float64      `json:"minimum,omitempty"`
+	Maximum    *float64      `json:"maximum,omitempty"`
+	Pattern    string        `json:"pattern,omitempty"`
+	Deprecated bool          `json:"deprecated,omitempty"`
+}
+
+// schemaFile is the schema of a named type written to its own file.
+type schemaFile struct {
+	// name is the file name, also used as the $ref of the type
+	name   string
+	t      *types.Type
+	schema *Schema
+}
+
+// schemaBuilder builds the schemas of named types, every named type referred
+// by them gets its own file too.
+type schemaBuilder struct {
+	noAdditionalProperties bool
+	baseID                 string
+	// typeMap replaces the types as model-api-gen does, see
+	// apigen.NewTypeMap
+	typeMap map[string]apigen.TypeMapping
+	// names maps types to their file names
+	names map[string]string
+	used  sets.String
+	// queue are the types whose schemas are not built yet
+	queue []*types.Type
+}
+
+func newSchemaBuilder(noAdditionalProperties bool, baseID string, typeMap map[string]apigen.TypeMapping) *schemaBuilder {
+	return &schemaBuilder{
+		noAdditionalProperties: noAdditionalProperties,
+		baseID:                 strings.TrimSuffix(baseID, "/"),
+		typeMap:                typeMap,
+		names:                  make(map[string]string),
+		used:                   sets.NewString(),
+	}
+}
+
+// moduleName returns the short name of package path, e.g. compute of
+// yunion.io/x/onecloud/pkg/compute/models.
+func moduleName(pkgPath string) string {
+	return filepath.Base(strings.TrimSuffix(pkgPath, "/models"))
+}
+
+// add records named type t and returns its file name. Types of different
+// packages with the same module and name are told by the full package path.
+func (b *schemaBuilder) add(t *types.Type) string {
+	if name, ok := b.names[t.String()]; ok {
+		return name
+	}
+	name := fmt.Sprintf("%s.%s%s", moduleName(t.Name.Package), t.Name.Name, FileExtension)
+	if b.used.Has(name) {
+		pkg := strings.NewReplacer("/", "_", ".", "_").Replace(t.Name.Package)
+		name = fmt.Sprintf("%s.%s%s", pkg, t.Name.Name, FileExtension)
+	}
+	b.names[t.String()] = name
+	b.used.Insert(name)
+	b.queue = append(b.queue, t)
+	return name
+}
+
+// build returns the schema files of added types and the types they refer to.
+func (b *schemaBuilder) build() []*schemaFile {
+	files := make([]*schemaFile, 0, len(b.queue))
+	for i := 0; i < len(b.queue); i++ {
+		t := b.queue[i]
+		s := b.namedSchema(t)
+		s.Schema = Draft
+		if b.baseID != "" {
+			s.ID = b.baseID + "/" + b.names[t.String()]
+		}
+		s.Title = t.Name.Name
+		s.Description = generators.CommentDescription(t.CommentLines)
+		files = append(files, &schemaFile{
+			name:   b.names[t.String()],
+			t:      t,
+			schema: s,
+		})
+	}
+	return files
+}
+
+// isNamedType returns true if t gets its own file and is referred by $ref.
+func isNamedType(t *types.Type) bool {
+	if t.Name.Package == "" || common.IsJSONObject(t) || isTimeType(t) {
+		return false
+	}
+	return t.Kind == types.Struct || t.Kind == types.Alias
+}
+
+func isTimeType(t *types.Type) bool {
+	return t.Name.Package == "time" && t.Name.Name == "Time"
+}
+
+func isModelBase(t *types.Type) bool {
+	return t.Name.Name == apigen.SModelBase
+}
+
+// namedSchema returns the schema of the definition of named type t.
+func (b *schemaBuilder) namedSchema(t *types.Type) *Schema {
+	if s := b.mappedSchema(t); s != nil {
+		return s
+	}
+	if t.Kind == types.Alias {
+		return b.schemaOf(t.Underlying)
+	}
+	return b.structSchema(t)
+}
+
+func builtinSchema(t *types.Type) *Schema {
+	switch t.Name.Name {
+	case "string":
+		return &Schema{Type: "string"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"byte", "rune":
+		return &Schema{Type: "integer"}
+	case "float32", "float64":
+		return &Schema{Type: "number"}
+	default:
+		klog.Warningf("Unsupported builtin type %s, accept any value", t.Name.Name)
+		return &Schema{}
+	}
+}
+
+// mappedSchema returns the schema of the type named type t is replaced by in
+// apis types, e.g. a nullable boolean of TriState, nil if it isn't replaced.
+func (b *schemaBuilder) mappedSchema(t *types.Type) *Schema {
+	if t.Name.Package == "" {
+		return nil
+	}
+	m, ok := apigen.LookupTypeMap(b.typeMap, t)
+	if !ok {
+		return nil
+	}
+	mt, err := common.ParseGoType(m.Type)
+	if err != nil {
+		klog.Warningf("Invalid type %q of type map of %s: %v", m.Type, t.String(), err)
+		return nil
+	}
+	if mt.Kind == types.Pointer {
+		s := b.schemaOf(mt.Elem)
+		if name, ok := s.Type.(string); ok {
+			s.Type = []string{name, "null"}
+		}
+		return s
+	}
+	return b.schemaOf(mt)
+}
+
+// schemaOf returns the schema of t referred by a field, named types are
+// referred by $ref to their files.
+func (b *schemaBuilder) schemaOf(t *types.Type) *Schema {
+	if s := b.mappedSchema(t); s != nil {
+		return s
+	}
+	if isNamedType(t) {
+		return &Schema{Ref: b.add(t)}
+	}
+	switch t.Kind {
+	case types.Builtin:
+		return builtinSchema(t)
+	case types.Pointer:
+		return b.schemaOf(t.Elem)
+	case types.Slice, types.Array:
+		if elem := apigen.UnderlyingType(t.Elem); elem.Kind == types.Builtin && elem.Name.Name == "byte" {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem)}
+	case types.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem)}
+	case types.Struct:
+		if isTimeType(t) {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+		if common.IsJSONObject(t) {
+			return &Schema{}
+		}
+		return b.structSchema(t)
+	case types.Interface:
+		return &Schema{}
+	case types.Alias:
+		return b.schemaOf(t.Underlying)
+	default:
+		klog.Warningf("Unsupported type %s, kind is %s, accept any value", t.String(), t.Kind)
+		return &Schema{}
+	}
+}
+
+// structSchema returns the object schema of struct t, fields of embedded
+// structs are flattened into it as encoding/json does.
+func (b *schemaBuilder) structSchema(t *types.Type) *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	b.addProperties(s, t)
+	if b.noAdditionalProperties {
+		s.AdditionalProperties = false
+	}
+	return s
+}
+
+func (b *schemaBuilder) addProperties(s *Schema, t *types.Type) {
+	// fields of the outer struct win over the embedded ones
+	embedded := make([]*types.Type, 0)
+	for _, m := range t.Members {
+		if common.GetFieldOptions(m.CommentLines).Skip {
+			continue
+		}
+		mt := m.Type
+		if m.Embedded && mt.Kind == types.Pointer {
+			mt = mt.Elem
+		}
+		if m.Embedded && apigen.UnderlyingType(mt).Kind == types.Struct && !isTimeType(mt) {
+			if !isModelBase(mt) {
+				embedded = append(embedded, apigen.UnderlyingType(mt))
+			}
+			continue
+		}
+		if common.IsPrivateStruct(m.Name) {
+			continue
+		}
+		info := reflectutils.ParseFieldJsonInfo(m.Name, reflect.StructTag(m.Tags))
+		if info.Ignore {
+			continue
+		}
+		if val, ok := info.Tags["ignore"]; ok && val == "true" {
+			continue
+		}
+		// a renamed field keeps its json name
+		name := info.MarshalName()
+		// keywords besides $ref apply together in draft 2020-12
+		prop := b.schemaOf(common.MemberType(m))
+		prop.Description = generators.CommentDescription(m.CommentLines)
+		markers := generators.MemberMarkers(t, m)
+		if err := applyMarkers(prop, markers); err != nil {
+			klog.Warningf("Ignore markers of %s.%s: %v", t.String(), m.Name, err)
+		}
+		if markers.Required {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+	for _, et := range embedded {
+		inner := &Schema{Properties: make(map[string]*Schema)}
+		b.addProperties(inner, et)
+		for name, prop := range inner.Properties {
+			if _, ok := s.Properties[name]; !ok {
+				s.Properties[name] = prop
+			}
+		}
+		for _, name := range inner.Required {
+			if s.Properties[name] == inner.Properties[name] {
+				s.Required = append(s.Required, name)
+			}
+		}
+	}
+}
+
+// valueType returns the value type of schema s.
+func valueType(s *Schema) *generators.ValueType {
+	if s == nil {
+		return nil
+	}
+	ret := &generators.ValueType{Items: valueType(s.Items)}
+	switch typ := s.Type.(type) {
+	case string:
+		ret.Type = typ
+	case []string:
+		// the type of a nullable type
+		ret.Type = typ[0]
+	}
+	return ret
+}
+
+// applyMarkers sets the field markers of swagger-gen except required to the
+// schema s of field, constraints of an array apply to its items.
+func applyMarkers(s *Schema, m *generators.FieldMarkers) error {
+	vals, err := m.Values(valueType(s))
+	if err != nil {
+		return err
+	}
+	s.Deprecated = m.Deprecated
+	s.Default = vals.Default
+	if vals.Example != nil {
+		s.Examples = []interface{}{vals.Example}
+	}
+	target := s
+	if s.Type == "array" && s.Items != nil {
+		target = s.Items
+	}
+	target.Enum = vals.Enum
+	target.Minimum = m.Minimum
+	target.Maximum = m.Maximum
+	target.Pattern = m.Pattern
+	return nil
+}
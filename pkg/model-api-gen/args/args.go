@@ -0,0 +1,114 @@
+package args
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/gengo/args"
+
+	"yunion.io/x/code-generator/pkg/common/cache"
+	"yunion.io/x/code-generator/pkg/common/diagnostics"
+)
+
+// CustomArgs is used by the gengo framework to pass args specific to model-api-gen.
+type CustomArgs struct {
+	// ClientPackage if set, a typed REST client of every resource manager
+	// is generated into this package.
+	ClientPackage string
+	// MappingFile is the YAML or JSON file of source to apis package
+	// mappings, the mappings of onecloud are used if empty.
+	MappingFile string
+	// FailOn is the lowest severity of diagnostics failing the run.
+	FailOn string
+	// DiagnosticsReport if set, the diagnostics are written to this file
+	// as JSON.
+	DiagnosticsReport string
+
+	// WithHelpers are the companion methods generated for the api types
+	// into zz_generated.helpers.go, any of HelperDeepCopy, HelperEqual and
+	// HelperString.
+	WithHelpers []string
+	// Cache skips the run if its inputs and outputs are unchanged.
+	Cache cache.Options
+	// DebugModels prints the resource model classification of input
+	// packages instead of generating.
+	DebugModels bool
+
+	// Diagnostics collects the problems found while generating.
+	Diagnostics *diagnostics.Collector
+}
+
+const (
+	// HelperDeepCopy generates DeepCopyInto and DeepCopy
+	HelperDeepCopy = "deepcopy"
+	// HelperEqual generates Equal
+	HelperEqual = "equal"
+	// HelperString generates String redacting the sensitive fields
+	HelperString = "string"
+)
+
+// Helpers are the valid values of --with-helpers.
+var Helpers = []string{HelperDeepCopy, HelperEqual, HelperString}
+
+// NewDefaults returns default arguments for the generator.
+func NewDefaults() (*args.GeneratorArgs, *CustomArgs) {
+	genericArgs := args.Default().WithoutDefaultFlagParsing()
+	customArgs := &CustomArgs{
+		FailOn:      string(diagnostics.SeverityError),
+		Diagnostics: diagnostics.NewCollector(),
+		Cache:       cache.NewOptions(),
+	}
+	genericArgs.CustomArgs = customArgs
+	genericArgs.OutputFileBaseName = "zz_generated.model"
+	return genericArgs, customArgs
+}
+
+// AddFlags add the generator flags to the flag set.
+func (ca *CustomArgs) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&ca.ClientPackage, "client-package", ca.ClientPackage, "Package path to generate typed REST clients of resource managers into, skipped if empty")
+	fs.StringVar(&ca.MappingFile, "mapping-file", ca.MappingFile, "YAML or JSON file of source to apis package mappings, local import prefixes and skipped packages")
+	fs.StringVar(&ca.FailOn, "fail-on", ca.FailOn, "Exit non-zero if any diagnostic is as severe as this, error or warning")
+	fs.StringVar(&ca.DiagnosticsReport, "diagnostics-report", ca.DiagnosticsReport, "File to write the diagnostics report as JSON, skipped if empty")
+	fs.StringSliceVar(&ca.WithHelpers, "with-helpers", ca.WithHelpers, "Companion methods to generate for the api types, any of "+strings.Join(Helpers, ","))
+	ca.Cache.AddFlags(fs)
+	fs.BoolVar(&ca.DebugModels, "debug-models", ca.DebugModels, "Print why each exported struct of the input packages is or isn't a model instead of generating")
+}
+
+// Validate checks the given arguments.
+func Validate(genericArgs *args.GeneratorArgs) error {
+	customArgs, ok := genericArgs.CustomArgs.(*CustomArgs)
+	if !ok {
+		return fmt.Errorf("custom args type %T is not *CustomArgs", genericArgs.CustomArgs)
+	}
+	if _, err := diagnostics.ParseSeverity(customArgs.FailOn); err != nil {
+		return fmt.Errorf("--fail-on: %v", err)
+	}
+	for _, h := range customArgs.WithHelpers {
+		if !isHelper(h) {
+			return fmt.Errorf("--with-helpers: unknown helper %q, expect any of %s", h, strings.Join(Helpers, ","))
+		}
+	}
+	if len(genericArgs.OutputPackagePath) == 0 && !customArgs.DebugModels {
+		return fmt.Errorf("output package cannot be empty")
+	}
+	return nil
+}
+
+func isHelper(h string) bool {
+	for _, v := range Helpers {
+		if h == v {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCustomArgs returns the CustomArgs of genericArgs, defaults are used if
+// the caller doesn't set it.
+func GetCustomArgs(genericArgs *args.GeneratorArgs) *CustomArgs {
+	if customArgs, ok := genericArgs.CustomArgs.(*CustomArgs); ok {
+		return customArgs
+	}
+	return &CustomArgs{}
+}
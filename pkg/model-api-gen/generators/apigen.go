@@ -1,13 +1,14 @@
 package generators
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
 
-	"golang.org/x/tools/imports"
 	"k8s.io/gengo/args"
 	"k8s.io/gengo/generator"
 	"k8s.io/gengo/namer"
@@ -20,6 +21,8 @@ import (
 	"yunion.io/x/pkg/utils"
 
 	"yunion.io/x/code-generator/pkg/common"
+	"yunion.io/x/code-generator/pkg/common/diagnostics"
+	apiargs "yunion.io/x/code-generator/pkg/model-api-gen/args"
 	"yunion.io/x/code-generator/pkg/swagger-gen/generators"
 )
 
@@ -54,13 +57,10 @@ var (
 	APIsMonitorPackage       = filepath.Join(APIsPackage, "monitor")
 )
 
+// GetInputOutputPackageMap returns the default mappings of source packages
+// to apis packages, see MappingConfig.
 func GetInputOutputPackageMap(apisPkg string) map[string]string {
-	ret := map[string]string{
-		CloudCommonDBPackage: APIsPackage,
-		CloudProviderPackage: APIsCloudProviderPackage, // filepath.Join(apisPkg, "cloudprovider"),
-		MonitorModelsPackage: APIsMonitorPackage,
-	}
-	return ret
+	return NewDefaultMappingConfig().Mappings
 }
 
 // NameSystems returns the name system used by the generators in this package.
@@ -85,6 +85,19 @@ func Packages(ctx *generator.Context, arguments *args.GeneratorArgs) generator.P
 		klog.Fatalf("Failed loading boilerplate: %v", err)
 	}
 
+	customArgs := apiargs.GetCustomArgs(arguments)
+	customArgs.Diagnostics.SetUniverse(ctx.Universe)
+	mapping, err := LoadMappingConfig(customArgs.MappingFile)
+	if err != nil {
+		klog.Fatalf("Failed loading mapping file: %v", err)
+	}
+	detector := mapping.NewModelDetector(ctx.Universe)
+	ctx.FileTypes[generator.GolangFileType] = mapping.golangFileType()
+	if arguments.VerifyOnly {
+		common.RegisterVerifyFileTypes(ctx, os.Stdout)
+	} else {
+		customArgs.Cache.RegisterFileTypes(ctx)
+	}
 	inputs := sets.NewString(ctx.Inputs...)
 	packages := generator.Packages{}
 	//header := append([]byte(fmt.Sprintf("// +build !%s\n\n", arguments.GeneratedBuildTag)), boilerplate...)
@@ -104,18 +117,44 @@ func Packages(ctx *generator.Context, arguments *args.GeneratorArgs) generator.P
 				PackagePath: arguments.OutputPackagePath,
 				HeaderText:  boilerplate,
 				GeneratorFunc: func(c *generator.Context) []generator.Generator {
-					return []generator.Generator{
+					// Generate api types by model.
+					api := NewApiGen(arguments.OutputFileBaseName, pkg.Path, "", ctx.Order, arguments.OutputPackagePath, mapping, detector, customArgs.Diagnostics)
+					gens := []generator.Generator{
 						// Always generate a "doc.go" file.
 						// generator.DefaultGen{OptionalName: "doc"},
-						// Generate api types by model.
-						NewApiGen(arguments.OutputFileBaseName, pkg.Path, "", ctx.Order, arguments.OutputPackagePath),
+						api,
+					}
+					if len(customArgs.WithHelpers) > 0 {
+						// Generate companion methods of api types, it must run after api.
+						gens = append(gens, NewHelpersGen("zz_generated.helpers", api, customArgs.WithHelpers))
 					}
+					return gens
 				},
 			})
+		if customArgs.ClientPackage != "" {
+			packages = append(packages, newClientPackage(pkg.Path, customArgs.ClientPackage, boilerplate, ctx.Order, detector))
+		}
 	}
 	return packages
 }
 
+func newClientPackage(srcPkg, clientPkg string, boilerplate []byte, pkgTypes []*types.Type, detector *common.ModelDetector) generator.Package {
+	return &generator.DefaultPackage{
+		PackageName: filepath.Base(clientPkg),
+		PackagePath: clientPkg,
+		HeaderText:  boilerplate,
+		GeneratorFunc: func(c *generator.Context) []generator.Generator {
+			return []generator.Generator{
+				NewClientBaseGen("zz_generated.client_base"),
+				NewClientGen("zz_generated.client", srcPkg, clientPkg, pkgTypes, detector),
+			}
+		},
+		FilterFunc: func(c *generator.Context, t *types.Type) bool {
+			return t.Name.Package == srcPkg
+		},
+	}
+}
+
 type apiGen struct {
 	generator.DefaultGen
 	// sourcePackage is source package of input types
@@ -126,11 +165,24 @@ type apiGen struct {
 	modelDependTypes sets.String
 	// isCommonDBPackage
 	isCommonDBPackage bool
+	// mapping is the mapping of source packages to apis packages
+	mapping *MappingConfig
+	// detector classifies the resource models of mapping
+	detector *common.ModelDetector
 
 	imports            namer.ImportTracker
 	needImportPackages sets.String
 	apisPkg            string
 	outputPackage      string
+	rawNamer           namer.Namer
+
+	// diags collects the problems of types can't be generated
+	diags *diagnostics.Collector
+	// typeMap replaces fields of the types, see NewTypeMap
+	typeMap map[string]TypeMapping
+	// decls are the generated declarations keyed by type name, helpersGen
+	// generates the companion methods of them
+	decls map[string][]byte
 }
 
 func isCommonDBPackage(pkg string) bool {
@@ -138,21 +190,26 @@ func isCommonDBPackage(pkg string) bool {
 	return strings.HasSuffix(pkg, CloudCommonDBPackage)
 }
 
+// defaultAPIsPkg returns yunion.io/x/<project>/pkg/apis of source package,
+// it returns empty if srcPkg isn't of the layout.
 func defaultAPIsPkg(srcPkg string) string {
 	yunionPrefix := "yunion.io/x/"
-	parts := strings.Split(srcPkg, yunionPrefix)
-	projectName := strings.Split(parts[1], "/")[0]
+	if !strings.HasPrefix(srcPkg, yunionPrefix) {
+		return ""
+	}
+	projectName := strings.Split(strings.TrimPrefix(srcPkg, yunionPrefix), "/")[0]
 	return filepath.Join(yunionPrefix, projectName, "pkg", "apis")
 }
 
-func reviseImportPath() {
-	imports.LocalPrefix = "yunion.io/x/:yunion.io/x/onecloud:yunion.io/x/meter:yunion.io/x/nocloud"
-}
-
-func NewApiGen(sanitizedName, sourcePackage, apisPkg string, pkgTypes []*types.Type, outputPkg string) generator.Generator {
-	reviseImportPath()
+func NewApiGen(sanitizedName, sourcePackage, apisPkg string, pkgTypes []*types.Type, outputPkg string, mapping *MappingConfig, detector *common.ModelDetector, diags *diagnostics.Collector) generator.Generator {
+	if mapping == nil {
+		mapping = NewDefaultMappingConfig()
+	}
+	if detector == nil {
+		detector = mapping.NewModelDetector(nil)
+	}
 	if apisPkg == "" {
-		apisPkg = defaultAPIsPkg(sourcePackage)
+		apisPkg = mapping.apisPackage(sourcePackage)
 	}
 	gen := &apiGen{
 		DefaultGen: generator.DefaultGen{
@@ -166,12 +223,30 @@ func NewApiGen(sanitizedName, sourcePackage, apisPkg string, pkgTypes []*types.T
 		needImportPackages: sets.NewString(),
 		apisPkg:            apisPkg,
 		outputPackage:      outputPkg,
+		mapping:            mapping,
+		detector:           detector,
+		diags:              diags,
+		typeMap:            NewTypeMap(mapping, pkgTypes),
+		decls:              make(map[string][]byte),
 	}
 	gen.collectTypes(pkgTypes)
 	klog.V(1).Infof("sets: %v\ndepsets: %v", gen.modelTypes.List(), gen.modelDependTypes.List())
 	return gen
 }
 
+// CollectTypes returns the model types of source package and the types they
+// depend on, they are the types model-api-gen generates.
+func CollectTypes(sourcePackage string, pkgTypes []*types.Type) (sets.String, sets.String) {
+	gen := &apiGen{
+		sourcePackage:    sourcePackage,
+		modelTypes:       sets.NewString(),
+		modelDependTypes: sets.NewString(),
+		detector:         common.DefaultModelDetector,
+	}
+	gen.collectTypes(pkgTypes)
+	return gen.modelTypes, gen.modelDependTypes
+}
+
 func (g *apiGen) Namers(c *generator.Context) namer.NameSystems {
 	// Have the raw namer for this file track what it imports.
 	return namer.NameSystems{
@@ -181,7 +256,7 @@ func (g *apiGen) Namers(c *generator.Context) namer.NameSystems {
 }
 
 func (g *apiGen) GetInputOutputPackageMap() map[string]string {
-	return GetInputOutputPackageMap(g.apisPkg)
+	return g.mapping.Mappings
 }
 
 func (g *apiGen) collectTypes(pkgTypes []*types.Type) {
@@ -202,50 +277,43 @@ func (g *apiGen) collectTypes(pkgTypes []*types.Type) {
 	}
 }
 
-// getPrimitiveType return the primitive type of Map, Slice, Pointer or Chan
-func getPrimitiveType(t *types.Type) *types.Type {
-	var compondKinds = sets.NewString(
-		string(types.Map),
-		string(types.Slice),
-		string(types.Pointer),
-		string(types.Chan))
-
-	if !compondKinds.Has(string(t.Kind)) {
-		return t
-	}
-
-	et := t.Elem
-	return getPrimitiveType(et)
-}
-
 func isModelBase(t *types.Type) bool {
 	return t.Name.Name == SModelBase
 }
 
+// addDependTypes walks t recursively and records the types of source package
+// it depends on.
 func (g *apiGen) addDependTypes(t *types.Type, out, dependOut sets.String) {
-	if t.Kind == types.Alias || t.Kind == types.Struct {
-		if !out.Has(t.String()) && g.inSourcePackage(t) && !isModelBase(t) {
-			dependOut.Insert(t.String())
-		}
-		umt := underlyingType(t)
-		if umt.Kind == types.Builtin {
-			return
-		}
-		t = getPrimitiveType(umt)
-		if t.Kind == types.Builtin {
-			return
-		}
-		if !out.Has(t.String()) && g.inSourcePackage(t) && !isModelBase(t) {
-			dependOut.Insert(t.String())
-		}
+	g.walkDependTypes(t, out, dependOut, sets.NewString())
+}
+
+func (g *apiGen) walkDependTypes(t *types.Type, out, dependOut, visited sets.String) {
+	if t == nil || visited.Has(t.String()) {
+		return
 	}
-	for _, m := range t.Members {
-		switch m.Type.Kind {
-		case types.Struct, types.Alias:
-			g.addDependTypes(m.Type, out, dependOut)
-		case types.Pointer, types.Slice:
-			g.addDependTypes(m.Type.Elem, out, dependOut)
+	visited.Insert(t.String())
+	switch t.Kind {
+	case types.Pointer, types.Slice, types.Array:
+		g.walkDependTypes(t.Elem, out, dependOut, visited)
+	case types.Map:
+		g.walkDependTypes(t.Key, out, dependOut, visited)
+		g.walkDependTypes(t.Elem, out, dependOut, visited)
+	case types.Alias:
+		g.insertDependType(t, out, dependOut)
+		g.walkDependTypes(t.Underlying, out, dependOut, visited)
+	case types.Struct:
+		g.insertDependType(t, out, dependOut)
+		for _, m := range t.Members {
+			g.walkDependTypes(m.Type, out, dependOut, visited)
 		}
+	default:
+		klog.V(5).Infof("ignore depend type %s of kind %s", t.String(), t.Kind)
+	}
+}
+
+func (g *apiGen) insertDependType(t *types.Type, out, dependOut sets.String) {
+	if !out.Has(t.String()) && g.inSourcePackage(t) && !isModelBase(t) {
+		dependOut.Insert(t.String())
 	}
 }
 
@@ -271,8 +339,7 @@ func (g *apiGen) Filter(c *generator.Context, t *types.Type) bool {
 }
 
 func (g *apiGen) isResourceModel(t *types.Type) bool {
-	val := common.IsResourceModel(t)
-	return val
+	return g.detector.Classify(t).IsModel
 }
 
 func (g *apiGen) args(t *types.Type) interface{} {
@@ -285,12 +352,13 @@ func (g *apiGen) args(t *types.Type) interface{} {
 func (g *apiGen) Imports(c *generator.Context) []string {
 	lines := []string{}
 	for _, line := range g.imports.ImportLines() {
-		if strings.Index(line, CloudProviderPackage) > -1 || strings.Index(line, CloudCommonDBPackage) > -1 {
-			continue
-		}
 		parts := strings.Split(line, " ")
 		if len(parts) == 2 {
 			pkgName := strings.Trim(parts[1], `"`)
+			// types of mapped packages are referred by the apis packages
+			if g.mapping.isMappedPackage(pkgName) {
+				continue
+			}
 			if g.needImportPackages.Has(pkgName) || pkgName == g.outputPackage {
 				continue
 			}
@@ -304,59 +372,57 @@ func (g *apiGen) Imports(c *generator.Context) []string {
 func (g *apiGen) GenerateType(c *generator.Context, t *types.Type, w io.Writer) error {
 	klog.V(1).Infof("Generating api model for type %s", t.String())
 
-	err := g.generateTypeForOp(c, t, w)
+	decl := &bytes.Buffer{}
+	err := g.generateTypeForOp(c, t, io.MultiWriter(w, decl))
 	if err != nil {
 		return errors.Wrap(err, "generateTypeForOp")
 	}
+	g.decls[t.Name.Name] = decl.Bytes()
 	return nil
 }
 
 func (g *apiGen) generateTypeForOp(c *generator.Context, t *types.Type, w io.Writer) error {
+	if t.Kind != types.Struct && t.Kind != types.Alias {
+		g.diags.Errorf(t, "", "unsupported type kind %s", t.Kind)
+		return nil
+	}
 	sw := generator.NewSnippetWriter(w, c, "$", "$")
 
-	sw.Do(fmt.Sprintf("// %s is an autogenerated struct via %s.\n", t.Name.Name, t.Name.String()), nil)
-
 	// 1. generate resource base output by model to pkg/apis/<pkg>/generated.model.go
 	switch t.Kind {
 	case types.Struct:
 		g.generateStructType(t, sw)
 	case types.Alias:
 		g.generatorAliasType(t, sw)
-	default:
-		klog.Fatalf("Unsupported type %s %s", t, t.Kind)
 	}
 	return sw.Error()
 }
 
 func (g *apiGen) generateStructType(t *types.Type, sw *generator.SnippetWriter) {
 	//klog.Errorf("for type %q", t.String())
+	g.generateTypeComment(t, sw)
 	sw.Do("type $.type|public$ struct {\n", g.args(t))
 	g.generateFor(t, sw)
 	sw.Do("}\n", nil)
 }
 
 func (g *apiGen) generatorAliasType(t *types.Type, sw *generator.SnippetWriter) {
-	sw.Do("type $.type|public$ ", g.args(t))
-	ut := t.Underlying
-	switch ut.Kind {
-	case types.Slice:
-		elem := ut.Elem
-		content := "$.type|public$"
-		if elem.Kind == types.Pointer {
-			content = g.getPointerSourcePackageName(elem)
-		} else if elem.Kind == types.Builtin {
-			content = "$.type$"
-		}
-		sw.Do(fmt.Sprintf("[]%s", content), g.args(elem))
-	case types.Builtin:
-		sw.Do("$.type$ ", g.args(t.Underlying))
-	default:
-		sw.Do("$.type|public$ ", g.args(t.Underlying))
+	content, ok := g.typeExpr(t, "", t.Underlying)
+	if !ok {
+		return
 	}
-	sw.Do("\n", nil)
+	g.generateTypeComment(t, sw)
+	sw.Do(fmt.Sprintf("type $.type|public$ %s\n", content), g.args(t))
 }
 
-func underlyingType(t *types.Type) *types.Type {
+func (g *apiGen) generateTypeComment(t *types.Type, sw *generator.SnippetWriter) {
+	for _, l := range commentLines(typeDoc(t)) {
+		sw.Do("$.$\n", l)
+	}
+}
+
+// UnderlyingType returns the type alias t finally refers to.
+func UnderlyingType(t *types.Type) *types.Type {
 	for t.Kind == types.Alias {
 		t = t.Underlying
 	}
@@ -391,6 +457,21 @@ func (g *apiGen) generateForMember(t *types.Type, mem types.Member, sw *generato
 		return
 	}
 
+	g.reportFieldOptions(t, mem)
+	opts := common.GetFieldOptions(mem.CommentLines)
+	if opts.Skip {
+		return
+	}
+	if opts.Type != "" {
+		// the type given by tag is used as is
+		NewModelMember(mem).Do(sw, nil)
+		return
+	}
+	if ct, ok := g.lookupTypeMap(mt); ok && mt.Name.Package != "" && !mem.Embedded {
+		NewModelMember(mem).AddTag(ct.JSONTags...).Type(ct.Type).Do(sw, nil)
+		return
+	}
+
 	var f func(types.Member, *generator.SnippetWriter)
 	switch mt.Kind {
 	case types.Builtin:
@@ -400,20 +481,24 @@ func (g *apiGen) generateForMember(t *types.Type, mem types.Member, sw *generato
 			g.doStruct(t, member, sw)
 		}
 	case types.Interface:
-		f = g.doInterface
+		f = func(member types.Member, sw *generator.SnippetWriter) {
+			g.doInterface(t, member, sw)
+		}
 	case types.Alias:
 		f = func(member types.Member, sw *generator.SnippetWriter) {
 			g.doAlias(t, member, sw)
 		}
 	case types.Pointer:
-		f = g.doPointer
-	case types.Slice:
-		f = g.doSlice
-	case types.Map:
-		f = g.doMap
+		f = func(member types.Member, sw *generator.SnippetWriter) {
+			g.doPointer(t, member, sw)
+		}
+	case types.Slice, types.Array, types.Map, types.Func, types.Chan:
+		f = func(member types.Member, sw *generator.SnippetWriter) {
+			g.doComposite(t, member, sw)
+		}
 	default:
-		klog.Fatalf("Hit an unsupported type %v.%s, kind is %s", t, mt.Name.Name, mt.Kind)
-		//klog.Warningf("Hit an unsupported type %v.%s, kind is %s", t, mt.Name.Name, mt.Kind)
+		g.diags.Errorf(t, mem.Name, "unsupported field type %s, kind is %s, skipped", mt.String(), mt.Kind)
+		return
 	}
 	f(mem, sw)
 }
@@ -425,21 +510,18 @@ type Member struct {
 	namer        string
 	embedded     bool
 	commentLines []string
+	// typeOverride is the type given by field tag, it wins over mType
+	typeOverride string
 }
 
-func NewMember(name string, commentLines []string) *Member {
-	clines := []string{}
-	for _, cl := range commentLines {
-		if len(cl) == 0 {
-			continue
-		}
-		clines = append(clines, fmt.Sprintf("// %s", cl))
-	}
+// NewMember returns a member of name, blank lines of doc separate its
+// paragraphs.
+func NewMember(name string, doc []string) *Member {
 	return &Member{
 		name:         name,
 		jsonTags:     make([]string, 0),
 		namer:        "raw",
-		commentLines: clines,
+		commentLines: commentLines(doc),
 	}
 }
 
@@ -483,9 +565,20 @@ func memberJsonName(m types.Member) string {
 	return info.MarshalName()
 }
 
+// NewModelMember returns the member of model field, it's renamed, retyped
+// or made omitempty by the +onecloud:model-api-gen-field tags.
 func NewModelMember(member types.Member) *Member {
-	m := NewMember(member.Name, member.CommentLines)
-	return m.AddTag(memberJsonName(member))
+	m := NewMember(member.Name, memberDoc(member.CommentLines))
+	m.AddTag(memberJsonName(member))
+	opts := common.GetFieldOptions(member.CommentLines)
+	if opts.Rename != "" {
+		m.Name(opts.Rename)
+	}
+	if opts.OmitEmpty {
+		m.AddTag("omitempty")
+	}
+	m.typeOverride = opts.Type
+	return m
 }
 
 func (m *Member) Do(sw *generator.SnippetWriter, args interface{}) {
@@ -494,7 +587,9 @@ func (m *Member) Do(sw *generator.SnippetWriter, args interface{}) {
 		ret      string
 	)
 	namePart := m.name
-	if m.mType != "" {
+	if m.typeOverride != "" {
+		typePart = m.typeOverride
+	} else if m.mType != "" {
 		typePart = m.mType
 	} else {
 		typePart = fmt.Sprintf("$.type|%s$", m.namer)
@@ -513,27 +608,15 @@ func (m *Member) Do(sw *generator.SnippetWriter, args interface{}) {
 	sw.Do(fmt.Sprintf("%s\n", ret), args)
 }
 
-func (g *apiGen) doSlice(m types.Member, sw *generator.SnippetWriter) {
-	memType := m.Type.Elem
-	point := false
-	if memType.Kind == types.Pointer {
-		memType = memType.Elem
-		point = true
-	}
-	memPkg := memType.Name.Package
-	modelMem := NewModelMember(m)
-	if memPkg == g.outputPackage || memPkg == g.sourcePackage {
-		if point {
-			modelMem.Type(fmt.Sprintf("[]*%s", memType.Name.Name))
-		} else {
-			modelMem.Type(fmt.Sprintf("[]%s", memType.Name.Name))
-		}
+// doComposite generates the member of composite type, the types of source
+// package are replaced recursively and the member is skipped if it can't be
+// generated.
+func (g *apiGen) doComposite(parentType *types.Type, m types.Member, sw *generator.SnippetWriter) {
+	expr, ok := g.typeExpr(parentType, m.Name, m.Type)
+	if !ok {
+		return
 	}
-	modelMem.Do(sw, g.args(m.Type))
-}
-
-func (g *apiGen) doMap(m types.Member, sw *generator.SnippetWriter) {
-	NewModelMember(m).Do(sw, g.args(m.Type))
+	NewModelMember(m).Type(expr).Do(sw, nil)
 }
 
 func (g *apiGen) doBuiltin(m types.Member, sw *generator.SnippetWriter) {
@@ -541,27 +624,27 @@ func (g *apiGen) doBuiltin(m types.Member, sw *generator.SnippetWriter) {
 }
 
 var (
-	TypeMap = map[string]struct {
-		Type     string
-		JSONTags []string
-	}{
+	// TypeMap are the default type mappings keyed by short or full type
+	// name, more are added by the typeMap of mapping file or the
+	// +onecloud:model-api-gen-field tags of types.
+	TypeMap = map[string]TypeMapping{
 		"TriState": {
-			"*bool",
-			[]string{"omitempty"},
+			Type:     "*bool",
+			JSONTags: []string{"omitempty"},
 		},
 	}
 )
 
+// doAlias generates the member of alias type as nested fields do, aliases
+// generated in this file or of other packages keep their names and the others
+// are flattened to the underlying type.
 func (g *apiGen) doAlias(parentType *types.Type, member types.Member, sw *generator.SnippetWriter) {
 	mt := member.Type
-	if ct, ok := TypeMap[mt.Name.Name]; ok {
-		m := NewModelMember(member).AddTag(ct.JSONTags...).Type(ct.Type)
-		m.Do(sw, nil)
+	if !g.inSourcePackage(mt) || g.modelDependTypes.Has(mt.String()) {
+		g.doComposite(parentType, member, sw)
 		return
 	}
-	ut := underlyingType(mt)
-	// NewModelMember(member).Do(sw, g.args(ut))
-	member.Type = ut
+	member.Type = UnderlyingType(mt)
 	g.generateForMember(parentType, member, sw)
 }
 
@@ -578,7 +661,7 @@ func (g *apiGen) doStruct(parentType *types.Type, member types.Member, sw *gener
 		info := reflectutils.ParseFieldJsonInfo(member.Name, reflect.StructTag(member.Tags))
 		_, getTag := info.Tags["get"]
 		_, listTag := info.Tags["list"]
-		if !getTag && !listTag && g.modelTypes.Has(parentType.String()) {
+		if !getTag && !listTag && g.modelTypes.Has(parentType.String()) && !common.GetFieldOptions(member.CommentLines).Include {
 			klog.V(1).Infof("doStruct ignore for memeber %s of %s cause of no get and list tag", mt.String(), parentType.String())
 			return
 		}
@@ -600,10 +683,11 @@ func (g *apiGen) doStruct(parentType *types.Type, member types.Member, sw *gener
 	m.Do(sw, g.args(mt))
 }
 
-func (g *apiGen) doInterface(m types.Member, sw *generator.SnippetWriter) {
+func (g *apiGen) doInterface(parentType *types.Type, m types.Member, sw *generator.SnippetWriter) {
 	// model can't embedded interface
 	if m.Embedded {
-		klog.Fatalf("%s used as embedded interface", m.String())
+		g.diags.Errorf(parentType, m.Name, "%s used as embedded interface, skipped", m.Type.String())
+		return
 	}
 	mem := NewModelMember(m)
 	mem.Do(sw, g.args(m.Type))
@@ -618,36 +702,26 @@ func (g *apiGen) inOutputPackage(t *types.Type) bool {
 }
 
 func (g *apiGen) inJSONUtilsPackage(t *types.Type) bool {
-	ut := underlyingType(t)
+	ut := UnderlyingType(t)
 	if t.Kind == types.Pointer {
 		ut = t.Elem
 	}
 	return strings.Contains(ut.Name.Package, "yunion.io/x/jsonutils")
 }
 
-func (g *apiGen) getPointerSourcePackageName(t *types.Type) string {
-	elem := t.Elem
-	if !g.inSourcePackage(elem) {
-		klog.Fatalf("pointer's elem %q not in package %q", elem.Name.String(), g.sourcePackage)
+func (g *apiGen) doPointer(parentType *types.Type, m types.Member, sw *generator.SnippetWriter) {
+	if !m.Embedded {
+		g.doComposite(parentType, m, sw)
+		return
 	}
-	return fmt.Sprintf("*%s", elem.Name.Name)
-}
-
-func (g *apiGen) doPointer(m types.Member, sw *generator.SnippetWriter) {
-	t := m.Type
 	mem := NewModelMember(m)
 	elem := m.Type.Elem
-	if g.inSourcePackage(elem) {
-		mem.Type(g.getPointerSourcePackageName(t))
-	} else if g.inOutputPackage(elem) {
+	if g.inSourcePackage(elem) || g.inOutputPackage(elem) {
 		mem.Type(fmt.Sprintf("*%s", elem.Name.Name))
 	}
-	if m.Embedded {
-		mem.Embedded()
-		mem.NoTag()
-	}
-	args := g.args(m.Type)
-	mem.Do(sw, args)
+	mem.Embedded()
+	mem.NoTag()
+	mem.Do(sw, g.args(m.Type))
 }
 
 type ResourceModel struct {
@@ -0,0 +1,484 @@
+package generators
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/gengo/generator"
+	"k8s.io/gengo/namer"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+
+	"yunion.io/x/pkg/util/sets"
+	"yunion.io/x/pkg/utils"
+
+	"yunion.io/x/code-generator/pkg/common"
+	"yunion.io/x/code-generator/pkg/swagger-gen/generators"
+)
+
+const clientBase = `
+// Transport sends requests of resource APIs.
+type Transport interface {
+	// Do sends request of method to path with query and body, the value of
+	// responseKey in response body, or the whole body if responseKey is
+	// empty, is unmarshaled into out.
+	Do(ctx context.Context, method, path string, query, body interface{}, responseKey string, out interface{}) error
+	// List sends list request to path with query, the array of dataKey in
+	// response body is unmarshaled into out.
+	List(ctx context.Context, path string, query interface{}, dataKey string, out interface{}) (*ListMeta, error)
+}
+
+// ListMeta is the pagination info of list response.
+type ListMeta struct {
+	Total      int    ` + "`json:\"total\"`" + `
+	Limit      int    ` + "`json:\"limit\"`" + `
+	Offset     int    ` + "`json:\"offset\"`" + `
+	NextMarker string ` + "`json:\"next_marker\"`" + `
+}
+
+// ListResult is the typed list response.
+type ListResult[T any] struct {
+	ListMeta
+	Data []T ` + "`json:\"data\"`" + `
+}
+
+func list[T any](ctx context.Context, t Transport, path string, query interface{}, dataKey string) (*ListResult[T], error) {
+	ret := new(ListResult[T])
+	meta, err := t.List(ctx, path, query, dataKey, &ret.Data)
+	if err != nil {
+		return nil, err
+	}
+	ret.ListMeta = *meta
+	return ret, nil
+}
+
+// resourceClient is embedded by every resource client.
+type resourceClient struct {
+	transport     Transport
+	keyword       string
+	keywordPlural string
+}
+
+func (c resourceClient) path(segments ...string) string {
+	p := "/" + c.keywordPlural
+	for _, s := range segments {
+		p += "/" + url.PathEscape(s)
+	}
+	return p
+}
+
+func (c resourceClient) body(input interface{}) interface{} {
+	return map[string]interface{}{c.keyword: input}
+}
+`
+
+// clientBaseGen generates the transport and list types shared by all
+// resource clients.
+type clientBaseGen struct {
+	generator.DefaultGen
+}
+
+func NewClientBaseGen(sanitizedName string) generator.Generator {
+	return &clientBaseGen{
+		DefaultGen: generator.DefaultGen{
+			OptionalName: sanitizedName,
+			OptionalBody: []byte(clientBase),
+		},
+	}
+}
+
+func (g *clientBaseGen) Filter(c *generator.Context, t *types.Type) bool {
+	return false
+}
+
+func (g *clientBaseGen) Imports(c *generator.Context) []string {
+	return []string{"context", "net/url"}
+}
+
+// clientGen generates a typed REST client of every resource manager in
+// source package.
+type clientGen struct {
+	generator.DefaultGen
+	sourcePackage string
+	clientPackage string
+	imports       namer.ImportTracker
+	modelTypes    sets.String
+	modelManagers map[string]*types.Type
+	clients       []*clientRef
+}
+
+type clientRef struct {
+	field    string
+	typeName string
+	singular string
+	plural   string
+}
+
+func NewClientGen(sanitizedName, sourcePackage, clientPackage string, pkgTypes []*types.Type, detector *common.ModelDetector) generator.Generator {
+	gen := &clientGen{
+		DefaultGen: generator.DefaultGen{
+			OptionalName: sanitizedName,
+		},
+		sourcePackage: sourcePackage,
+		clientPackage: clientPackage,
+		imports:       generator.NewImportTracker(),
+		modelTypes:    sets.NewString(),
+		modelManagers: make(map[string]*types.Type),
+		clients:       make([]*clientRef, 0),
+	}
+	detector.CollectModelManager(sourcePackage, pkgTypes, gen.modelTypes, gen.modelManagers)
+	return gen
+}
+
+func (g *clientGen) Namers(c *generator.Context) namer.NameSystems {
+	return namer.NameSystems{
+		"raw": namer.NewRawNamer(g.clientPackage, g.imports),
+	}
+}
+
+func (g *clientGen) Filter(c *generator.Context, t *types.Type) bool {
+	man, ok := g.modelManagers[t.String()]
+	if !ok {
+		return false
+	}
+	return !generators.IncludeIgnoreTag(man) && !generators.IncludeIgnoreTag(t)
+}
+
+func (g *clientGen) Imports(c *generator.Context) []string {
+	return append(g.imports.ImportLines(), "context")
+}
+
+func camelKeyword(keyword string) string {
+	return utils.Kebab2Camel(keyword, "_")
+}
+
+func (g *clientGen) GenerateType(c *generator.Context, t *types.Type, w io.Writer) error {
+	klog.V(1).Infof("Generating client for model %s", t.String())
+	res := generators.ParseResourceMethods(g.modelManagers[t.String()], t)
+	client := &clientRef{
+		field:    camelKeyword(res.Plural),
+		typeName: camelKeyword(res.Singular) + "Client",
+		singular: res.Singular,
+		plural:   res.Plural,
+	}
+	g.clients = append(g.clients, client)
+
+	sw := generator.NewSnippetWriter(w, c, "$", "$")
+	sw.Do(fmt.Sprintf("// %s is the typed client of %s.\n", client.typeName, client.plural), nil)
+	sw.Do(fmt.Sprintf("type %s struct {\nresourceClient\n}\n\n", client.typeName), nil)
+
+	cw := &clientWriter{sw: sw, client: client}
+	if getM := res.Get; getM != nil {
+		out := listElem(getM.Resutls(0))
+		cw.get(getM, out)
+		cw.list(res.List, out)
+		cw.create(res.Create, out)
+		cw.update(res.Update, out)
+		cw.delete(res.Delete, out)
+	}
+	for _, m := range res.GetSpecs {
+		cw.getSpec(m)
+	}
+	for _, m := range res.Performs {
+		cw.perform(m)
+	}
+	for _, m := range res.GetProperties {
+		cw.getProperty(m)
+	}
+	for _, m := range res.PerformClasses {
+		cw.performClass(m)
+	}
+	return sw.Error()
+}
+
+func (g *clientGen) Finalize(c *generator.Context, w io.Writer) error {
+	sw := generator.NewSnippetWriter(w, c, "$", "$")
+	sw.Do(fmt.Sprintf("// Clientset is the typed client of resources in package %s.\n", filepath.Base(g.sourcePackage)), nil)
+	sw.Do("type Clientset struct {\n", nil)
+	for _, cli := range g.clients {
+		sw.Do(fmt.Sprintf("%s *%s\n", cli.field, cli.typeName), nil)
+	}
+	sw.Do("}\n\n", nil)
+	sw.Do("// NewClientset returns the typed clients sending requests by transport.\n", nil)
+	sw.Do("func NewClientset(transport Transport) *Clientset {\n", nil)
+	sw.Do("return &Clientset{\n", nil)
+	for _, cli := range g.clients {
+		sw.Do(fmt.Sprintf("%s: &%s{resourceClient{transport, %q, %q}},\n", cli.field, cli.typeName, cli.singular, cli.plural), nil)
+	}
+	sw.Do("}\n}\n", nil)
+	return sw.Error()
+}
+
+// listElem returns element type of FetchCustomizeColumns result.
+func listElem(t *types.Type) *types.Type {
+	if t.Kind == types.Slice {
+		return t.Elem
+	}
+	return t
+}
+
+// clientType is how an input or output type is used in client method
+// signature, structs are passed by pointer.
+type clientType struct {
+	t       *types.Type
+	pointer bool
+}
+
+func newClientType(t *types.Type) *clientType {
+	vt := generators.GetValidType(t)
+	if vt == nil || vt.IsAnonymousStruct() {
+		return nil
+	}
+	return &clientType{
+		t:       vt,
+		pointer: vt.Kind == types.Struct,
+	}
+}
+
+// placeholder returns the snippet of the type argument named key.
+func (ct *clientType) placeholder(key string) string {
+	if ct.pointer {
+		return fmt.Sprintf("*$.%s|raw$", key)
+	}
+	return fmt.Sprintf("$.%s|raw$", key)
+}
+
+type clientWriter struct {
+	sw     *generator.SnippetWriter
+	client *clientRef
+}
+
+type clientMethod struct {
+	name    string
+	doc     string
+	action  string
+	path    string
+	withId  bool
+	query   *clientType
+	body    *clientType
+	wrapKey bool
+	output  *clientType
+	// responseKey is the expression of the key wrapping the output in
+	// response body, the output isn't wrapped if it's emptyResponseKey
+	responseKey string
+}
+
+const (
+	keywordResponseKey = "c.keyword"
+	emptyResponseKey   = `""`
+)
+
+func methodDoc(m *generators.Method, defDoc string) string {
+	lines := make([]string, 0)
+	for _, l := range m.Method().CommentLines {
+		l = strings.TrimSpace(l)
+		if len(l) == 0 || strings.HasPrefix(l, "+") {
+			continue
+		}
+		lines = append(lines, l)
+	}
+	if len(lines) == 0 {
+		return defDoc
+	}
+	return strings.Join(lines, "\n// ")
+}
+
+func (w *clientWriter) do(cm *clientMethod) {
+	args := generator.Args{}
+	params := []string{"ctx context.Context"}
+	if cm.withId {
+		params = append(params, "id string")
+	}
+	query := "nil"
+	if cm.query != nil {
+		args["query"] = cm.query.t
+		params = append(params, "query "+cm.query.placeholder("query"))
+		query = "query"
+	}
+	body := "nil"
+	if cm.body != nil {
+		args["input"] = cm.body.t
+		params = append(params, "input "+cm.body.placeholder("input"))
+		body = "input"
+		if cm.wrapKey {
+			body = "c.body(input)"
+		}
+	}
+	results := "error"
+	if cm.output != nil {
+		args["output"] = cm.output.t
+		results = fmt.Sprintf("(%s, error)", cm.output.placeholder("output"))
+	}
+	pathArgs := make([]string, 0)
+	if cm.withId {
+		pathArgs = append(pathArgs, "id")
+	}
+	if cm.path != "" {
+		pathArgs = append(pathArgs, fmt.Sprintf("%q", cm.path))
+	}
+	// docs and paths may contain $, they are passed as args instead of
+	// being part of the templates
+	args["name"] = cm.name
+	args["doc"] = cm.doc
+	args["client"] = w.client.typeName
+	args["action"] = fmt.Sprintf("%q", cm.action)
+	args["path"] = fmt.Sprintf("c.path(%s)", strings.Join(pathArgs, ", "))
+	args["queryArg"] = query
+	args["bodyArg"] = body
+	args["responseKey"] = cm.responseKey
+
+	sw := w.sw
+	sw.Do("// $.name$ $.doc$\n", args)
+	sw.Do("func (c *$.client$) $.name$("+strings.Join(params, ", ")+") "+results+" {\n", args)
+	if cm.output == nil {
+		sw.Do("return c.transport.Do(ctx, $.action$, $.path$, $.queryArg$, $.bodyArg$, \"\", nil)\n}\n\n", args)
+		return
+	}
+	args["out"], args["zero"] = "out", "nil"
+	if cm.output.pointer {
+		sw.Do("out := new($.output|raw$)\n", args)
+	} else {
+		sw.Do("var out $.output|raw$\n", args)
+		args["out"], args["zero"] = "&out", "out"
+	}
+	sw.Do("if err := c.transport.Do(ctx, $.action$, $.path$, $.queryArg$, $.bodyArg$, $.responseKey$, $.out$); err != nil {\n", args)
+	sw.Do("return $.zero$, err\n}\nreturn out, nil\n}\n\n", args)
+}
+
+func (w *clientWriter) get(m *generators.Method, out *types.Type) {
+	w.do(&clientMethod{
+		name:        "Get",
+		doc:         fmt.Sprintf("gets the details of %s by id or name.", w.client.singular),
+		action:      "GET",
+		withId:      true,
+		query:       newClientType(m.Params(2)),
+		output:      newClientType(out),
+		responseKey: keywordResponseKey,
+	})
+}
+
+func (w *clientWriter) list(m *generators.Method, out *types.Type) {
+	if m == nil {
+		return
+	}
+	outType := newClientType(out)
+	if outType == nil {
+		return
+	}
+	query := newClientType(m.Params(3))
+	args := generator.Args{"output": outType.t}
+	queryParam := ""
+	queryArg := "nil"
+	if query != nil {
+		args["query"] = query.t
+		queryParam = ", query " + query.placeholder("query")
+		queryArg = "query"
+	}
+	w.sw.Do(fmt.Sprintf("// List lists %s.\n", w.client.plural), nil)
+	w.sw.Do(fmt.Sprintf("func (c *%s) List(ctx context.Context%s) (*ListResult[$.output|raw$], error) {\n", w.client.typeName, queryParam), args)
+	w.sw.Do(fmt.Sprintf("return list[$.output|raw$](ctx, c.transport, c.path(), %s, c.keywordPlural)\n}\n\n", queryArg), args)
+}
+
+func (w *clientWriter) create(m *generators.Method, out *types.Type) {
+	if m == nil {
+		return
+	}
+	w.do(&clientMethod{
+		name:        "Create",
+		doc:         fmt.Sprintf("creates a %s.", w.client.singular),
+		action:      "POST",
+		body:        newClientType(m.Params(4)),
+		wrapKey:     true,
+		output:      newClientType(out),
+		responseKey: keywordResponseKey,
+	})
+}
+
+func (w *clientWriter) update(m *generators.Method, out *types.Type) {
+	if m == nil {
+		return
+	}
+	w.do(&clientMethod{
+		name:        "Update",
+		doc:         fmt.Sprintf("updates the %s by id or name.", w.client.singular),
+		action:      "PUT",
+		withId:      true,
+		body:        newClientType(m.Params(3)),
+		wrapKey:     true,
+		output:      newClientType(out),
+		responseKey: keywordResponseKey,
+	})
+}
+
+func (w *clientWriter) delete(m *generators.Method, out *types.Type) {
+	if m == nil {
+		return
+	}
+	w.do(&clientMethod{
+		name:        "Delete",
+		doc:         fmt.Sprintf("deletes the %s by id or name.", w.client.singular),
+		action:      "DELETE",
+		withId:      true,
+		query:       newClientType(m.Params(2)),
+		output:      newClientType(out),
+		responseKey: keywordResponseKey,
+	})
+}
+
+func actionPath(m *generators.Method, prefix string) string {
+	return utils.CamelSplit(strings.TrimPrefix(m.Name(), prefix), "-")
+}
+
+func (w *clientWriter) getSpec(m *generators.Method) {
+	w.do(&clientMethod{
+		name:        m.Name(),
+		doc:         methodDoc(m, fmt.Sprintf("gets %s of the %s.", actionPath(m, generators.GetSpec), w.client.singular)),
+		action:      "GET",
+		path:        actionPath(m, generators.GetSpec),
+		withId:      true,
+		query:       newClientType(m.Params(2)),
+		output:      newClientType(m.Resutls(0)),
+		responseKey: keywordResponseKey,
+	})
+}
+
+func (w *clientWriter) perform(m *generators.Method) {
+	w.do(&clientMethod{
+		name:        m.Name(),
+		doc:         methodDoc(m, fmt.Sprintf("performs %s on the %s.", actionPath(m, generators.Perform), w.client.singular)),
+		action:      "POST",
+		path:        actionPath(m, generators.Perform),
+		withId:      true,
+		body:        newClientType(m.Params(3)),
+		wrapKey:     true,
+		output:      newClientType(m.Resutls(0)),
+		responseKey: keywordResponseKey,
+	})
+}
+
+func (w *clientWriter) getProperty(m *generators.Method) {
+	w.do(&clientMethod{
+		name:        m.Name(),
+		doc:         methodDoc(m, fmt.Sprintf("gets %s of %s.", actionPath(m, generators.GetProperty), w.client.plural)),
+		action:      "GET",
+		path:        actionPath(m, generators.GetProperty),
+		query:       newClientType(m.Params(2)),
+		output:      newClientType(m.Resutls(0)),
+		responseKey: emptyResponseKey,
+	})
+}
+
+func (w *clientWriter) performClass(m *generators.Method) {
+	w.do(&clientMethod{
+		name:        "Class" + m.Name(),
+		doc:         methodDoc(m, fmt.Sprintf("performs %s on %s.", actionPath(m, generators.Perform), w.client.plural)),
+		action:      "POST",
+		path:        actionPath(m, generators.Perform),
+		body:        newClientType(m.Params(3)),
+		wrapKey:     true,
+		output:      newClientType(m.Resutls(0)),
+		responseKey: emptyResponseKey,
+	})
+}
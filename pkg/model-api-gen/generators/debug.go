@@ -0,0 +1,79 @@
+package generators
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"k8s.io/gengo/args"
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/code-generator/pkg/common"
+	apiargs "yunion.io/x/code-generator/pkg/model-api-gen/args"
+)
+
+// DebugModels parses the input packages and prints why each exported
+// struct is or isn't classified as a resource model, and whether the
+// managers are accepted.
+func DebugModels(arguments *args.GeneratorArgs, w io.Writer) error {
+	customArgs := apiargs.GetCustomArgs(arguments)
+	mapping, err := LoadMappingConfig(customArgs.MappingFile)
+	if err != nil {
+		return err
+	}
+	b, err := arguments.NewBuilder()
+	if err != nil {
+		return err
+	}
+	u, err := b.FindTypes()
+	if err != nil {
+		return err
+	}
+	detector := mapping.NewModelDetector(u)
+
+	pkgs := make([]string, 0)
+	for _, pkg := range u {
+		if arguments.InputIncludes(pkg) {
+			pkgs = append(pkgs, pkg.Path)
+		}
+	}
+	sort.Strings(pkgs)
+	for _, path := range pkgs {
+		debugPackageModels(detector, u[path], w)
+	}
+	return nil
+}
+
+func debugPackageModels(detector *common.ModelDetector, pkg *types.Package, w io.Writer) {
+	fmt.Fprintf(w, "%s\n", pkg.Path)
+	names := make([]string, 0, len(pkg.Types))
+	for name, t := range pkg.Types {
+		if t.Kind == types.Struct && !common.IsPrivateStruct(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	models := make(map[string]bool)
+	for _, name := range names {
+		c := detector.Classify(pkg.Types[name])
+		models[c.Type] = c.IsModel
+		fmt.Fprintf(w, "  %s\n", c.String())
+	}
+	for _, name := range names {
+		t := pkg.Types[name]
+		if !strings.HasSuffix(name, "Manager") {
+			continue
+		}
+		modelName := strings.TrimSuffix(t.String(), "Manager")
+		if !models[modelName] {
+			continue
+		}
+		ok, reason := detector.IsModelManager(t)
+		verdict := "manager"
+		if !ok {
+			verdict = "not manager"
+		}
+		fmt.Fprintf(w, "  %s: %s of %s, %s\n", t.String(), verdict, modelName, reason)
+	}
+}
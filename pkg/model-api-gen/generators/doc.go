@@ -0,0 +1,113 @@
+package generators
+
+import (
+	"strconv"
+	"strings"
+
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+)
+
+const (
+	// tagDocName controls whether the doc comment of a model type or field
+	// is copied to the apis package, only its Deprecated paragraphs are kept
+	// with +onecloud:model-api-gen-doc=false
+	tagDocName = "onecloud:model-api-gen-doc"
+
+	deprecatedPrefix = "Deprecated:"
+)
+
+// docEnabled returns false if the doc of comment lines is suppressed by
+// +onecloud:model-api-gen-doc=false.
+func docEnabled(comments []string) bool {
+	vals := types.ExtractCommentTags("+", comments)[tagDocName]
+	if len(vals) == 0 {
+		return true
+	}
+	enabled, err := strconv.ParseBool(vals[0])
+	if err != nil {
+		klog.Warningf("invalid value %q of +%s, expect true or false", vals[0], tagDocName)
+		return true
+	}
+	return enabled
+}
+
+// splitDoc splits comment lines into paragraphs separated by blank lines,
+// the Deprecated paragraphs are returned separately. The tags of
+// model-api-gen are dropped, other comment tags are dropped too unless
+// keepTags.
+func splitDoc(comments []string, keepTags bool) (doc, deprecated [][]string) {
+	var para []string
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		if strings.HasPrefix(para[0], deprecatedPrefix) {
+			deprecated = append(deprecated, para)
+		} else {
+			doc = append(doc, para)
+		}
+		para = nil
+	}
+	for _, l := range comments {
+		text := strings.TrimSpace(l)
+		if text == "" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(text, "+") && (!keepTags || strings.HasPrefix(text[1:], tagName)) {
+			continue
+		}
+		para = append(para, strings.TrimRight(l, " \t"))
+	}
+	flush()
+	return doc, deprecated
+}
+
+// joinParagraphs returns the lines of paragraphs separated by blank lines.
+func joinParagraphs(paras ...[]string) []string {
+	lines := make([]string, 0)
+	for i, p := range paras {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, p...)
+	}
+	return lines
+}
+
+// memberDoc returns the comment lines of a field copied to the apis package,
+// only the Deprecated paragraphs are kept if the doc is suppressed. Comment
+// tags are kept for the generators reading apis types, e.g. swagger-gen.
+func memberDoc(comments []string) []string {
+	doc, deprecated := splitDoc(comments, true)
+	if !docEnabled(comments) {
+		doc = nil
+	}
+	return joinParagraphs(append(doc, deprecated...)...)
+}
+
+// typeDoc returns the comment lines of type t copied to the apis package:
+// its doc, the note of the source type and its Deprecated paragraphs.
+func typeDoc(t *types.Type) []string {
+	doc, deprecated := splitDoc(t.CommentLines, false)
+	if !docEnabled(t.CommentLines) {
+		doc = nil
+	}
+	note := []string{t.Name.Name + " is an autogenerated struct via " + t.Name.String() + "."}
+	paras := append(doc, note)
+	return joinParagraphs(append(paras, deprecated...)...)
+}
+
+// commentLines renders lines as go comments.
+func commentLines(lines []string) []string {
+	ret := make([]string, len(lines))
+	for i, l := range lines {
+		if l == "" {
+			ret[i] = "//"
+		} else {
+			ret[i] = "// " + l
+		}
+	}
+	return ret
+}
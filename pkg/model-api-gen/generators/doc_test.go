@@ -0,0 +1,67 @@
+package generators
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/gengo/types"
+)
+
+func TestMemberDoc(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments []string
+		want     []string
+	}{
+		{
+			name:     "paragraphs and tags are kept",
+			comments: []string{"Boot order", "+onecloud:swagger-gen-field-required", "", "", "Deprecated: use boot index", ""},
+			want:     []string{"Boot order", "+onecloud:swagger-gen-field-required", "", "Deprecated: use boot index"},
+		},
+		{
+			name:     "doc suppressed",
+			comments: []string{"+onecloud:model-api-gen-doc=false", "Cached by the scheduler", "", "Deprecated: use boot index"},
+			want:     []string{"Deprecated: use boot index"},
+		},
+		{
+			name:     "doc enabled explicitly",
+			comments: []string{"+onecloud:model-api-gen-doc=true", "Boot order"},
+			want:     []string{"Boot order"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := memberDoc(tt.comments); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("memberDoc() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypeDoc(t *testing.T) {
+	typ := &types.Type{
+		Name: types.Name{Package: "yunion.io/x/onecloud/pkg/compute/models", Name: "SDisk"},
+		CommentLines: []string{
+			"SDisk is a disk",
+			"+onecloud:model-api-gen",
+			"",
+			"Deprecated: listed by disks",
+		},
+	}
+	want := []string{
+		"// SDisk is a disk",
+		"//",
+		"// SDisk is an autogenerated struct via yunion.io/x/onecloud/pkg/compute/models.SDisk.",
+		"//",
+		"// Deprecated: listed by disks",
+	}
+	if got := commentLines(typeDoc(typ)); !reflect.DeepEqual(got, want) {
+		t.Errorf("typeDoc() = %q, want %q", got, want)
+	}
+
+	typ.CommentLines = append(typ.CommentLines, "+onecloud:model-api-gen-doc=false")
+	want = []string{want[2], want[3], want[4]}
+	if got := commentLines(typeDoc(typ)); !reflect.DeepEqual(got, want) {
+		t.Errorf("typeDoc() of suppressed doc = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+package generators
+
+import (
+	"fmt"
+
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/code-generator/pkg/common"
+)
+
+// TypeMapping replaces the fields of a type by Type in apis types, the
+// JSONTags are added to their json tags, e.g. *bool and omitempty of
+// TriState.
+type TypeMapping struct {
+	Type     string   `yaml:"type"`
+	JSONTags []string `yaml:"jsonTags"`
+}
+
+// typeMapping returns the mapping of type options, nil if type isn't
+// replaced.
+func typeMapping(o *common.FieldOptions) *TypeMapping {
+	if o.Type == "" {
+		return nil
+	}
+	m := &TypeMapping{Type: o.Type}
+	if o.OmitEmpty {
+		m.JSONTags = []string{"omitempty"}
+	}
+	return m
+}
+
+// NewTypeMap returns the type mappings of apiGen: the global TypeMap, the
+// ones of mapping config and the type tags of pkgTypes, keys are the short
+// or full names of types. The other generators use it to describe the
+// fields as model-api-gen generates them.
+func NewTypeMap(mapping *MappingConfig, pkgTypes []*types.Type) map[string]TypeMapping {
+	ret := make(map[string]TypeMapping)
+	for name, m := range TypeMap {
+		ret[name] = m
+	}
+	for name, m := range mapping.TypeMap {
+		ret[name] = m
+	}
+	for _, t := range pkgTypes {
+		if t.Kind != types.Alias && t.Kind != types.Struct {
+			continue
+		}
+		if m := typeMapping(common.GetFieldOptions(t.CommentLines)); m != nil {
+			ret[t.String()] = *m
+		}
+	}
+	return ret
+}
+
+// LookupTypeMap returns the mapping of named type t in typeMap.
+func LookupTypeMap(typeMap map[string]TypeMapping, t *types.Type) (TypeMapping, bool) {
+	if m, ok := typeMap[t.String()]; ok {
+		return m, true
+	}
+	m, ok := typeMap[t.Name.Name]
+	return m, ok
+}
+
+// lookupTypeMap returns the mapping of named type t.
+func (g *apiGen) lookupTypeMap(t *types.Type) (TypeMapping, bool) {
+	return LookupTypeMap(g.typeMap, t)
+}
+
+// reportFieldOptions reports the invalid field tags of member m of t.
+func (g *apiGen) reportFieldOptions(t *types.Type, m types.Member) {
+	opts, errs := common.ParseFieldOptions(m.CommentLines)
+	if m.Embedded && opts.Rename != "" {
+		errs = append(errs, fmt.Errorf("+%s=%s%s: embedded field can't be renamed", common.TagFieldOptions, "rename:", opts.Rename))
+	}
+	for _, err := range errs {
+		g.diags.Errorf(t, m.Name, "%v", err)
+	}
+}
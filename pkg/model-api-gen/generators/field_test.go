@@ -0,0 +1,19 @@
+package generators
+
+import (
+	"testing"
+)
+
+func TestNewTypeMap(t *testing.T) {
+	mapping := NewDefaultMappingConfig()
+	mapping.TypeMap = map[string]TypeMapping{
+		"example.com/svc/pkg/db.Flag": {Type: "*bool"},
+	}
+	typeMap := NewTypeMap(mapping, nil)
+	if _, ok := typeMap["TriState"]; !ok {
+		t.Errorf("default TriState mapping is missing")
+	}
+	if m := typeMap["example.com/svc/pkg/db.Flag"]; m.Type != "*bool" {
+		t.Errorf("mapping of Flag = %+v", m)
+	}
+}
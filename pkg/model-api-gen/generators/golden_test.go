@@ -0,0 +1,44 @@
+package generators
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"yunion.io/x/code-generator/pkg/common/golden"
+	apiargs "yunion.io/x/code-generator/pkg/model-api-gen/args"
+)
+
+func TestGolden(t *testing.T) {
+	arguments, customArgs := apiargs.NewDefaults()
+	arguments.InputDirs = []string{"yunion.io/x/onecloud/pkg/compute/models"}
+	arguments.OutputPackagePath = "yunion.io/x/onecloud/pkg/apis/compute"
+	customArgs.ClientPackage = "yunion.io/x/onecloud/pkg/mcclient/typed/compute"
+	customArgs.WithHelpers = apiargs.Helpers
+	dir := golden.Run(t, arguments, NameSystems(), DefaultNameSystem(), Packages)
+	golden.Compare(t, dir, filepath.Join("testdata", "golden"))
+	for _, d := range customArgs.Diagnostics.Diagnostics() {
+		t.Errorf("unexpected diagnostic %s", d)
+	}
+}
+
+// TestGoldenClientResponseKeys checks the responses of properties and class
+// actions aren't unwrapped by the resource keyword.
+func TestGoldenClientResponseKeys(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "golden", "yunion.io/x/onecloud/pkg/mcclient/typed/compute/zz_generated.client.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := string(data)
+	for _, call := range []string{
+		`c.transport.Do(ctx, "GET", c.path(id), nil, nil, c.keyword, out)`,
+		`c.transport.Do(ctx, "POST", c.path(id, "start"), nil, c.body(input), c.keyword, out)`,
+		`c.transport.Do(ctx, "GET", c.path("statistics"), query, nil, "", out)`,
+		`c.transport.Do(ctx, "POST", c.path("batch-start"), nil, c.body(input), "", out)`,
+	} {
+		if !strings.Contains(client, call) {
+			t.Errorf("client doesn't call %s", call)
+		}
+	}
+}
@@ -0,0 +1,650 @@
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"k8s.io/gengo/generator"
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/pkg/util/sets"
+
+	apiargs "yunion.io/x/code-generator/pkg/model-api-gen/args"
+)
+
+// redactedValue replaces the sensitive fields printed by String.
+const redactedValue = "******"
+
+// sensitiveWords mark a string field as sensitive if its json name contains
+// any of them.
+var sensitiveWords = []string{"password", "passwd", "secret", "token", "credential", "private_key"}
+
+// valueKind is how a value of api type is copied and compared.
+type valueKind int
+
+const (
+	// kindValue is copied by assignment and compared by ==
+	kindValue valueKind = iota
+	// kindTime is time.Time, compared by its Equal
+	kindTime
+	// kindStruct has generated DeepCopyInto and Equal of pointer receiver
+	kindStruct
+	// kindNamed is a named map, slice or array with generated DeepCopyInto
+	// and Equal of value receiver
+	kindNamed
+	// kindShallow is copied by assignment and compared by reflect.DeepEqual,
+	// e.g. interfaces and types of other packages without helpers
+	kindShallow
+	kindPointer
+	kindSlice
+	kindArray
+	kindMap
+)
+
+// helpersGen generates the companion methods of the api types apiGen
+// generated, it works on the declarations apiGen wrote so renamed, retyped
+// and skipped fields are honoured.
+type helpersGen struct {
+	generator.DefaultGen
+	api     *apiGen
+	helpers sets.String
+
+	universe types.Universe
+	// specs are the generated declarations keyed by type name
+	specs map[string]*ast.TypeSpec
+	// imports are the import paths of the generated declarations keyed by
+	// package name
+	imports map[string]string
+}
+
+// NewHelpersGen returns the generator of companion methods of the api types
+// generated by api, helpers are the values of apiargs.Helpers.
+func NewHelpersGen(sanitizedName string, api generator.Generator, helpers []string) generator.Generator {
+	return &helpersGen{
+		DefaultGen: generator.DefaultGen{
+			OptionalName: sanitizedName,
+		},
+		api:     api.(*apiGen),
+		helpers: sets.NewString(helpers...),
+	}
+}
+
+func (g *helpersGen) Filter(c *generator.Context, t *types.Type) bool {
+	return g.api.Filter(c, t)
+}
+
+func (g *helpersGen) Imports(c *generator.Context) []string {
+	// unused imports are dropped when the file is formatted
+	lines := g.api.Imports(c)
+	if g.helpers.Has(apiargs.HelperString) {
+		lines = append(lines, `"fmt"`, `"strings"`)
+	}
+	return append(lines, `"reflect"`)
+}
+
+// Init parses the declarations apiGen generated, apiGen runs before as the
+// generators of a package are executed in order.
+func (g *helpersGen) Init(c *generator.Context, w io.Writer) error {
+	g.universe = c.Universe
+	g.specs = make(map[string]*ast.TypeSpec)
+	g.imports = make(map[string]string)
+	for _, line := range g.api.Imports(c) {
+		// lines are `name "path"`, `"path"` or path
+		parts := strings.Fields(line)
+		path := strings.Trim(parts[len(parts)-1], `"`)
+		name := filepath.Base(path)
+		if len(parts) == 2 {
+			name = parts[0]
+		}
+		g.imports[name] = path
+	}
+
+	src := bytes.NewBufferString("package apis\n")
+	for _, decl := range g.api.decls {
+		src.Write(decl)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "", src.Bytes(), 0)
+	if err != nil {
+		return fmt.Errorf("parse generated api types: %v", err)
+	}
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok {
+				g.specs[ts.Name.Name] = ts
+			}
+		}
+	}
+	return nil
+}
+
+func (g *helpersGen) GenerateType(c *generator.Context, t *types.Type, w io.Writer) error {
+	spec, ok := g.specs[t.Name.Name]
+	if !ok {
+		// skipped by apiGen
+		return nil
+	}
+	b := &bytes.Buffer{}
+	name := spec.Name.Name
+	if st, ok := spec.Type.(*ast.StructType); ok {
+		if g.helpers.Has(apiargs.HelperDeepCopy) {
+			g.structDeepCopy(b, name, st)
+		}
+		if g.helpers.Has(apiargs.HelperEqual) {
+			g.structEqual(b, name, st)
+		}
+		if g.helpers.Has(apiargs.HelperString) {
+			g.structString(b, name, st)
+		}
+	} else if g.identKind(name) == kindNamed {
+		if g.helpers.Has(apiargs.HelperDeepCopy) {
+			g.namedDeepCopy(b, name, spec.Type)
+		}
+		if g.helpers.Has(apiargs.HelperEqual) {
+			g.namedEqual(b, name, spec.Type)
+		}
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func (g *helpersGen) structDeepCopy(b *bytes.Buffer, name string, st *ast.StructType) {
+	fmt.Fprintf(b, "// DeepCopyInto copies the receiver into out, in must be non-nil.\n")
+	fmt.Fprintf(b, "func (in *%s) DeepCopyInto(out *%s) {\n", name, name)
+	fmt.Fprintf(b, "*out = *in\n")
+	for _, f := range st.Fields.List {
+		if !g.needsCopy(f.Type) {
+			continue
+		}
+		for _, n := range fieldNames(f) {
+			g.copyInto(b, "out."+n, "in."+n, f.Type, 0)
+		}
+	}
+	fmt.Fprintf(b, "}\n\n")
+	fmt.Fprintf(b, "// DeepCopy returns a deep copy of the receiver.\n")
+	fmt.Fprintf(b, "func (in *%s) DeepCopy() *%s {\n", name, name)
+	fmt.Fprintf(b, "if in == nil {\nreturn nil\n}\n")
+	fmt.Fprintf(b, "out := new(%s)\nin.DeepCopyInto(out)\nreturn out\n}\n\n", name)
+}
+
+func (g *helpersGen) namedDeepCopy(b *bytes.Buffer, name string, e ast.Expr) {
+	fmt.Fprintf(b, "// DeepCopyInto copies the receiver into out.\n")
+	fmt.Fprintf(b, "func (in %s) DeepCopyInto(out *%s) {\n", name, name)
+	if g.kindOf(e) == kindArray {
+		fmt.Fprintf(b, "*out = in\n")
+	}
+	g.copyInto(b, "*out", "in", e, 0)
+	fmt.Fprintf(b, "}\n\n")
+	fmt.Fprintf(b, "// DeepCopy returns a deep copy of the receiver.\n")
+	fmt.Fprintf(b, "func (in %s) DeepCopy() %s {\n", name, name)
+	if g.kindOf(e) != kindArray {
+		fmt.Fprintf(b, "if in == nil {\nreturn nil\n}\n")
+	}
+	fmt.Fprintf(b, "out := new(%s)\nin.DeepCopyInto(out)\nreturn *out\n}\n\n", name)
+}
+
+func (g *helpersGen) structEqual(b *bytes.Buffer, name string, st *ast.StructType) {
+	fmt.Fprintf(b, "// Equal reports whether the receiver and other are deeply equal, nil and\n")
+	fmt.Fprintf(b, "// empty slices or maps are equal.\n")
+	fmt.Fprintf(b, "func (in *%s) Equal(other *%s) bool {\n", name, name)
+	fmt.Fprintf(b, "if in == nil || other == nil {\nreturn in == other\n}\n")
+	for _, f := range st.Fields.List {
+		for _, n := range fieldNames(f) {
+			g.equal(b, "in."+n, "other."+n, f.Type, 0)
+		}
+	}
+	fmt.Fprintf(b, "return true\n}\n\n")
+}
+
+func (g *helpersGen) namedEqual(b *bytes.Buffer, name string, e ast.Expr) {
+	fmt.Fprintf(b, "// Equal reports whether the receiver and other are deeply equal, nil and\n")
+	fmt.Fprintf(b, "// empty slices or maps are equal.\n")
+	fmt.Fprintf(b, "func (in %s) Equal(other %s) bool {\n", name, name)
+	g.equal(b, "in", "other", e, 0)
+	fmt.Fprintf(b, "return true\n}\n\n")
+}
+
+// structString writes String of struct st printing the fields as %+v does,
+// except the sensitive strings are redacted and the structs nested in
+// fields are printed by their String, as fmt never calls String of pointer
+// receiver on nested values.
+func (g *helpersGen) structString(b *bytes.Buffer, name string, st *ast.StructType) {
+	fmt.Fprintf(b, "// String returns the fields of the receiver, the sensitive ones are\n")
+	fmt.Fprintf(b, "// redacted.\n")
+	fmt.Fprintf(b, "func (in *%s) String() string {\n", name)
+	fmt.Fprintf(b, "if in == nil {\nreturn \"<nil>\"\n}\n")
+	fmt.Fprintf(b, "b := &strings.Builder{}\n")
+	sep := "{"
+	for _, f := range st.Fields.List {
+		for _, n := range fieldNames(f) {
+			field := "in." + n
+			switch {
+			case len(f.Names) > 0 && isStringIdent(f.Type) && isSensitive(n, f.Tag):
+				fmt.Fprintf(b, "if %s != \"\" {\nb.WriteString(%q)\n} else {\nb.WriteString(%q)\n}\n", field, sep+n+":"+redactedValue, sep+n+":")
+			case g.kindOf(f.Type) == kindStruct || g.kindOf(f.Type) == kindPointer && g.needsString(f.Type):
+				fmt.Fprintf(b, "fmt.Fprintf(b, %q, %s.String())\n", sep+n+":%s", field)
+			case g.needsString(f.Type):
+				str := strings.ToLower(n[:1]) + n[1:] + "Str"
+				g.stringInto(b, str, ":=", field, f.Type, 0)
+				fmt.Fprintf(b, "fmt.Fprintf(b, %q, %s)\n", sep+n+":%v", str)
+			default:
+				fmt.Fprintf(b, "fmt.Fprintf(b, %q, %s)\n", sep+n+":%+v", field)
+			}
+			sep = " "
+		}
+	}
+	if sep == "{" {
+		fmt.Fprintf(b, "b.WriteString(\"{\")\n")
+	}
+	fmt.Fprintf(b, "b.WriteString(\"}\")\n")
+	fmt.Fprintf(b, "return b.String()\n}\n\n")
+}
+
+func isStringIdent(e ast.Expr) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == "string"
+}
+
+// needsString reports whether values of type e hold structs, they are
+// printed by String so that their sensitive fields are redacted.
+func (g *helpersGen) needsString(e ast.Expr) bool {
+	switch g.kindOf(e) {
+	case kindStruct:
+		return true
+	case kindPointer:
+		return g.kindOf(e.(*ast.StarExpr).X) == kindStruct
+	case kindSlice, kindArray:
+		return g.needsString(e.(*ast.ArrayType).Elt)
+	case kindMap:
+		return g.needsString(e.(*ast.MapType).Value)
+	case kindNamed:
+		if spec := g.localSpec(e); spec != nil {
+			return g.needsString(spec.Type)
+		}
+	}
+	return false
+}
+
+// localSpec returns the generated declaration of named type e, nil if it
+// isn't generated in the package.
+func (g *helpersGen) localSpec(e ast.Expr) *ast.TypeSpec {
+	if p, ok := e.(*ast.ParenExpr); ok {
+		return g.localSpec(p.X)
+	}
+	if id, ok := e.(*ast.Ident); ok {
+		return g.specs[id.Name]
+	}
+	return nil
+}
+
+// stringType returns the type holding the printed values of type e, whose
+// %v is the %+v of e with the structs printed by String.
+func (g *helpersGen) stringType(e ast.Expr) string {
+	switch g.kindOf(e) {
+	case kindSlice, kindArray:
+		return "[]" + g.stringType(e.(*ast.ArrayType).Elt)
+	case kindMap:
+		m := e.(*ast.MapType)
+		return fmt.Sprintf("map[%s]%s", gotypes.ExprString(m.Key), g.stringType(m.Value))
+	case kindNamed:
+		return g.stringType(g.localSpec(e).Type)
+	}
+	return "string"
+}
+
+// stringInto writes the statements converting src of type e into dst of
+// stringType(e), op is = or := declaring dst.
+func (g *helpersGen) stringInto(b *bytes.Buffer, dst, op, src string, e ast.Expr, depth int) {
+	switch g.kindOf(e) {
+	case kindSlice, kindArray:
+		i := loopVar("i", depth)
+		fmt.Fprintf(b, "%s %s make(%s, len(%s))\n", dst, op, g.stringType(e), src)
+		fmt.Fprintf(b, "for %s := range %s {\n", i, src)
+		g.stringInto(b, index(dst, i), "=", index(src, i), e.(*ast.ArrayType).Elt, depth+1)
+		fmt.Fprintf(b, "}\n")
+	case kindMap:
+		elem := e.(*ast.MapType).Value
+		key, val := loopVar("key", depth), loopVar("val", depth)
+		fmt.Fprintf(b, "%s %s make(%s, len(%s))\n", dst, op, g.stringType(e), src)
+		fmt.Fprintf(b, "for %s, %s := range %s {\n", key, val, src)
+		// map elements aren't addressable
+		outVal := loopVar("outVal", depth)
+		g.stringInto(b, outVal, ":=", val, elem, depth+1)
+		fmt.Fprintf(b, "%s[%s] = %s\n", operand(dst), key, outVal)
+		fmt.Fprintf(b, "}\n")
+	case kindNamed:
+		g.stringInto(b, dst, op, src, g.localSpec(e).Type, depth)
+	default:
+		fmt.Fprintf(b, "%s %s %s.String()\n", dst, op, operand(src))
+	}
+}
+
+// isSensitive reports the field is sensitive by its json name, the go name
+// is used if it has no json tag.
+func isSensitive(name string, tag *ast.BasicLit) bool {
+	jsonName := name
+	if tag != nil {
+		if val, err := strconv.Unquote(tag.Value); err == nil {
+			if n := strings.Split(reflect.StructTag(val).Get("json"), ",")[0]; n != "" {
+				jsonName = n
+			}
+		}
+	}
+	jsonName = strings.ToLower(jsonName)
+	for _, w := range sensitiveWords {
+		if strings.Contains(jsonName, w) || strings.Contains(jsonName, strings.Replace(w, "_", "", -1)) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldNames returns the names of field f, the type name if it's embedded.
+func fieldNames(f *ast.Field) []string {
+	if len(f.Names) == 0 {
+		typ := f.Type
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ = star.X
+		}
+		switch typ := typ.(type) {
+		case *ast.Ident:
+			return []string{typ.Name}
+		case *ast.SelectorExpr:
+			return []string{typ.Sel.Name}
+		}
+		return nil
+	}
+	names := make([]string, len(f.Names))
+	for i, n := range f.Names {
+		names[i] = n.Name
+	}
+	return names
+}
+
+func (g *helpersGen) kindOf(e ast.Expr) valueKind {
+	switch e := e.(type) {
+	case *ast.StarExpr:
+		return kindPointer
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return kindSlice
+		}
+		if g.needsCopy(e.Elt) {
+			return kindArray
+		}
+		if k := g.kindOf(e.Elt); k == kindValue {
+			return kindValue
+		}
+		return kindArray
+	case *ast.MapType:
+		return kindMap
+	case *ast.ParenExpr:
+		return g.kindOf(e.X)
+	case *ast.Ident:
+		return g.identKind(e.Name)
+	case *ast.SelectorExpr:
+		pkg, ok := e.X.(*ast.Ident)
+		if !ok {
+			return kindShallow
+		}
+		return g.foreignKind(g.imports[pkg.Name], e.Sel.Name)
+	}
+	return kindShallow
+}
+
+// identKind returns the kind of predeclared or package type name.
+func (g *helpersGen) identKind(name string) valueKind {
+	if obj, ok := gotypes.Universe.Lookup(name).(*gotypes.TypeName); ok {
+		if _, ok := obj.Type().Underlying().(*gotypes.Basic); ok {
+			return kindValue
+		}
+		// error
+		return kindShallow
+	}
+	spec, ok := g.specs[name]
+	if !ok {
+		// types written by hand in the output package
+		return g.universeKind(g.lookupType(g.api.outputPackage, name), false)
+	}
+	if _, ok := spec.Type.(*ast.StructType); ok {
+		return kindStruct
+	}
+	return namedKind(g.kindOf(spec.Type))
+}
+
+// namedKind returns the kind of named type whose underlying type is of kind
+// k, methods of named types are generated for maps, slices and arrays.
+func namedKind(k valueKind) valueKind {
+	switch k {
+	case kindValue:
+		return kindValue
+	case kindSlice, kindMap, kindArray:
+		return kindNamed
+	}
+	return kindShallow
+}
+
+// foreignKind returns the kind of type name of package path, types
+// generated by model-api-gen into the mapped or sub packages are expected
+// to have helpers too.
+func (g *helpersGen) foreignKind(path, name string) valueKind {
+	if path == "time" && name == "Time" {
+		return kindTime
+	}
+	if src := g.sourcePackage(path); src != "" {
+		return g.universeKind(g.lookupType(src, name), true)
+	}
+	return g.universeKind(g.lookupType(path, name), false)
+}
+
+// lookupType returns the parsed type name of package path, nil if it isn't
+// parsed.
+func (g *helpersGen) lookupType(path, name string) *types.Type {
+	pkg, ok := g.universe[path]
+	if !ok {
+		return nil
+	}
+	return pkg.Types[name]
+}
+
+// sourcePackage returns the package model-api-gen generates the apis
+// package path from, empty if path isn't generated.
+func (g *helpersGen) sourcePackage(path string) string {
+	for src, out := range g.api.mapping.Mappings {
+		if out == path {
+			return src
+		}
+	}
+	if strings.HasPrefix(path, g.api.outputPackage+"/") {
+		return g.api.sourcePackage + strings.TrimPrefix(path, g.api.outputPackage)
+	}
+	return ""
+}
+
+// universeKind returns the kind of parsed type t, generated reports whether
+// the api type of t is generated with helpers.
+func (g *helpersGen) universeKind(t *types.Type, generated bool) valueKind {
+	if t == nil {
+		return kindShallow
+	}
+	switch t.Kind {
+	case types.Builtin:
+		if t.Name.Name == "error" {
+			return kindShallow
+		}
+		return kindValue
+	case types.Struct:
+		if generated {
+			return kindStruct
+		}
+	case types.Alias:
+		ut := UnderlyingType(t)
+		switch ut.Kind {
+		case types.Builtin:
+			return g.universeKind(ut, false)
+		case types.Map, types.Slice, types.Array:
+			if generated {
+				return kindNamed
+			}
+		}
+	}
+	return kindShallow
+}
+
+// needsCopy reports whether values of type e share memory after assignment.
+func (g *helpersGen) needsCopy(e ast.Expr) bool {
+	switch g.kindOf(e) {
+	case kindPointer, kindSlice, kindMap, kindStruct, kindNamed:
+		return true
+	case kindArray:
+		return g.needsCopy(e.(*ast.ArrayType).Elt)
+	}
+	return false
+}
+
+// copyInto writes the statements deep copying src of type e into dst, dst
+// holds the shallow copy of src or the zero value.
+func (g *helpersGen) copyInto(b *bytes.Buffer, dst, src string, e ast.Expr, depth int) {
+	switch g.kindOf(e) {
+	case kindStruct, kindNamed:
+		fmt.Fprintf(b, "%s.DeepCopyInto(%s)\n", operand(src), addr(dst))
+	case kindPointer:
+		elem := e.(*ast.StarExpr).X
+		if g.kindOf(elem) == kindStruct {
+			// DeepCopy of structs handles nil
+			fmt.Fprintf(b, "%s = %s.DeepCopy()\n", dst, src)
+			return
+		}
+		fmt.Fprintf(b, "if %s != nil {\n", src)
+		fmt.Fprintf(b, "%s = new(%s)\n", dst, gotypes.ExprString(elem))
+		if g.needsCopy(elem) {
+			g.copyInto(b, deref(dst), deref(src), elem, depth+1)
+		} else {
+			fmt.Fprintf(b, "%s = %s\n", deref(dst), deref(src))
+		}
+		fmt.Fprintf(b, "}\n")
+	case kindSlice:
+		elem := e.(*ast.ArrayType).Elt
+		fmt.Fprintf(b, "if %s != nil {\n", src)
+		fmt.Fprintf(b, "%s = make(%s, len(%s))\n", dst, gotypes.ExprString(e), src)
+		if g.needsCopy(elem) {
+			g.copyElems(b, dst, src, elem, depth)
+		} else {
+			fmt.Fprintf(b, "copy(%s, %s)\n", dst, src)
+		}
+		fmt.Fprintf(b, "}\n")
+	case kindArray:
+		g.copyElems(b, dst, src, e.(*ast.ArrayType).Elt, depth)
+	case kindMap:
+		elem := e.(*ast.MapType).Value
+		key, val := loopVar("key", depth), loopVar("val", depth)
+		fmt.Fprintf(b, "if %s != nil {\n", src)
+		fmt.Fprintf(b, "%s = make(%s, len(%s))\n", dst, gotypes.ExprString(e), src)
+		fmt.Fprintf(b, "for %s, %s := range %s {\n", key, val, src)
+		if g.needsCopy(elem) {
+			// map elements aren't addressable
+			outVal := loopVar("outVal", depth)
+			fmt.Fprintf(b, "var %s %s\n", outVal, gotypes.ExprString(elem))
+			g.copyInto(b, outVal, val, elem, depth+1)
+			fmt.Fprintf(b, "%s[%s] = %s\n", operand(dst), key, outVal)
+		} else {
+			fmt.Fprintf(b, "%s[%s] = %s\n", operand(dst), key, val)
+		}
+		fmt.Fprintf(b, "}\n}\n")
+	default:
+		fmt.Fprintf(b, "%s = %s\n", dst, src)
+	}
+}
+
+func (g *helpersGen) copyElems(b *bytes.Buffer, dst, src string, elem ast.Expr, depth int) {
+	i := loopVar("i", depth)
+	fmt.Fprintf(b, "for %s := range %s {\n", i, src)
+	g.copyInto(b, index(dst, i), index(src, i), elem, depth+1)
+	fmt.Fprintf(b, "}\n")
+}
+
+// equal writes the statements returning false if a and o of type e differ.
+func (g *helpersGen) equal(b *bytes.Buffer, a, o string, e ast.Expr, depth int) {
+	switch g.kindOf(e) {
+	case kindValue:
+		fmt.Fprintf(b, "if %s != %s {\nreturn false\n}\n", a, o)
+	case kindTime:
+		fmt.Fprintf(b, "if !%s.Equal(%s) {\nreturn false\n}\n", operand(a), o)
+	case kindStruct:
+		fmt.Fprintf(b, "if !%s.Equal(%s) {\nreturn false\n}\n", operand(a), addr(o))
+	case kindNamed:
+		fmt.Fprintf(b, "if !%s.Equal(%s) {\nreturn false\n}\n", operand(a), o)
+	case kindPointer:
+		elem := e.(*ast.StarExpr).X
+		if g.kindOf(elem) == kindStruct {
+			// Equal of structs handles nil
+			fmt.Fprintf(b, "if !%s.Equal(%s) {\nreturn false\n}\n", a, o)
+			return
+		}
+		fmt.Fprintf(b, "if (%s == nil) != (%s == nil) {\nreturn false\n}\n", a, o)
+		fmt.Fprintf(b, "if %s != nil {\n", a)
+		g.equal(b, deref(a), deref(o), elem, depth+1)
+		fmt.Fprintf(b, "}\n")
+	case kindSlice, kindArray:
+		i := loopVar("i", depth)
+		if g.kindOf(e) == kindSlice {
+			fmt.Fprintf(b, "if len(%s) != len(%s) {\nreturn false\n}\n", a, o)
+		}
+		fmt.Fprintf(b, "for %s := range %s {\n", i, a)
+		g.equal(b, index(a, i), index(o, i), e.(*ast.ArrayType).Elt, depth+1)
+		fmt.Fprintf(b, "}\n")
+	case kindMap:
+		key, val, otherVal := loopVar("key", depth), loopVar("val", depth), loopVar("otherVal", depth)
+		fmt.Fprintf(b, "if len(%s) != len(%s) {\nreturn false\n}\n", a, o)
+		fmt.Fprintf(b, "for %s, %s := range %s {\n", key, val, a)
+		fmt.Fprintf(b, "%s, ok := %s[%s]\n", otherVal, operand(o), key)
+		fmt.Fprintf(b, "if !ok {\nreturn false\n}\n")
+		g.equal(b, val, otherVal, e.(*ast.MapType).Value, depth+1)
+		fmt.Fprintf(b, "}\n")
+	default:
+		fmt.Fprintf(b, "if !reflect.DeepEqual(%s, %s) {\nreturn false\n}\n", a, o)
+	}
+}
+
+// loopVar returns the name of loop variable of nesting depth.
+func loopVar(name string, depth int) string {
+	if depth == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s%d", name, depth)
+}
+
+func deref(x string) string {
+	return "*" + x
+}
+
+// operand returns x usable as operand of selector or index expression.
+func operand(x string) string {
+	if strings.HasPrefix(x, "*") {
+		return "(" + x + ")"
+	}
+	return x
+}
+
+func addr(x string) string {
+	if strings.HasPrefix(x, "*") {
+		return x[1:]
+	}
+	return "&" + x
+}
+
+func index(x, i string) string {
+	return fmt.Sprintf("%s[%s]", operand(x), i)
+}
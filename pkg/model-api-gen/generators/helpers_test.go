@@ -0,0 +1,80 @@
+package generators
+
+import (
+	"go/ast"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"yunion.io/x/code-generator/pkg/common/golden"
+)
+
+func TestIsSensitive(t *testing.T) {
+	tag := func(s string) *ast.BasicLit {
+		return &ast.BasicLit{Kind: token.STRING, Value: "`" + s + "`"}
+	}
+	tests := []struct {
+		name string
+		tag  *ast.BasicLit
+		want bool
+	}{
+		{name: "VncPassword", tag: tag(`json:"vnc_password"`), want: true},
+		{name: "Key", tag: tag(`json:"private_key,omitempty"`), want: true},
+		{name: "AccessSecret", want: true},
+		{name: "Secret", tag: tag(`json:"name"`), want: false},
+		{name: "Host", tag: tag(`json:"host"`), want: false},
+	}
+	for _, tt := range tests {
+		if got := isSensitive(tt.name, tt.tag); got != tt.want {
+			t.Errorf("isSensitive(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestGoldenHelpers builds the golden apis package with the fixture apis
+// package in a GOPATH and runs testdata/helpers against it.
+func TestGoldenHelpers(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command isn't found")
+	}
+	gopath := t.TempDir()
+	apisDir := filepath.Join(gopath, "src", "yunion.io", "x", "onecloud", "pkg", "apis")
+	computeDir := filepath.Join(apisDir, "compute")
+	copyFiles(t, filepath.Join(golden.FixtureRoot(), "src", "yunion.io", "x", "onecloud", "pkg", "apis"), apisDir)
+	copyFiles(t, filepath.Join("testdata", "golden", "yunion.io", "x", "onecloud", "pkg", "apis", "compute"), computeDir)
+	copyFiles(t, filepath.Join("testdata", "helpers"), computeDir)
+
+	cmd := exec.Command("go", "test", "-count=1", ".")
+	cmd.Dir = computeDir
+	cmd.Env = append(os.Environ(), "GO111MODULE=off", "GOPATH="+gopath, "GOFLAGS=")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("test golden helpers: %v\n%s", err, out)
+	}
+}
+
+// copyFiles copies the regular files of dir src into dir dst.
+func copyFiles(t *testing.T, src, dst string) {
+	t.Helper()
+	infos, err := ioutil.ReadDir(src)
+	if err != nil {
+		t.Fatalf("read %s: %v", src, err)
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		t.Fatalf("mkdir %s: %v", dst, err)
+	}
+	for _, info := range infos {
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(src, info.Name()))
+		if err != nil {
+			t.Fatalf("read %s: %v", info.Name(), err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dst, info.Name()), data, 0644); err != nil {
+			t.Fatalf("write %s: %v", info.Name(), err)
+		}
+	}
+}
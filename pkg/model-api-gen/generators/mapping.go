@@ -0,0 +1,179 @@
+package generators
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/imports"
+	"gopkg.in/yaml.v3"
+	"k8s.io/gengo/generator"
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/pkg/util/sets"
+
+	"yunion.io/x/code-generator/pkg/common"
+)
+
+// MappingConfig describes the packages of source models and the apis
+// packages they are generated into, it's loaded from the YAML or JSON file
+// given by --mapping-file so that forks and services outside the onecloud
+// module path work too.
+type MappingConfig struct {
+	// ReplaceDefaults if true, the file replaces the mappings of onecloud
+	// instead of being merged into them.
+	ReplaceDefaults bool `yaml:"replaceDefaults"`
+	// Mappings maps source packages to output apis packages, types of them
+	// are referred by the apis packages instead of generated again.
+	Mappings map[string]string `yaml:"mappings"`
+	// APIsPackage is the apis package of input packages, it's derived from
+	// yunion.io/x/<project>/pkg/apis layout if empty.
+	APIsPackage string `yaml:"apisPackage"`
+	// LocalPrefixes are the import path prefixes grouped after third party
+	// imports by goimports, each one makes a group in order, prefixes of
+	// the same group are separated by comma.
+	LocalPrefixes []string `yaml:"localPrefixes"`
+	// ModelBase is the full name of the base type of all resource models
+	ModelBase string `yaml:"modelBase"`
+	// SkipPackages are the packages whose types never make resource models
+	SkipPackages []string `yaml:"skipPackages"`
+	// ModelInterface and ManagerInterface are the full names of interfaces
+	// implemented by models and managers, e.g. db.IModel and
+	// db.IModelManager of onecloud. Structs with methods of ModelInterface
+	// are models too, and managers must have methods of ManagerInterface.
+	// Method sets aren't checked if they are empty.
+	ModelInterface   string `yaml:"modelInterface"`
+	ManagerInterface string `yaml:"managerInterface"`
+	// TypeMap replaces fields of the types keyed by short or full type
+	// name, merged into TypeMap.
+	TypeMap map[string]TypeMapping `yaml:"typeMap"`
+}
+
+// NewDefaultMappingConfig returns the mapping of onecloud.
+func NewDefaultMappingConfig() *MappingConfig {
+	return &MappingConfig{
+		Mappings: map[string]string{
+			CloudCommonDBPackage: APIsPackage,
+			CloudProviderPackage: APIsCloudProviderPackage,
+			MonitorModelsPackage: APIsMonitorPackage,
+		},
+		LocalPrefixes: []string{
+			"yunion.io/x/",
+			"yunion.io/x/onecloud",
+			"yunion.io/x/meter",
+			"yunion.io/x/nocloud",
+		},
+		ModelBase: CloudCommonDBPackage + "." + SModelBase,
+		SkipPackages: []string{
+			CloudCommonDBPackage + "/quotas",
+		},
+	}
+}
+
+// LoadMappingConfig loads mapping file, mappings of file are merged into
+// the defaults and other fields set in file replace the defaults, the
+// defaults aren't used if replaceDefaults is set in file. Defaults are
+// returned if file is empty.
+func LoadMappingConfig(file string) (*MappingConfig, error) {
+	if file == "" {
+		return NewDefaultMappingConfig(), nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is YAML too
+	conf := &MappingConfig{}
+	if err := yaml.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("parse mapping file %s: %v", file, err)
+	}
+	if !conf.ReplaceDefaults {
+		conf = NewDefaultMappingConfig()
+		if err := yaml.Unmarshal(data, conf); err != nil {
+			return nil, fmt.Errorf("parse mapping file %s: %v", file, err)
+		}
+	}
+	if err := conf.validate(); err != nil {
+		return nil, fmt.Errorf("mapping file %s: %v", file, err)
+	}
+	return conf, nil
+}
+
+func (c *MappingConfig) validate() error {
+	for src, out := range c.Mappings {
+		if src == "" || out == "" {
+			return fmt.Errorf("invalid mapping %q: %q", src, out)
+		}
+	}
+	for name, m := range c.TypeMap {
+		if name == "" {
+			return fmt.Errorf("invalid type map %q: %q", name, m.Type)
+		}
+		if _, err := common.ParseGoType(m.Type); err != nil {
+			return fmt.Errorf("invalid type map %q: %v", name, err)
+		}
+	}
+	for key, name := range map[string]string{
+		"modelBase":        c.ModelBase,
+		"modelInterface":   c.ModelInterface,
+		"managerInterface": c.ManagerInterface,
+	} {
+		if name != "" && strings.LastIndex(name, ".") <= 0 {
+			return fmt.Errorf("%s %q is not a full type name, e.g. yunion.io/x/onecloud/pkg/cloudcommon/db.SModelBase", key, name)
+		}
+	}
+	return nil
+}
+
+// NewModelDetector returns the detector of resource models of the mapping,
+// interfaces are looked up from universe u.
+func (c *MappingConfig) NewModelDetector(u types.Universe) *common.ModelDetector {
+	d := common.NewModelDetector()
+	d.ModelBase = c.ModelBase
+	d.SkipPackages = sets.NewString(c.SkipPackages...)
+	d.SetInterfaces(c.ModelInterface, c.ManagerInterface)
+	d.SetUniverse(u)
+	return d
+}
+
+// localPrefixLock serializes the formatting of go files, goimports only
+// takes the local prefixes from its global LocalPrefix.
+var localPrefixLock sync.Mutex
+
+// golangFileType returns the go file type formatting the imports of
+// LocalPrefixes into their own groups.
+func (c *MappingConfig) golangFileType() *generator.DefaultFileType {
+	localPrefix := strings.Join(c.LocalPrefixes, ":")
+	ft := generator.NewGolangFile()
+	ft.Format = func(src []byte) ([]byte, error) {
+		localPrefixLock.Lock()
+		defer localPrefixLock.Unlock()
+		prev := imports.LocalPrefix
+		imports.LocalPrefix = localPrefix
+		defer func() {
+			imports.LocalPrefix = prev
+		}()
+		return imports.Process("", src, nil)
+	}
+	return ft
+}
+
+// apisPackage returns the apis package of source package srcPkg.
+func (c *MappingConfig) apisPackage(srcPkg string) string {
+	if c.APIsPackage != "" {
+		return c.APIsPackage
+	}
+	return defaultAPIsPkg(srcPkg)
+}
+
+// isMappedPackage returns true if pkg or its parent is a mapped source
+// package, types of them are referred by apis packages.
+func (c *MappingConfig) isMappedPackage(pkg string) bool {
+	for src := range c.Mappings {
+		if pkg == src || strings.HasPrefix(pkg, src+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package generators
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/code-generator/pkg/common"
+)
+
+func TestLoadMappingConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "mapping.yaml")
+	content := `
+mappings:
+  example.com/svc/pkg/db: example.com/svc/pkg/apis
+apisPackage: example.com/svc/pkg/apis
+modelBase: example.com/svc/pkg/db.SModelBase
+skipPackages:
+  - example.com/svc/pkg/db/quotas
+`
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := LoadMappingConfig(file)
+	if err != nil {
+		t.Fatalf("LoadMappingConfig: %v", err)
+	}
+	// mappings are merged into defaults
+	if conf.Mappings["example.com/svc/pkg/db"] != "example.com/svc/pkg/apis" || conf.Mappings[CloudCommonDBPackage] != APIsPackage {
+		t.Errorf("mappings = %v", conf.Mappings)
+	}
+	if !conf.isMappedPackage("example.com/svc/pkg/db/lockman") || conf.isMappedPackage("example.com/svc/pkg/dbx") {
+		t.Errorf("isMappedPackage of sub packages is wrong")
+	}
+	if got := conf.apisPackage("example.com/svc/pkg/models"); got != "example.com/svc/pkg/apis" {
+		t.Errorf("apisPackage = %q", got)
+	}
+
+	d := conf.NewModelDetector(nil)
+	base := &types.Type{Name: types.Name{Package: "example.com/svc/pkg/db", Name: "SModelBase"}, Kind: types.Struct}
+	model := &types.Type{
+		Name:    types.Name{Package: "example.com/svc/pkg/models", Name: "SServer"},
+		Kind:    types.Struct,
+		Members: []types.Member{{Name: "SModelBase", Type: base, Embedded: true}},
+	}
+	quota := &types.Type{
+		Name:    types.Name{Package: "example.com/svc/pkg/db/quotas", Name: "SQuota"},
+		Kind:    types.Struct,
+		Members: []types.Member{{Name: "SModelBase", Type: base, Embedded: true}},
+	}
+	if !d.Classify(model).IsModel || d.Classify(base).IsModel || d.Classify(quota).IsModel {
+		t.Errorf("resource models of mapping are wrong")
+	}
+	// the global detector keeps the defaults
+	if common.IsResourceModel(model) {
+		t.Errorf("mapping changed the default model detector")
+	}
+}
+
+func TestLoadMappingConfigReplaceDefaults(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "mapping.yaml")
+	content := `
+replaceDefaults: true
+mappings:
+  example.com/svc/pkg/db: example.com/svc/pkg/apis
+modelBase: example.com/svc/pkg/db.SModelBase
+`
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := LoadMappingConfig(file)
+	if err != nil {
+		t.Fatalf("LoadMappingConfig: %v", err)
+	}
+	if len(conf.Mappings) != 1 || conf.Mappings["example.com/svc/pkg/db"] != "example.com/svc/pkg/apis" {
+		t.Errorf("mappings = %v", conf.Mappings)
+	}
+	if len(conf.SkipPackages) != 0 || len(conf.LocalPrefixes) != 0 {
+		t.Errorf("defaults are kept: skipPackages %v, localPrefixes %v", conf.SkipPackages, conf.LocalPrefixes)
+	}
+}
+
+func TestDefaultAPIsPkg(t *testing.T) {
+	for src, want := range map[string]string{
+		"yunion.io/x/onecloud/pkg/compute/models": "yunion.io/x/onecloud/pkg/apis",
+		"yunion.io/x/meter/pkg/models":            "yunion.io/x/meter/pkg/apis",
+		"example.com/svc/pkg/models":              "",
+	} {
+		if got := defaultAPIsPkg(src); got != want {
+			t.Errorf("defaultAPIsPkg(%s) = %q, want %q", src, got, want)
+		}
+	}
+}
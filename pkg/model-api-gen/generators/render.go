@@ -0,0 +1,142 @@
+package generators
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/gengo/namer"
+	"k8s.io/gengo/types"
+)
+
+// arrayLen returns the length part of array type, e.g. [4] of [4]string.
+// gengo doesn't store the length, but it's kept in the name of the array.
+func arrayLen(t *types.Type) (string, bool) {
+	name := t.Name.Name
+	if !strings.HasPrefix(name, "[") {
+		return "", false
+	}
+	idx := strings.Index(name, "]")
+	if idx <= 1 {
+		return "", false
+	}
+	return name[:idx+1], true
+}
+
+// typeExpr renders type t of the field of parentType as go expression of
+// output package, types of source package are replaced recursively by the
+// generated ones. It returns false if t contains kinds can't be generated.
+func (g *apiGen) typeExpr(parentType *types.Type, field string, t *types.Type) (string, bool) {
+	switch t.Kind {
+	case types.Builtin:
+		return t.Name.Name, true
+	case types.Pointer, types.Slice, types.Array:
+		var prefix string
+		switch t.Kind {
+		case types.Pointer:
+			prefix = "*"
+		case types.Slice:
+			prefix = "[]"
+		case types.Array:
+			l, ok := arrayLen(t)
+			if !ok {
+				g.diags.Errorf(parentType, field, "unknown length of array %s", t.String())
+				return "", false
+			}
+			prefix = l
+		}
+		elem, ok := g.typeExpr(parentType, field, t.Elem)
+		if !ok {
+			return "", false
+		}
+		if t.Kind == types.Pointer && g.isPointerTypeMap(t.Elem) {
+			// e.g. *TriState is *bool too
+			return elem, true
+		}
+		return prefix + elem, true
+	case types.Map:
+		key, ok := g.typeExpr(parentType, field, t.Key)
+		if !ok {
+			return "", false
+		}
+		elem, ok := g.typeExpr(parentType, field, t.Elem)
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprintf("map[%s]%s", key, elem), true
+	case types.Alias:
+		if ct, ok := g.lookupTypeMap(t); ok {
+			return ct.Type, true
+		}
+		if g.inSourcePackage(t) {
+			if g.modelDependTypes.Has(t.String()) {
+				return t.Name.Name, true
+			}
+			return g.typeExpr(parentType, field, UnderlyingType(t))
+		}
+		if t.Name.Package != "" {
+			// e.g. time.Duration or the aliases of mapped packages
+			return g.namedTypeExpr(t), true
+		}
+		return g.typeExpr(parentType, field, UnderlyingType(t))
+	case types.Interface:
+		if t.Name.Package == "" {
+			if t.Name.Name == "error" {
+				return t.Name.Name, true
+			}
+			return "interface{}", true
+		}
+		return g.namedTypeExpr(t), true
+	case types.Struct:
+		if t.Name.Package == "" {
+			// anonymous struct
+			return g.rawName(t), true
+		}
+		if ct, ok := g.lookupTypeMap(t); ok {
+			return ct.Type, true
+		}
+		return g.namedTypeExpr(t), true
+	case types.Func, types.Chan:
+		// func and chan can't be marshalled to JSON, so they never appear in apis
+		g.diags.Warningf(parentType, field, "%s of kind %s can't be marshalled, skipped", t.String(), t.Kind)
+		return "", false
+	default:
+		g.diags.Errorf(parentType, field, "unsupported type %s, kind is %s, skipped", t.String(), t.Kind)
+		return "", false
+	}
+}
+
+// namedTypeExpr returns the name of named type t in output package, the
+// package of t is imported if required.
+func (g *apiGen) namedTypeExpr(t *types.Type) string {
+	switch {
+	case g.inSourcePackage(t), g.inOutputPackage(t):
+		return t.Name.Name
+	}
+	if outPkg, ok := g.GetInputOutputPackageMap()[t.Name.Package]; ok {
+		g.needImportPackages.Insert(outPkg)
+		return fmt.Sprintf("%s.%s", filepath.Base(outPkg), t.Name.Name)
+	}
+	if strings.HasPrefix(t.Name.Package, g.sourcePackage+"/") {
+		outPkg := filepath.Join(g.outputPackage, t.Name.Package[len(g.sourcePackage)+1:])
+		g.needImportPackages.Insert(outPkg)
+		return fmt.Sprintf("%s.%s", filepath.Base(outPkg), t.Name.Name)
+	}
+	return g.rawName(t)
+}
+
+func (g *apiGen) rawName(t *types.Type) string {
+	if g.rawNamer == nil {
+		g.rawNamer = namer.NewRawNamer(g.outputPackage, g.imports)
+	}
+	return g.rawNamer.Name(t)
+}
+
+// isPointerTypeMap reports named t is replaced by a pointer type of TypeMap.
+func (g *apiGen) isPointerTypeMap(t *types.Type) bool {
+	if t.Name.Package == "" {
+		return false
+	}
+	ct, ok := g.lookupTypeMap(t)
+	return ok && strings.HasPrefix(ct.Type, "*")
+}
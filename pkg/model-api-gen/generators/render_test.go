@@ -0,0 +1,91 @@
+package generators
+
+import (
+	"testing"
+
+	"k8s.io/gengo/types"
+)
+
+func TestTypeExpr(t *testing.T) {
+	const srcPkg = "yunion.io/x/onecloud/pkg/compute/models"
+	g := NewApiGen("zz_generated.model", srcPkg, "", nil, "yunion.io/x/onecloud/pkg/apis/compute", nil, nil, nil).(*apiGen)
+
+	str := types.String
+	tag := &types.Type{Name: types.Name{Package: srcPkg, Name: "ServerTag"}, Kind: types.Struct}
+	triState := &types.Type{Name: types.Name{Package: srcPkg, Name: "TriState"}, Kind: types.Alias, Underlying: str}
+	ptr := func(e *types.Type) *types.Type {
+		return &types.Type{Name: types.Name{Name: "*" + e.String()}, Kind: types.Pointer, Elem: e}
+	}
+	slice := func(e *types.Type) *types.Type {
+		return &types.Type{Name: types.Name{Name: "[]" + e.String()}, Kind: types.Slice, Elem: e}
+	}
+	tests := []struct {
+		name string
+		t    *types.Type
+		want string
+		ok   bool
+	}{
+		{
+			name: "nested map of source struct",
+			t: &types.Type{
+				Kind: types.Map,
+				Key:  str,
+				Elem: &types.Type{Kind: types.Map, Key: str, Elem: ptr(tag)},
+			},
+			want: "map[string]map[string]*ServerTag",
+			ok:   true,
+		},
+		{
+			name: "slice of slice",
+			t:    slice(slice(tag)),
+			want: "[][]ServerTag",
+			ok:   true,
+		},
+		{
+			name: "array",
+			t:    &types.Type{Name: types.Name{Name: "[2]*" + tag.String()}, Kind: types.Array, Elem: ptr(tag)},
+			want: "[2]*ServerTag",
+			ok:   true,
+		},
+		{
+			name: "slice of pointer of TypeMap alias",
+			t:    slice(ptr(triState)),
+			want: "[]*bool",
+			ok:   true,
+		},
+		{
+			name: "alias of other package",
+			t:    slice(&types.Type{Name: types.Name{Package: "time", Name: "Duration"}, Kind: types.Alias, Underlying: types.Int64}),
+			want: "[]time.Duration",
+			ok:   true,
+		},
+		{
+			name: "alias of source package not generated",
+			t:    &types.Type{Name: types.Name{Package: srcPkg, Name: "ServerStatus"}, Kind: types.Alias, Underlying: str},
+			want: "string",
+			ok:   true,
+		},
+		{
+			name: "func",
+			t:    &types.Type{Name: types.Name{Name: "func()"}, Kind: types.Func},
+			ok:   false,
+		},
+		{
+			name: "map of chan",
+			t: &types.Type{
+				Kind: types.Map,
+				Key:  str,
+				Elem: &types.Type{Name: types.Name{Name: "chan string"}, Kind: types.Chan, Elem: str},
+			},
+			ok: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := g.typeExpr(tag, "Field", tt.t)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("typeExpr() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,352 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by generators.test. DO NOT EDIT.
+
+package compute
+
+import (
+	"fmt"
+	"strings"
+	time "time"
+)
+
+// DeepCopyInto copies the receiver into out, in must be non-nil.
+func (in *SDisk) DeepCopyInto(out *SDisk) {
+	*out = *in
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SDisk) DeepCopy() *SDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(SDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// Equal reports whether the receiver and other are deeply equal, nil and
+// empty slices or maps are equal.
+func (in *SDisk) Equal(other *SDisk) bool {
+	if in == nil || other == nil {
+		return in == other
+	}
+	if in.DiskSize != other.DiskSize {
+		return false
+	}
+	if in.Storage != other.Storage {
+		return false
+	}
+	if in.EncryptPassword != other.EncryptPassword {
+		return false
+	}
+	return true
+}
+
+// String returns the fields of the receiver, the sensitive ones are
+// redacted.
+func (in *SDisk) String() string {
+	if in == nil {
+		return "<nil>"
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "{DiskSize:%+v", in.DiskSize)
+	fmt.Fprintf(b, " Storage:%+v", in.Storage)
+	if in.EncryptPassword != "" {
+		b.WriteString(" EncryptPassword:******")
+	} else {
+		b.WriteString(" EncryptPassword:")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// DeepCopyInto copies the receiver into out, in must be non-nil.
+func (in *SServer) DeepCopyInto(out *SServer) {
+	*out = *in
+	in.SStandaloneResourceBase.DeepCopyInto(&out.SStandaloneResourceBase)
+	if in.Disabled != nil {
+		out.Disabled = new(bool)
+		*out.Disabled = *in.Disabled
+	}
+	if in.AutoStart != nil {
+		out.AutoStart = new(bool)
+		*out.AutoStart = *in.AutoStart
+	}
+	if in.ImageMeta != nil {
+		out.ImageMeta = make(map[string]string, len(in.ImageMeta))
+		for key, val := range in.ImageMeta {
+			out.ImageMeta[key] = val
+		}
+	}
+	if in.AgentTimeout != nil {
+		out.AgentTimeout = new(time.Duration)
+		*out.AgentTimeout = *in.AgentTimeout
+	}
+	in.Tags.DeepCopyInto(&out.Tags)
+	if in.Disks != nil {
+		out.Disks = make([]SDisk, len(in.Disks))
+		for i := range in.Disks {
+			in.Disks[i].DeepCopyInto(&out.Disks[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SServer) DeepCopy() *SServer {
+	if in == nil {
+		return nil
+	}
+	out := new(SServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// Equal reports whether the receiver and other are deeply equal, nil and
+// empty slices or maps are equal.
+func (in *SServer) Equal(other *SServer) bool {
+	if in == nil || other == nil {
+		return in == other
+	}
+	if !in.SStandaloneResourceBase.Equal(&other.SStandaloneResourceBase) {
+		return false
+	}
+	if in.VcpuCount != other.VcpuCount {
+		return false
+	}
+	if in.VmemSize != other.VmemSize {
+		return false
+	}
+	if in.BootOrder != other.BootOrder {
+		return false
+	}
+	if in.Status != other.Status {
+		return false
+	}
+	if (in.Disabled == nil) != (other.Disabled == nil) {
+		return false
+	}
+	if in.Disabled != nil {
+		if *in.Disabled != *other.Disabled {
+			return false
+		}
+	}
+	if !in.LastStartAt.Equal(other.LastStartAt) {
+		return false
+	}
+	if (in.AutoStart == nil) != (other.AutoStart == nil) {
+		return false
+	}
+	if in.AutoStart != nil {
+		if *in.AutoStart != *other.AutoStart {
+			return false
+		}
+	}
+	if in.BackupHostID != other.BackupHostID {
+		return false
+	}
+	if len(in.ImageMeta) != len(other.ImageMeta) {
+		return false
+	}
+	for key, val := range in.ImageMeta {
+		otherVal, ok := other.ImageMeta[key]
+		if !ok {
+			return false
+		}
+		if val != otherVal {
+			return false
+		}
+	}
+	if (in.AgentTimeout == nil) != (other.AgentTimeout == nil) {
+		return false
+	}
+	if in.AgentTimeout != nil {
+		if *in.AgentTimeout != *other.AgentTimeout {
+			return false
+		}
+	}
+	if !in.Tags.Equal(other.Tags) {
+		return false
+	}
+	if len(in.Disks) != len(other.Disks) {
+		return false
+	}
+	for i := range in.Disks {
+		if !in.Disks[i].Equal(&other.Disks[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the fields of the receiver, the sensitive ones are
+// redacted.
+func (in *SServer) String() string {
+	if in == nil {
+		return "<nil>"
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "{SStandaloneResourceBase:%s", in.SStandaloneResourceBase.String())
+	fmt.Fprintf(b, " VcpuCount:%+v", in.VcpuCount)
+	fmt.Fprintf(b, " VmemSize:%+v", in.VmemSize)
+	fmt.Fprintf(b, " BootOrder:%+v", in.BootOrder)
+	fmt.Fprintf(b, " Status:%+v", in.Status)
+	fmt.Fprintf(b, " Disabled:%+v", in.Disabled)
+	fmt.Fprintf(b, " LastStartAt:%+v", in.LastStartAt)
+	fmt.Fprintf(b, " AutoStart:%+v", in.AutoStart)
+	fmt.Fprintf(b, " BackupHostID:%+v", in.BackupHostID)
+	fmt.Fprintf(b, " ImageMeta:%+v", in.ImageMeta)
+	fmt.Fprintf(b, " AgentTimeout:%+v", in.AgentTimeout)
+	fmt.Fprintf(b, " Tags:%+v", in.Tags)
+	disksStr := make([]string, len(in.Disks))
+	for i := range in.Disks {
+		disksStr[i] = in.Disks[i].String()
+	}
+	fmt.Fprintf(b, " Disks:%v", disksStr)
+	b.WriteString("}")
+	return b.String()
+}
+
+// DeepCopyInto copies the receiver into out, in must be non-nil.
+func (in *ServerDetails) DeepCopyInto(out *ServerDetails) {
+	*out = *in
+	in.StandaloneResourceDetails.DeepCopyInto(&out.StandaloneResourceDetails)
+	in.SServer.DeepCopyInto(&out.SServer)
+	if in.StorageDisks != nil {
+		out.StorageDisks = make(map[string][]*SDisk, len(in.StorageDisks))
+		for key, val := range in.StorageDisks {
+			var outVal []*SDisk
+			if val != nil {
+				outVal = make([]*SDisk, len(val))
+				for i1 := range val {
+					outVal[i1] = val[i1].DeepCopy()
+				}
+			}
+			out.StorageDisks[key] = outVal
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *ServerDetails) DeepCopy() *ServerDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// Equal reports whether the receiver and other are deeply equal, nil and
+// empty slices or maps are equal.
+func (in *ServerDetails) Equal(other *ServerDetails) bool {
+	if in == nil || other == nil {
+		return in == other
+	}
+	if !in.StandaloneResourceDetails.Equal(&other.StandaloneResourceDetails) {
+		return false
+	}
+	if !in.SServer.Equal(&other.SServer) {
+		return false
+	}
+	if in.Host != other.Host {
+		return false
+	}
+	if len(in.StorageDisks) != len(other.StorageDisks) {
+		return false
+	}
+	for key, val := range in.StorageDisks {
+		otherVal, ok := other.StorageDisks[key]
+		if !ok {
+			return false
+		}
+		if len(val) != len(otherVal) {
+			return false
+		}
+		for i1 := range val {
+			if !val[i1].Equal(otherVal[i1]) {
+				return false
+			}
+		}
+	}
+	if in.VncPassword != other.VncPassword {
+		return false
+	}
+	return true
+}
+
+// String returns the fields of the receiver, the sensitive ones are
+// redacted.
+func (in *ServerDetails) String() string {
+	if in == nil {
+		return "<nil>"
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "{StandaloneResourceDetails:%s", in.StandaloneResourceDetails.String())
+	fmt.Fprintf(b, " SServer:%s", in.SServer.String())
+	fmt.Fprintf(b, " Host:%+v", in.Host)
+	storageDisksStr := make(map[string][]string, len(in.StorageDisks))
+	for key, val := range in.StorageDisks {
+		outVal := make([]string, len(val))
+		for i1 := range val {
+			outVal[i1] = val[i1].String()
+		}
+		storageDisksStr[key] = outVal
+	}
+	fmt.Fprintf(b, " StorageDisks:%v", storageDisksStr)
+	if in.VncPassword != "" {
+		b.WriteString(" VncPassword:******")
+	} else {
+		b.WriteString(" VncPassword:")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in ServerTags) DeepCopyInto(out *ServerTags) {
+	if in != nil {
+		*out = make(map[string]string, len(in))
+		for key, val := range in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in ServerTags) DeepCopy() ServerTags {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerTags)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// Equal reports whether the receiver and other are deeply equal, nil and
+// empty slices or maps are equal.
+func (in ServerTags) Equal(other ServerTags) bool {
+	if len(in) != len(other) {
+		return false
+	}
+	for key, val := range in {
+		otherVal, ok := other[key]
+		if !ok {
+			return false
+		}
+		if val != otherVal {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package compute
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestHelpers is copied next to the golden apis package by TestGoldenHelpers
+// of model-api-gen, it checks the generated helpers.
+func TestHelpers(t *testing.T) {
+	autoStart := true
+	in := &ServerDetails{
+		SServer: SServer{
+			VcpuCount: 2,
+			AutoStart: &autoStart,
+			Tags:      map[string]string{"env": "prod"},
+			Disks:     []SDisk{{DiskSize: 1024, EncryptPassword: "disk-secret"}},
+		},
+		Host: "host1",
+		StorageDisks: map[string][]*SDisk{
+			"local": {{DiskSize: 2048, EncryptPassword: "local-secret"}, nil},
+		},
+		VncPassword: "vnc-secret",
+	}
+	in.Name = "server1"
+
+	out := in.DeepCopy()
+	if !out.Equal(in) || !in.Equal(out) {
+		t.Fatalf("copy %s isn't equal to %s", out, in)
+	}
+	*out.AutoStart = false
+	out.Tags["env"] = "dev"
+	out.Disks[0].DiskSize = 1
+	out.StorageDisks["local"][0].DiskSize = 1
+	out.Name = "server2"
+	if !*in.AutoStart || in.Tags["env"] != "prod" || in.Disks[0].DiskSize != 1024 || in.StorageDisks["local"][0].DiskSize != 2048 || in.Name != "server1" {
+		t.Errorf("changing the copy changes the original %s", in)
+	}
+	if out.Equal(in) {
+		t.Errorf("changed copy %s is equal to %s", out, in)
+	}
+	if (&SServer{}).Equal(&SServer{Tags: map[string]string{}}) != true {
+		t.Errorf("nil and empty maps aren't equal")
+	}
+
+	str := in.String()
+	for _, secret := range []string{"disk-secret", "local-secret", "vnc-secret"} {
+		if strings.Contains(str, secret) {
+			t.Errorf("String() = %s, %s isn't redacted", str, secret)
+		}
+	}
+	for _, want := range []string{"Name:server1", "VcpuCount:2", "Host:host1", "VncPassword:******", "EncryptPassword:******", "<nil>"} {
+		if !strings.Contains(str, want) {
+			t.Errorf("String() = %s, want %s", str, want)
+		}
+	}
+}
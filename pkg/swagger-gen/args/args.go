@@ -0,0 +1,90 @@
+package args
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/gengo/args"
+
+	"yunion.io/x/code-generator/pkg/common/cache"
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+// CustomArgs is used by the gengo framework to pass args specific to swagger-gen.
+type CustomArgs struct {
+	// SpecFormat if set, swagger-gen emits an OpenAPI 3.0 document of this
+	// format instead of go-swagger comment stubs.
+	SpecFormat string
+	// MetaConfig is the YAML or JSON file of spec meta, e.g. title, host and
+	// security schemes, defaults of onecloud are used if empty.
+	MetaConfig string
+	// PatternConfig is the YAML or JSON file enabling or disabling method
+	// patterns per input package, all patterns are enabled if empty.
+	PatternConfig string
+	// Merge if set, operations of all input packages are emitted into one
+	// OpenAPI spec of the service instead of one spec per package.
+	Merge bool
+	// ConflictReport is the JSON file conflicts found by Merge are written
+	// to, e.g. duplicated operationIds.
+	ConflictReport string
+	// Cache skips the run if its inputs and outputs are unchanged.
+	Cache cache.Options
+}
+
+// NewDefaults returns default arguments for the generator.
+func NewDefaults() (*args.GeneratorArgs, *CustomArgs) {
+	genericArgs := args.Default().WithoutDefaultFlagParsing()
+	customArgs := &CustomArgs{
+		Cache: cache.NewOptions(),
+	}
+	genericArgs.CustomArgs = customArgs
+	genericArgs.OutputFileBaseName = "zz_generated.swagger_spec"
+	return genericArgs, customArgs
+}
+
+// AddFlags add the generator flags to the flag set.
+func (ca *CustomArgs) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&ca.SpecFormat, "spec-format", ca.SpecFormat, fmt.Sprintf("Emit an OpenAPI 3.0 spec file of format %q or %q instead of go-swagger comments", openapi.FormatYAML, openapi.FormatJSON))
+	fs.StringVar(&ca.MetaConfig, "meta-config", ca.MetaConfig, "YAML or JSON file of spec meta, e.g. title, version, host, contact and security schemes")
+	fs.StringVar(&ca.PatternConfig, "pattern-config", ca.PatternConfig, "YAML or JSON file enabling or disabling method patterns, e.g. perform and get-spec, per input package")
+	fs.BoolVar(&ca.Merge, "merge", ca.Merge, "Emit one OpenAPI spec of all input packages, requires --spec-format")
+	fs.StringVar(&ca.ConflictReport, "conflict-report", ca.ConflictReport, "JSON file the conflicts of --merge are written to, e.g. duplicated operationIds and routes")
+	ca.Cache.AddFlags(fs)
+}
+
+// IsOpenAPI returns true if an OpenAPI document should be emitted.
+func (ca *CustomArgs) IsOpenAPI() bool {
+	return ca.SpecFormat != ""
+}
+
+// Validate checks the given arguments.
+func Validate(genericArgs *args.GeneratorArgs) error {
+	customArgs, ok := genericArgs.CustomArgs.(*CustomArgs)
+	if !ok {
+		return fmt.Errorf("custom args type %T is not *CustomArgs", genericArgs.CustomArgs)
+	}
+	if len(genericArgs.OutputPackagePath) == 0 {
+		return fmt.Errorf("output package cannot be empty")
+	}
+	switch customArgs.SpecFormat {
+	case "", openapi.FormatYAML, openapi.FormatJSON:
+	default:
+		return fmt.Errorf("unsupported spec format %q", customArgs.SpecFormat)
+	}
+	if customArgs.Merge && !customArgs.IsOpenAPI() {
+		return fmt.Errorf("--merge requires --spec-format")
+	}
+	if customArgs.ConflictReport != "" && !customArgs.Merge {
+		return fmt.Errorf("--conflict-report requires --merge")
+	}
+	return nil
+}
+
+// GetCustomArgs returns the CustomArgs of genericArgs, defaults are used if
+// the caller doesn't set it.
+func GetCustomArgs(genericArgs *args.GeneratorArgs) *CustomArgs {
+	if customArgs, ok := genericArgs.CustomArgs.(*CustomArgs); ok {
+		return customArgs
+	}
+	return &CustomArgs{}
+}
@@ -0,0 +1,165 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/gengo/generator"
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/pkg/util/sets"
+	"yunion.io/x/pkg/utils"
+)
+
+// definitions names the parameter and response definitions of a spec by
+// their fingerprints, so routes of the same shape share one definition.
+type definitions struct {
+	// names maps fingerprint to definition name
+	names map[string]string
+	used  sets.String
+}
+
+func newDefinitions() *definitions {
+	return &definitions{
+		names: make(map[string]string),
+		used:  sets.NewString(),
+	}
+}
+
+// name returns the definition name of fingerprint. The first unused one of
+// candidates is taken by a new fingerprint, the last candidate is returned
+// as is if all are used.
+func (d *definitions) name(fingerprint string, candidates ...string) string {
+	if name, ok := d.names[fingerprint]; ok {
+		return name
+	}
+	name := candidates[len(candidates)-1]
+	for _, c := range candidates {
+		if c != "" && !d.used.Has(c) {
+			name = c
+			break
+		}
+	}
+	d.names[fingerprint] = name
+	d.used.Insert(name)
+	return name
+}
+
+// definitionName returns the name definitions of named type t derive from,
+// empty if t isn't named.
+func definitionName(t *types.Type) string {
+	if t == nil || !isNamedType(t) {
+		return ""
+	}
+	return t.Name.Name
+}
+
+func typeFingerprint(t *types.Type) string {
+	if t == nil {
+		return ""
+	}
+	return t.String()
+}
+
+func pairsFingerprint(pairs map[string]string) string {
+	ret := make([]string, 0, len(pairs))
+	for k, v := range pairs {
+		ret = append(ret, k+"="+v)
+	}
+	sort.Strings(ret)
+	return strings.Join(ret, ",")
+}
+
+// fingerprint returns the shape of parameters, the singular only counts if
+// it's written to the id description or body key.
+func (r *parameter) fingerprint() string {
+	body := r.getBody()
+	singular := ""
+	if r.withId || body != nil {
+		singular = r.singular
+	}
+	return fmt.Sprintf("parameter id=%v singular=%s paths=%s list=%v query=%s body=%s count=%v",
+		r.withId, singular, pairsFingerprint(r.paths), r.isList,
+		typeFingerprint(r.getQuery()), typeFingerprint(body), r.bodyWithCount)
+}
+
+// definitionNames returns the candidate names of parameters, derived from
+// the body or query type and falling back to the operationId.
+func (r *parameter) definitionNames() []string {
+	names := make([]string, 0, 2)
+	if name := definitionName(r.getBody()); name != "" {
+		names = append(names, name+"Params")
+	} else if name := definitionName(r.getQuery()); name != "" {
+		names = append(names, name+"Params")
+	} else if r.withId && r.singular != "" && len(r.paths) == 0 && !r.isList {
+		names = append(names, utils.Kebab2Camel(r.singular, "_")+"IdParams")
+	}
+	return append(names, r.operationId)
+}
+
+// bodyFingerprint returns the shape of request body.
+func (r *parameter) bodyFingerprint() string {
+	return fmt.Sprintf("body singular=%s body=%s count=%v", r.singular, typeFingerprint(r.getBody()), r.bodyWithCount)
+}
+
+func (r *parameter) bodyDefinitionNames() []string {
+	names := make([]string, 0, 2)
+	if name := definitionName(r.getBody()); name != "" {
+		names = append(names, name+"Body")
+	}
+	return append(names, r.operationId+"Body")
+}
+
+func (r *response) fingerprint() string {
+	return fmt.Sprintf("response output=%s key=%s list=%v offset=%v headers=%s",
+		typeFingerprint(r.getOutput()), r.bodyKey, r.isList, r.isListOffset, pairsFingerprint(r.headers))
+}
+
+// definitionNames returns the candidate names of response, derived from the
+// output type and falling back to the id given by the route.
+func (r *response) definitionNames() []string {
+	names := make([]string, 0, 2)
+	if name := definitionName(r.getOutput()); name != "" {
+		if r.isList {
+			name += "List"
+		}
+		names = append(names, name+"Response")
+	}
+	return append(names, r.id)
+}
+
+// commentEmitter collects operations and writes go-swagger comment stubs of
+// them when the file is finalized, routes of the same parameters or
+// response share one definition.
+type commentEmitter struct {
+	commenters []*commenter
+}
+
+func (e *commentEmitter) emit(c *commenter) {
+	e.commenters = append(e.commenters, c)
+}
+
+func (e *commentEmitter) flush(sw *generator.SnippetWriter) {
+	defs := newDefinitions()
+	paramNames := make([]string, len(e.commenters))
+	operations := make(map[string][]string)
+	for i, c := range e.commenters {
+		name := defs.name(c.parameter.fingerprint(), c.parameter.definitionNames()...)
+		paramNames[i] = name
+		operations[name] = append(operations[name], c.parameter.operationId)
+		c.response.id = defs.name(c.response.fingerprint(), c.response.definitionNames()...)
+	}
+	written := sets.NewString()
+	for i, c := range e.commenters {
+		c.route.Do(sw)
+		if name := paramNames[i]; !written.Has(name) {
+			written.Insert(name)
+			c.parameter.doShared(sw, name, operations[name])
+		}
+		if name := c.response.id; !written.Has(name) {
+			written.Insert(name)
+			c.response.Do(sw)
+		}
+	}
+}
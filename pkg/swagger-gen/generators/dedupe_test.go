@@ -0,0 +1,72 @@
+package generators
+
+import (
+	"testing"
+
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+func TestDefinitions(t *testing.T) {
+	pkg := "yunion.io/x/onecloud/pkg/apis/compute"
+	details := &types.Type{Name: types.Name{Package: pkg, Name: "ServerDetails"}, Kind: types.Struct}
+	resp := func(id, key string, isList bool) *response {
+		return &response{id: id, output: details, bodyKey: key, isList: isList}
+	}
+
+	defs := newDefinitions()
+	name := func(r *response) string {
+		return defs.name(r.fingerprint(), r.definitionNames()...)
+	}
+	for _, c := range []struct {
+		resp *response
+		want string
+	}{
+		{resp("server_GetOutput", "server", false), "ServerDetailsResponse"},
+		// same shape shares the definition
+		{resp("server_UpdateOutput", "server", false), "ServerDetailsResponse"},
+		{resp("server_ListOutput", "servers", true), "ServerDetailsListResponse"},
+		// name of the type is taken by another shape
+		{resp("server_PingOutput", "", false), "server_PingOutput"},
+		{&response{id: "server_PurgeOutput"}, "server_PurgeOutput"},
+		{&response{id: "disk_PurgeOutput"}, "server_PurgeOutput"},
+	} {
+		if got := name(c.resp); got != c.want {
+			t.Errorf("name of %s = %s, want %s", c.resp.id, got, c.want)
+		}
+	}
+
+	params := func(id, singular string) *parameter {
+		p := newParameter(singular, singular+"s", id)
+		p.withId = true
+		return p
+	}
+	a, b, c := params("server_Get", "server"), params("server_GetVnc", "server"), params("disk_Get", "disk")
+	if a.fingerprint() != b.fingerprint() || a.fingerprint() == c.fingerprint() {
+		t.Errorf("fingerprints of id parameters: %q, %q, %q", a.fingerprint(), b.fingerprint(), c.fingerprint())
+	}
+	if names := a.definitionNames(); names[0] != "ServerIdParams" {
+		t.Errorf("definition names = %v", names)
+	}
+}
+
+func TestOpenAPISpecResponseConflict(t *testing.T) {
+	s := newOpenAPISpec(NewDefaultMetaConfig("compute"), openapi.FormatYAML)
+	s.source = "yunion.io/x/onecloud/pkg/compute/models"
+	s.doc.Components.Responses["models_VersionOutput"] = &openapi.Response{Description: "OK"}
+	if id := s.responseID("models_VersionOutput", s.doc.Components.Responses["models_VersionOutput"]); id != "models_VersionOutput" {
+		t.Fatalf("id = %s", id)
+	}
+
+	s.source = "yunion.io/x/onecloud/pkg/image/models"
+	if id := s.responseID("models_VersionOutput", &openapi.Response{Description: "OK"}); id != "models_VersionOutput" {
+		t.Errorf("equal response isn't shared: %s", id)
+	}
+	if id := s.responseID("models_VersionOutput", &openapi.Response{Description: "Version"}); id != "image_models_VersionOutput" {
+		t.Errorf("different response isn't renamed: %s", id)
+	}
+	if len(s.conflicts) != 1 || s.conflicts[0].Kind != ConflictResponse {
+		t.Errorf("conflicts = %v", s.conflicts)
+	}
+}
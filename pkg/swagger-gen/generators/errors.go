@@ -0,0 +1,160 @@
+package generators
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/gengo/generator"
+
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+const (
+	// errorResponseId is the shared response of onecloud error envelope
+	errorResponseId = "ErrorOutput"
+	// errorSchemaName is the schema of onecloud error envelope
+	errorSchemaName = "ErrorBody"
+)
+
+var (
+	// defaultErrorCodes are documented on every route
+	defaultErrorCodes = []int{
+		http.StatusBadRequest,
+		http.StatusUnauthorized,
+		http.StatusForbidden,
+		http.StatusNotFound,
+		http.StatusConflict,
+		http.StatusInternalServerError,
+	}
+)
+
+// errorBodyDefinition declares the go-swagger types of onecloud error
+// envelope, it's generated into doc.go.
+const errorBodyDefinition = `
+// ErrorBody is the error envelope returned by onecloud services.
+type ErrorBody struct {
+	Error struct {
+		// The http status code
+		Code int ` + "`json:\"code\"`" + `
+		// The error class, e.g. ResourceNotFoundError
+		Class string ` + "`json:\"class\"`" + `
+		// The error details
+		Details string ` + "`json:\"details\"`" + `
+	} ` + "`json:\"error\"`" + `
+}
+
+// Error of the request
+// swagger:response ErrorOutput
+type ErrorOutput struct {
+	// in:body
+	Body ErrorBody
+}
+`
+
+// extractSwaggerRespErrors parses "<code>,<class>" values of resp error
+// tags, classes of the same code are merged.
+func extractSwaggerRespErrors(subject string, comments []string) map[int][]string {
+	return parseCommentTags(subject, TagTargetMethod, comments, nil).errors()
+}
+
+// errorCodes returns the sorted error codes of route, the default codes
+// and the codes of resp error tags.
+func (r route) errorCodes() []int {
+	codes := make([]int, 0, len(defaultErrorCodes)+len(r.errors))
+	codes = append(codes, defaultErrorCodes...)
+	for code := range r.errors {
+		found := false
+		for _, c := range defaultErrorCodes {
+			if c == code {
+				found = true
+				break
+			}
+		}
+		if !found {
+			codes = append(codes, code)
+		}
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+// errorResponseId returns the response id of error code, classes
+// documented errors use their own response.
+func (r route) errorResponseId(code int) string {
+	if len(r.errors[code]) == 0 {
+		return errorResponseId
+	}
+	return fmt.Sprintf("%sError%d", r.parameter.operationId, code)
+}
+
+func errorDescription(code int, classes []string) string {
+	return fmt.Sprintf("%s: %s", http.StatusText(code), strings.Join(classes, ", "))
+}
+
+// doErrors writes go-swagger responses of the documented error classes.
+func (r route) doErrors(sw *generator.SnippetWriter) {
+	for _, code := range r.errorCodes() {
+		classes := r.errors[code]
+		if len(classes) == 0 {
+			continue
+		}
+		id := r.errorResponseId(code)
+		h := newSW(sw)
+		h.line(errorDescription(code, classes))
+		h.line(fmt.Sprintf("swagger:response %s", id))
+		sw.Do(fmt.Sprintf("type %s struct {\n", id), nil)
+		h.line("in:body")
+		sw.Do(fmt.Sprintf("Body %s\n", errorSchemaName), nil)
+		sw.Do("}\n\n", nil)
+	}
+}
+
+func errorSchema() *openapi.Schema {
+	return openapi.ObjectSchema(map[string]*openapi.Schema{
+		"error": openapi.ObjectSchema(map[string]*openapi.Schema{
+			"code": {
+				Type:        openapi.TypeInteger,
+				Description: "The http status code",
+			},
+			"class": {
+				Type:        openapi.TypeString,
+				Description: "The error class, e.g. ResourceNotFoundError",
+			},
+			"details": {
+				Type:        openapi.TypeString,
+				Description: "The error details",
+			},
+		}),
+	})
+}
+
+// addErrorComponents registers the shared error schema and response.
+func addErrorComponents(doc *openapi.Document) {
+	doc.Components.Schemas[errorSchemaName] = errorSchema()
+	doc.Components.Responses[errorResponseId] = &openapi.Response{
+		Description: "Error of the request",
+		Content:     openapi.JSONContent(openapi.RefSchema(errorSchemaName)),
+	}
+}
+
+// errorResponses returns OpenAPI error responses of route keyed by code.
+func (r route) errorResponses() map[string]*openapi.Response {
+	ret := make(map[string]*openapi.Response)
+	for _, code := range r.errorCodes() {
+		classes := r.errors[code]
+		var resp *openapi.Response
+		if len(classes) == 0 {
+			resp = &openapi.Response{Ref: openapi.ComponentResponsesPrefix + errorResponseId}
+		} else {
+			resp = &openapi.Response{
+				Description: errorDescription(code, classes),
+				Content:     openapi.JSONContent(openapi.RefSchema(errorSchemaName)),
+			}
+		}
+		ret[strconv.Itoa(code)] = resp
+	}
+	return ret
+}
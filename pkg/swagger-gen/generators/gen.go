@@ -3,8 +3,8 @@ package generators
 import (
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
-	"strconv"
 	"strings"
 
 	"k8s.io/gengo/args"
@@ -19,6 +19,8 @@ import (
 
 	"yunion.io/x/code-generator/pkg/common"
 	"yunion.io/x/code-generator/pkg/common/inflection"
+	swaggerargs "yunion.io/x/code-generator/pkg/swagger-gen/args"
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
 )
 
 const (
@@ -70,6 +72,25 @@ const (
 	tagModelSingular = "onecloud:swagger-gen-model-singular"
 	// 设置 swagger model manager 的复数
 	tagModelPlural = "onecloud:swagger-gen-model-plural"
+
+	// 声明方法可能返回的错误，格式为 <code>,<class>，如 404,ResourceNotFoundError
+	tagRespError = "onecloud:swagger-gen-resp-error"
+
+	// 设置结构体字段的可选值，以逗号分隔，如 running,ready
+	tagFieldEnum = "onecloud:swagger-gen-field-enum"
+	// 设置结构体字段的默认值
+	tagFieldDefault = "onecloud:swagger-gen-field-default"
+	// 如果该值设置，则结构体字段是必填的
+	tagFieldRequired = "onecloud:swagger-gen-field-required"
+	// 设置结构体数值字段的最小值和最大值
+	tagFieldMinimum = "onecloud:swagger-gen-field-minimum"
+	tagFieldMaximum = "onecloud:swagger-gen-field-maximum"
+	// 设置结构体字符串字段需要匹配的正则表达式
+	tagFieldPattern = "onecloud:swagger-gen-field-pattern"
+	// 设置结构体字段的示例值
+	tagFieldExample = "onecloud:swagger-gen-field-example"
+	// 如果该值设置，则结构体字段已废弃
+	tagFieldDeprecated = "onecloud:swagger-gen-field-deprecated"
 )
 
 func extractTagByName(comments []string, tagName string) []string {
@@ -81,123 +102,14 @@ func extractIgnoreTag(comments []string) []string {
 }
 
 func extractSwaggerRoute(comments []string) *SwaggerConfigRoute {
-	// 1. get route method
-	vals := extractTagByName(comments, tagRouteMethod)
-	if len(vals) == 0 {
-		return nil
-	}
-	route := new(SwaggerConfigRoute)
-	route.Method = vals[0]
-	// 2. get route path
-	vals = extractTagByName(comments, tagRoutePath)
-	if len(vals) == 0 {
-		return nil
-	}
-	route.Path = vals[0]
-	// 3. get route tags
-	vals = extractTagByName(comments, tagRouteTag)
-	if len(vals) == 0 {
-		return nil
-	}
-	route.Tags = vals
-	return route
-}
-
-func fetchTagIdx(params []*types.Type, comments []string, tagName string) *types.Type {
-	vals := extractTagByName(comments, tagName)
-	if len(vals) == 0 {
-		return nil
-	}
-	idx, err := strconv.Atoi(vals[0])
-	if err != nil {
-		log.Errorf("invalid tag %s=%s", tagName, vals[0])
-		return nil
-	}
-	// params := ut.Signature.Parameters
-	if idx >= len(params) {
-		log.Errorf("invalid tag %s=%s, only %d arguments", tagName, vals[0], len(params))
-		return nil
-	}
-	return params[idx]
-}
-
-func extractSwaggerParam(ut *types.Type, comments []string) *SwaggerConfigParam {
-	var paths map[string]string
-	vals := extractTagByName(comments, tagParamPath)
-	if len(vals) > 0 {
-		paths = make(map[string]string)
-		for i := 0; i < len(vals); i += 2 {
-			k := vals[i]
-			v := k
-			if i+1 < len(vals) {
-				v = vals[i+1]
-			}
-			paths[k] = v
-		}
-	}
-	query := fetchTagIdx(ut.Signature.Parameters, comments, tagParamQueryIdx)
-	body := fetchTagIdx(ut.Signature.Parameters, comments, tagParamBodyIdx)
-	if paths == nil && query == nil && body == nil {
-		return nil
-	}
-	param := new(SwaggerConfigParam)
-	param.Query = query
-	param.Body = body
-	param.Paths = paths
-	vals = extractTagByName(comments, tagParamBodyKey)
-	if len(vals) > 0 {
-		param.Key = vals[0]
-	}
-	return param
+	return parseCommentTags("", TagTargetFunc, comments, nil).swaggerRoute()
 }
 
-func extractSwaggerResponse(ut *types.Type, comments []string) *SwaggerConfigResponse {
-	respType := fetchTagIdx(ut.Signature.Results, comments, tagRespIdx)
-	if respType == nil {
-		return nil
-	}
-
-	resp := new(SwaggerConfigResponse)
-	vals := extractTagByName(comments, tagRespBodyKey)
-	if len(vals) > 0 {
-		resp.BodyKey = vals[0]
-	}
-	vals = extractTagByName(comments, tagRespHeader)
-	if len(vals) > 0 {
-		resp.Headers = make(map[string]string)
-		for i := 0; i < len(vals); i += 2 {
-			k := vals[i]
-			v := k
-			if i+1 < len(vals) {
-				v = vals[i+1]
-			}
-			resp.Headers[k] = v
-		}
-	}
-	resp.Output = respType
-	vals = extractTagByName(comments, tagRespBodyList)
-	if len(vals) > 0 {
-		resp.IsList = true
-		vals = extractTagByName(comments, tagRespBodyListOffset)
-		if len(vals) > 0 {
-			resp.IsListOffset = true
-		}
-	}
-	return resp
-}
-
-func extractSwaggerConfig(ut *types.Type, comments []string) *SwaggerConfig {
-	route := extractSwaggerRoute(comments)
-	if route == nil {
-		return nil
-	}
-	param := extractSwaggerParam(ut, comments)
-	resp := extractSwaggerResponse(ut, comments)
-	return &SwaggerConfig{
-		Route:    route,
-		Param:    param,
-		Response: resp,
-	}
+// extractSwaggerConfig returns the route config declared by tags of
+// function t, nil is returned if the route isn't complete.
+func extractSwaggerConfig(t *types.Type) *SwaggerConfig {
+	sig := t.Underlying.Signature
+	return parseCommentTags(t.String(), TagTargetFunc, t.SecondClosestCommentLines, sig).swaggerConfig(sig)
 }
 
 func extractTagSingleValue(t *types.Type, tagKey string) string {
@@ -228,7 +140,13 @@ func getFunctionHasSwaggerConfig(t *types.Type) *SwaggerConfig {
 	if t.Kind != types.DeclarationOf {
 		return nil
 	}
-	return extractSwaggerConfig(t.Underlying, t.SecondClosestCommentLines)
+	return extractSwaggerConfig(t)
+}
+
+// hasSwaggerRoute returns true if function t is tagged as a route, the
+// tags are validated when the route is generated.
+func hasSwaggerRoute(t *types.Type) bool {
+	return t.Kind == types.DeclarationOf && len(extractTagByName(t.SecondClosestCommentLines, tagRouteMethod)) > 0
 }
 
 func NameSystems() namer.NameSystems {
@@ -248,6 +166,7 @@ func Packages(ctx *generator.Context, arguments *args.GeneratorArgs) generator.P
 	if err != nil {
 		klog.Fatalf("Failed loading boilerplate: %v", err)
 	}
+	customArgs := swaggerargs.GetCustomArgs(arguments)
 	pkgs := generator.Packages{}
 	inputs := sets.NewString(ctx.Inputs...)
 	header := append([]byte(fmt.Sprintf("// +build !%s\n\n", arguments.GeneratedBuildTag)), boilerplate...)
@@ -255,7 +174,46 @@ func Packages(ctx *generator.Context, arguments *args.GeneratorArgs) generator.P
 	outPkgName := strings.Split(filepath.Base(arguments.OutputPackagePath), ".")[0]
 	pkgPath := arguments.OutputPackagePath
 	svcName := outPkgName
-	pkgs = append(pkgs, NewDocPackage(outPkgName, pkgPath, header, svcName))
+	meta, err := LoadMetaConfig(customArgs.MetaConfig, svcName)
+	if err != nil {
+		klog.Fatalf("Failed loading meta config: %v", err)
+	}
+	patternConf, err := LoadPatternConfig(customArgs.PatternConfig)
+	if err != nil {
+		klog.Fatalf("Failed loading pattern config: %v", err)
+	}
+	if customArgs.IsOpenAPI() {
+		registerOpenAPIFileType(ctx)
+	} else {
+		pkgs = append(pkgs, NewDocPackage(outPkgName, pkgPath, header, meta))
+	}
+	if arguments.VerifyOnly {
+		common.RegisterVerifyFileTypes(ctx, os.Stdout)
+	} else {
+		customArgs.Cache.RegisterFileTypes(ctx)
+	}
+	if customArgs.Merge {
+		sources := make([]string, 0, len(inputs))
+		for _, i := range inputs.List() {
+			if ctx.Universe[i] != nil {
+				sources = append(sources, i)
+			}
+		}
+		klog.Infof("Merging pkgs %v", sources)
+		return append(pkgs, &generator.DefaultPackage{
+			PackageName: outPkgName,
+			PackagePath: pkgPath,
+			HeaderText:  header,
+			GeneratorFunc: func(c *generator.Context) []generator.Generator {
+				return []generator.Generator{
+					NewMergedOpenAPIGen(arguments.OutputFileBaseName, svcName, sources, ctx.Order, patternConf, meta, customArgs.SpecFormat, customArgs.ConflictReport),
+				}
+			},
+			FilterFunc: func(c *generator.Context, t *types.Type) bool {
+				return inputs.Has(t.Name.Package)
+			},
+		})
+	}
 	for i := range inputs {
 		pkg := ctx.Universe[i]
 		if pkg == nil {
@@ -268,9 +226,13 @@ func Packages(ctx *generator.Context, arguments *args.GeneratorArgs) generator.P
 				PackagePath: pkgPath,
 				HeaderText:  header,
 				GeneratorFunc: func(c *generator.Context) []generator.Generator {
+					gen := NewSwaggerGen(arguments.OutputFileBaseName, pkg.Path, ctx.Order, patternConf.Patterns(pkg.Path))
+					if customArgs.IsOpenAPI() {
+						gen = NewOpenAPIGen(gen, meta, customArgs.SpecFormat)
+					}
 					return []generator.Generator{
 						// Generate swagger code by model.
-						NewSwaggerGen(arguments.OutputFileBaseName, pkg.Path, ctx.Order),
+						gen,
 					}
 				},
 				FilterFunc: func(c *generator.Context, t *types.Type) bool {
@@ -287,10 +249,18 @@ type swaggerGen struct {
 	sourcePackage string
 	modelTypes    sets.String
 	modelManagers map[string]*types.Type
+	// patterns are the method patterns enabled for source package
+	patterns []MethodPattern
+
+	// spec is not nil when an OpenAPI document is emitted instead of
+	// go-swagger comments
+	spec *openapiSpec
+	// comments collects operations of go-swagger comments
+	comments *commentEmitter
 }
 
-func NewSwaggerGen(sanitizedName, sourcePackage string, pkgTypes []*types.Type) generator.Generator {
-	ident := filepath.Base(strings.TrimRight(sourcePackage, "models"))
+func NewSwaggerGen(sanitizedName, sourcePackage string, pkgTypes []*types.Type, patterns []MethodPattern) generator.Generator {
+	ident := packageIdent(sourcePackage)
 	gen := &swaggerGen{
 		DefaultGen: generator.DefaultGen{
 			OptionalName: fmt.Sprintf("%s_%s", sanitizedName, ident),
@@ -298,12 +268,59 @@ func NewSwaggerGen(sanitizedName, sourcePackage string, pkgTypes []*types.Type)
 		sourcePackage: sourcePackage,
 		modelTypes:    sets.NewString(),
 		modelManagers: make(map[string]*types.Type),
+		patterns:      patterns,
+		comments:      new(commentEmitter),
 	}
 	gen.collectTypes(pkgTypes)
 	log.Infof("modelTypes: %v, modelManagers: %v", gen.modelTypes.List(), gen.modelManagers)
 	return gen
 }
 
+// NewOpenAPIGen makes swagger generator gen emit an OpenAPI 3.0 document of
+// format with service meta.
+func NewOpenAPIGen(gen generator.Generator, meta *MetaConfig, format string) generator.Generator {
+	g := gen.(*swaggerGen)
+	g.spec = newOpenAPISpec(meta, format)
+	g.spec.source = g.sourcePackage
+	return g
+}
+
+func (g *swaggerGen) Filename() string {
+	if g.spec != nil {
+		return g.OptionalName + openapi.Extension(g.spec.format)
+	}
+	return g.DefaultGen.Filename()
+}
+
+func (g *swaggerGen) FileType() string {
+	if g.spec != nil {
+		return openapiFileType
+	}
+	return g.DefaultGen.FileType()
+}
+
+func (g *swaggerGen) Finalize(c *generator.Context, w io.Writer) error {
+	if g.spec == nil {
+		sw := generator.NewSnippetWriter(w, c, "$", "$")
+		g.comments.flush(sw)
+		return sw.Error()
+	}
+	g.spec.logConflicts()
+	out, err := g.spec.Marshal()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+func (g *swaggerGen) emitter() emitter {
+	if g.spec != nil {
+		return g.spec
+	}
+	return g.comments
+}
+
 func (g *swaggerGen) collectTypes(pkgTypes []*types.Type) {
 	common.CollectModelManager(g.sourcePackage, pkgTypes, g.modelTypes, g.modelManagers)
 }
@@ -316,11 +333,8 @@ func (g *swaggerGen) Filter(c *generator.Context, t *types.Type) bool {
 	if IncludeIgnoreTag(t) {
 		return false
 	}
-	if t.Kind == types.DeclarationOf {
-		swaggerCfg := getFunctionHasSwaggerConfig(t)
-		if swaggerCfg != nil {
-			return true
-		}
+	if hasSwaggerRoute(t) {
+		return true
 	}
 	if g.modelTypes.Has(t.String()) {
 		return true
@@ -330,52 +344,60 @@ func (g *swaggerGen) Filter(c *generator.Context, t *types.Type) bool {
 
 func (g *swaggerGen) GenerateType(c *generator.Context, t *types.Type, w io.Writer) error {
 	klog.V(2).Infof("Generating api model for type %s", t)
-	sw := generator.NewSnippetWriter(w, c, "$", "$")
+	g.generate(t, g.emitter())
+	return nil
+}
+
+// generate emits the operations of swagger annotated function or model t.
+func (g *swaggerGen) generate(t *types.Type, e emitter) {
 	if t.Kind == types.DeclarationOf {
-		g.generateDeclarationCode(t, sw)
-	} else {
-		mm := g.getModelManager(t)
-		if mm == nil {
-			log.Errorf("Not found model type %s manager", t.String())
-			return nil
-		}
-		g.generateCode(g.getModelManager(t), t, sw)
+		g.generateDeclarationCode(t, e)
+		return
+	}
+	mm := g.getModelManager(t)
+	if mm == nil {
+		log.Errorf("Not found model type %s manager", t.String())
+		return
 	}
-	return sw.Error()
+	g.generateCode(mm, t, e)
 }
 
-func (g *swaggerGen) generateDeclarationCode(t *types.Type, sw *generator.SnippetWriter) {
+func (g *swaggerGen) generateDeclarationCode(t *types.Type, e emitter) {
 	config := getFunctionHasSwaggerConfig(t)
-	config.generate(t, sw)
+	if config == nil {
+		return
+	}
+	config.generate(t, e)
 }
 
-func (g *swaggerGen) generateCode(manType *types.Type, modelType *types.Type, sw *generator.SnippetWriter) {
+func (g *swaggerGen) generateCode(manType *types.Type, modelType *types.Type, e emitter) {
 	if IncludeIgnoreTag(manType) || IncludeIgnoreTag(modelType) {
 		// do nothing
 		return
 	}
 
-	parser := newTypeParser(manType, modelType)
-
-	getM := parser.getM()
-	generateGet(getM, sw)
-	generateCreate(parser.createM(), getM, sw)
-	lm := parser.listM()
-	generateList(lm, getM, sw)
-	generateUpdate(parser.updateM(), getM, sw)
-	generateDelete(parser.deleteM(), getM, sw)
-
-	applyGenerateFunc(generateGetSpec, parser.getSpecM, sw)
-	applyGenerateFunc(generatePerformAction, parser.performActionM, sw)
-	applyGenerateFunc(generateGetProperty, parser.getPropertyM, sw)
-	applyGenerateFunc(generateClassPerformAction, parser.performClassActionM, sw)
+	res := parseResourceMethods(manType, modelType, g.patterns)
+	for _, p := range g.patterns {
+		for _, m := range res.Methods[p.Name()] {
+			generateOperation(p, m, res, e)
+		}
+	}
 }
 
-func applyGenerateFunc(genFunc func(*Method, *generator.SnippetWriter), getMethods func() []*Method, sw *generator.SnippetWriter) {
-	methods := getMethods()
-	for i := range methods {
-		genFunc(methods[i], sw)
+// generateOperation emits the operation of method m matched by pattern p.
+func generateOperation(p MethodPattern, m *Method, res *ResourceMethods, e emitter) {
+	param := p.Parameter(newParameterFactory(m))
+	resp := p.Response(newResponseFactory(m), res)
+	if param == nil || resp == nil {
+		return
+	}
+	route := p.Route(newRouteFactory(m), param, resp)
+	c := &commenter{
+		route:     route,
+		parameter: param,
+		response:  resp,
 	}
+	e.emit(c)
 }
 
 const (
@@ -522,7 +544,7 @@ func newTypeParser(man *types.Type, model *types.Type) *typeParser {
 
 func getManagerKeyword(manName string) string {
 	name := strings.TrimSuffix(manName, "Manager")
-	if len(name) == 0 && len(name) == 1 {
+	if len(name) <= 1 {
 		log.Fatalf("Invalid manager struct name: %s", manName)
 	}
 	isUpper := func(x byte) bool {
@@ -576,164 +598,27 @@ func isValidType(t *types.Type) error {
 	return nil
 }
 
-func (p *typeParser) createM() *Method {
-	return p.getMethod(Create, p.manager,
-		func(m *Method) bool {
-			sig := m.Signature()
-			paramsLen := len(sig.Parameters)
-			retLen := len(sig.Results)
-			// ValidateCreateData(context.Context, mcclient.TokenCredential, mcclient.IIdentityProvider, query jsonutils.JSONObject, data *jsonutils.JSONDict) (Object, error)
-			if paramsLen != 5 || retLen != 2 {
-				return false
-			}
-			return true
-		},
-	)
-}
-
-func (p *typeParser) listM() *Method {
-	return p.getMethod(List, p.manager,
-		func(m *Method) bool {
-			sig := m.Signature()
-			paramsLen := len(sig.Parameters)
-			retLen := len(sig.Results)
-			// ListItemFilter(context.Context, *sqlchemy.SQuery, mcclient.TokenCredential, query jsonutils.JSONObject) (*sqlchemy.SQuery, error)
-			if paramsLen != 4 || retLen != 2 {
-				return false
-			}
-			return true
-		})
-}
-
-func (p *typeParser) getM() *Method {
-	return p.getMethod(Get, p.manager,
-		func(m *Method) bool {
-			sig := m.Signature()
-			paramsLen := len(sig.Parameters)
-			retLen := len(sig.Results)
-			// FetchCustomizeColumns(ctx context.Context, userCred mcclient.TokenCredential, query jsonutils.JSONObject, objs []interface{}, fields stringutils2.SSortedStrings, isList bool) []api.ScriptApplyRecordDetails
-			if (paramsLen == 6) && retLen == 1 {
-				return true
-			}
-			return false
-		})
-}
-
-func (p *typeParser) updateM() *Method {
-	return p.getMethod(Update, p.model, func(m *Method) bool {
-		sig := m.Signature()
-		paramsLen := len(sig.Parameters)
-		retLen := len(sig.Results)
-		// ValidateUpdateData(context.Context, mcclient.TokenCredential, query Object, data Object) (Object, error)
-		if paramsLen != 4 || retLen != 2 {
-			return false
-		}
-		return true
-	})
-}
-
-func (p *typeParser) deleteM() *Method {
-	return p.getMethod(Delete, p.model, func(m *Method) bool {
-		sig := m.Signature()
-		paramsLen := len(sig.Parameters)
-		retLen := len(sig.Results)
-		// CustomizeDelete(context.Context, mcclient.TokenCredential, query Object, body Object) error
-		if paramsLen != 4 || retLen != 1 {
-			return false
+// patternMethods returns the methods matched by pattern, they are sorted
+// by name if the pattern matches multiple methods.
+func (p *typeParser) patternMethods(pattern MethodPattern) []*Method {
+	receiver := p.model
+	if pattern.Receiver() == ReceiverManager {
+		receiver = p.manager
+	}
+	ms := p.getMethods(pattern.Prefix(), receiver, pattern.Match)
+	if !pattern.Multiple() {
+		if len(ms) > 1 {
+			ms = ms[:1]
 		}
-		return true
-	})
-}
-
-func (p *typeParser) performActionM() []*Method {
-	return p.getMethods(Perform, p.model,
-		func(m *Method) bool {
-			sig := m.Signature()
-			paramsLen := len(sig.Parameters)
-			retLen := len(sig.Results)
-			if paramsLen != 4 || retLen != 2 || sig.Parameters[1].Name.Name != "TokenCredential" {
-				return false
-			}
-			body := sig.Parameters[3]
-			output := sig.Results[0]
-			// input body and output must struct pointer
-			if err := validInputOutput(body, output); err != nil {
-				log.Warningf("validInputOutput for method %s: %v", m.String(), err)
-				//return false
-			}
-			return true
-		},
-	)
-}
-
-func (p *typeParser) performClassActionM() []*Method {
-	return p.getMethods(Perform, p.manager,
-		func(m *Method) bool {
-			sig := m.Signature()
-			paramsLen := len(sig.Parameters)
-			retLen := len(sig.Results)
-			if paramsLen != 4 || retLen != 2 {
-				return false
-			}
-			body := sig.Parameters[3]
-			output := sig.Results[0]
-			// input body and output must struct pointer
-			if err := validInputOutput(body, output); err != nil {
-				log.Warningf("validInputOutput for method %s: %v", m.String(), err)
-				//return false
-			}
-			return true
-		},
-	)
-}
-
-func (p *typeParser) getSpecM() []*Method {
-	return p.getMethods(GetSpec, p.model,
-		func(m *Method) bool {
-			paramsLen := len(m.Signature().Parameters)
-			retLen := len(m.Signature().Results)
-			if paramsLen != 3 || retLen != 2 {
-				return false
-			}
-			sig := m.Signature()
-			//input := sig.Parameters[2]
-			output := sig.Results[0]
-			if err := isValidType(output); err != nil {
-				log.Warningf("method %s: output type is invalid: %v", m.String(), err)
-				//return false
-			}
-			return true
-		},
-	)
-}
-
-func (p *typeParser) getPropertyM() []*Method {
-	return p.getMethods(GetProperty, p.manager,
-		func(m *Method) bool {
-			sig := m.Signature()
-			paramsLen := len(sig.Parameters)
-			retLen := len(sig.Results)
-			// GetPropertyLatest(ctx context.Context, userCred mcclient.TokenCredential, query jsonutils.JSONObject) (Object, error)
-			if paramsLen != 3 || retLen != 2 {
-				return false
-			}
-			return true
-		},
-	)
+		return ms
+	}
+	return sortMethods(ms)
 }
 
 func (p *typeParser) getMethods(funcPreKeyword string, model *types.Type, preF func(*Method) bool) []*Method {
 	return getTypeMethods(funcPreKeyword, p.singular, p.plural, model, preF)
 }
 
-func (p *typeParser) getMethod(funcPreKeyword string, model *types.Type, preF func(*Method) bool) *Method {
-	ms := p.getMethods(funcPreKeyword, model, preF)
-	if len(ms) == 0 {
-		return nil
-	}
-	return ms[0]
-}
-
 func getArgs(t *types.Type) interface{} {
 	if t == nil {
 		return nil
@@ -747,16 +632,6 @@ type commenter struct {
 	response  *response
 }
 
-func (c commenter) Do(sw *generator.SnippetWriter) {
-	for _, f := range []func(*generator.SnippetWriter){
-		c.route.Do,
-		c.parameter.Do,
-		c.response.Do,
-	} {
-		f(sw)
-	}
-}
-
 type snippetWriter struct {
 	sw *generator.SnippetWriter
 }
@@ -767,7 +642,7 @@ func newSW(sw *generator.SnippetWriter) *snippetWriter {
 
 func (w snippetWriter) lines(lines []string) {
 	for _, l := range lines {
-		w.sw.Do(fmt.Sprintf("// %s\n", l), nil)
+		w.sw.Do("// $.$\n", l)
 	}
 }
 
@@ -778,138 +653,3 @@ func (w snippetWriter) emptyLine() {
 func (w snippetWriter) line(l string) {
 	w.lines([]string{l})
 }
-
-func generateCreate(createMethod, getMethod *Method, sw *generator.SnippetWriter) {
-	if createMethod == nil || getMethod == nil {
-		return
-	}
-	param := newParameterFactory(createMethod).Create()
-	resp := newResponseFactory(createMethod).ResultByGetMethod(getMethod)
-	route := newRouteFactory(createMethod).Create(param, resp)
-	c := &commenter{
-		route:     route,
-		parameter: param,
-		response:  resp,
-	}
-	c.Do(sw)
-}
-
-func generateList(listMethod, getMethod *Method, sw *generator.SnippetWriter) {
-	if listMethod == nil || getMethod == nil {
-		return
-	}
-	param := newParameterFactory(listMethod).List()
-	resp := newResponseFactory(listMethod).ListResult(getMethod)
-	route := newRouteFactory(listMethod).List(param, resp)
-	c := &commenter{
-		route:     route,
-		parameter: param,
-		response:  resp,
-	}
-	c.Do(sw)
-}
-
-func generateGet(method *Method, sw *generator.SnippetWriter) {
-	if method == nil {
-		return
-	}
-	param := newParameterFactory(method).Get()
-	resp := newResponseFactory(method).FirstSingularResult()
-	route := newRouteFactory(method).Get(param, resp)
-	c := &commenter{
-		route:     route,
-		parameter: param,
-		response:  resp,
-	}
-	c.Do(sw)
-}
-
-func generateUpdate(method, getMethod *Method, sw *generator.SnippetWriter) {
-	if method == nil || getMethod == nil {
-		return
-	}
-	param := newParameterFactory(method).Update()
-	resp := newResponseFactory(method).ResultByGetMethod(getMethod)
-	route := newRouteFactory(method).Update(param, resp)
-	c := &commenter{
-		route:     route,
-		parameter: param,
-		response:  resp,
-	}
-	c.Do(sw)
-}
-
-func generateDelete(method, getMethod *Method, sw *generator.SnippetWriter) {
-	if method == nil || getMethod == nil {
-		return
-	}
-	param := newParameterFactory(method).Delete()
-	resp := newResponseFactory(method).ResultByGetMethod(getMethod)
-	route := newRouteFactory(method).Delete(param, resp)
-	c := &commenter{
-		route:     route,
-		parameter: param,
-		response:  resp,
-	}
-	c.Do(sw)
-}
-
-func generateGetSpec(method *Method, sw *generator.SnippetWriter) {
-	if method == nil {
-		return
-	}
-	param := newParameterFactory(method).GetSpec()
-	resp := newResponseFactory(method).FirstSingularResult()
-	route := newRouteFactory(method).GetSpec(param, resp)
-	c := &commenter{
-		route:     route,
-		parameter: param,
-		response:  resp,
-	}
-	c.Do(sw)
-}
-
-func generatePerformAction(method *Method, sw *generator.SnippetWriter) {
-	if method == nil {
-		return
-	}
-	param := newParameterFactory(method).PerformAction()
-	resp := newResponseFactory(method).FirstSingularResultNoError()
-	route := newRouteFactory(method).PerformAction(param, resp)
-	c := &commenter{
-		route:     route,
-		parameter: param,
-		response:  resp,
-	}
-	c.Do(sw)
-}
-
-func generateClassPerformAction(method *Method, sw *generator.SnippetWriter) {
-	if method == nil {
-		return
-	}
-	param := newParameterFactory(method).PerformClassAction()
-	resp := newResponseFactory(method).FirstSingularResultNoError()
-	route := newRouteFactory(method).PerformClassAction(param, resp)
-	c := &commenter{
-		route:     route,
-		parameter: param,
-		response:  resp,
-	}
-	c.Do(sw)
-}
-
-func generateGetProperty(method *Method, sw *generator.SnippetWriter) {
-	if method == nil {
-		return
-	}
-	param := newParameterFactory(method).GetProperty()
-	resp := newResponseFactory(method).FirstSingularResultNoError()
-	route := newRouteFactory(method).GetProperty(param, resp)
-	c := &commenter{
-		route:     route,
-		parameter: param,
-		response:  resp,
-	}
-	c.Do(sw)
-}
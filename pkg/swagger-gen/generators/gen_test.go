@@ -25,6 +25,25 @@ func Test_extractSwaggerRoute(t *testing.T) {
 				Tags:   []string{"tag1", "tag2"},
 			},
 		},
+		{
+			name: "error input",
+			comments: []string{
+				"+onecloud:swagger-gen-route-method=GET",
+				"+onecloud:swagger-gen-route-path=/v2.0/tokens",
+				"+onecloud:swagger-gen-route-tag=tag1",
+				"+onecloud:swagger-gen-resp-error=401,InvalidCredentialError",
+				"+onecloud:swagger-gen-resp-error=401,NotFoundError",
+				"+onecloud:swagger-gen-resp-error=invalid,Error",
+			},
+			want: &SwaggerConfigRoute{
+				Method: "GET",
+				Path:   "/v2.0/tokens",
+				Tags:   []string{"tag1"},
+				Errors: map[int][]string{
+					401: {"InvalidCredentialError", "NotFoundError"},
+				},
+			},
+		},
 		{
 			name: "no method input",
 			comments: []string{
@@ -59,3 +78,35 @@ func Test_extractSwaggerRoute(t *testing.T) {
 		})
 	}
 }
+
+func Test_routeErrorCodes(t *testing.T) {
+	r := route{
+		parameter: newParameter("server", "servers", "server_PerformStart"),
+		errors: map[int][]string{
+			400: {"InputParameterError"},
+			503: {"ServiceUnavailable"},
+		},
+	}
+	want := []int{400, 401, 403, 404, 409, 500, 503}
+	if got := r.errorCodes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("errorCodes() = %v, want %v", got, want)
+	}
+	if got := r.errorResponseId(400); got != "server_PerformStartError400" {
+		t.Errorf("errorResponseId(400) = %s", got)
+	}
+	if got := r.errorResponseId(404); got != errorResponseId {
+		t.Errorf("errorResponseId(404) = %s", got)
+	}
+}
+
+func Test_packageIdent(t *testing.T) {
+	for pkg, want := range map[string]string{
+		"yunion.io/x/onecloud/pkg/compute/models":  "compute",
+		"yunion.io/x/onecloud/pkg/keystone/tokens": "tokens",
+		"yunion.io/x/onecloud/pkg/monitor/metrics": "metrics",
+	} {
+		if got := packageIdent(pkg); got != want {
+			t.Errorf("packageIdent(%q) = %q, want %q", pkg, got, want)
+		}
+	}
+}
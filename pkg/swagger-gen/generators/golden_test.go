@@ -0,0 +1,66 @@
+package generators
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"yunion.io/x/code-generator/pkg/common/golden"
+	swaggerargs "yunion.io/x/code-generator/pkg/swagger-gen/args"
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+func TestGolden(t *testing.T) {
+	for _, c := range []struct {
+		name       string
+		specFormat string
+	}{
+		{"comment", ""},
+		{"openapi", openapi.FormatYAML},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			arguments, customArgs := swaggerargs.NewDefaults()
+			arguments.InputDirs = []string{"yunion.io/x/onecloud/pkg/compute/models"}
+			arguments.OutputPackagePath = "yunion.io/x/onecloud/pkg/generated/swagger/compute"
+			customArgs.SpecFormat = c.specFormat
+			dir := golden.Run(t, arguments, NameSystems(), DefaultNameSystem(), Packages)
+			golden.Compare(t, dir, filepath.Join("testdata", "golden", c.name))
+		})
+	}
+}
+
+func TestGoldenMerge(t *testing.T) {
+	arguments, customArgs := swaggerargs.NewDefaults()
+	arguments.InputDirs = []string{
+		"yunion.io/x/onecloud/pkg/compute/models",
+		"yunion.io/x/onecloud/pkg/image/models",
+	}
+	arguments.OutputPackagePath = "yunion.io/x/onecloud/pkg/generated/swagger/compute"
+	customArgs.SpecFormat = openapi.FormatYAML
+	customArgs.Merge = true
+	customArgs.ConflictReport = filepath.Join(t.TempDir(), "conflicts.json")
+	dir := golden.Run(t, arguments, NameSystems(), DefaultNameSystem(), Packages)
+	golden.Compare(t, dir, filepath.Join("testdata", "golden", "merge"))
+
+	data, err := ioutil.ReadFile(customArgs.ConflictReport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflicts := make([]*SpecConflict, 0)
+	if err := json.Unmarshal(data, &conflicts); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, 0, len(conflicts))
+	for _, c := range conflicts {
+		got = append(got, string(c.Kind)+" "+c.Name)
+	}
+	want := []string{
+		"operation-id models_Ping",
+		"route GET /ping",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("conflicts = %v, want %v", got, want)
+	}
+}
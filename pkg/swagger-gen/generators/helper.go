@@ -3,6 +3,7 @@ package generators
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"k8s.io/gengo/generator"
@@ -53,6 +54,8 @@ func (f *routeFactory) newRoute(action string, input *parameter, output *respons
 		},
 	}
 	commentLines := method.Method().CommentLines
+	r.errors = extractSwaggerRespErrors(method.String(), commentLines)
+	commentLines = removeTagLines(commentLines)
 	if len(commentLines) > 0 {
 		r.summary = commentLines[0]
 	}
@@ -152,6 +155,8 @@ type route struct {
 	summary     string
 	description []string
 	response    map[int]*response
+	// errors are the error classes declared by tags, keyed by http code
+	errors map[int][]string
 }
 
 func (r route) Do(sw *generator.SnippetWriter) {
@@ -173,11 +178,19 @@ func (r route) Do(sw *generator.SnippetWriter) {
 	}
 	h.emptyLine()
 	h.line("responses:")
-	// TODO: responses for errors
-	for code, resp := range r.response {
-		h.line(fmt.Sprintf("%d: %s", code, resp.id))
+	codes := make([]int, 0, len(r.response))
+	for code := range r.response {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		h.line(fmt.Sprintf("%d: %s", code, r.response[code].id))
+	}
+	for _, code := range r.errorCodes() {
+		h.line(fmt.Sprintf("%d: %s", code, r.errorResponseId(code)))
 	}
 	sw.Do("\n", nil)
+	r.doErrors(sw)
 }
 
 type paramterFactory struct {
@@ -219,6 +232,7 @@ func (f *paramterFactory) List() *parameter {
 	} else {
 		p.errorMsgs = append(p.errorMsgs, fmt.Sprintf("unsupport query type: %v", err))
 	}
+	p.isList = true
 	return p
 }
 
@@ -335,6 +349,8 @@ type parameter struct {
 	query         *types.Type
 	body          *types.Type
 	bodyWithCount bool
+	// isList means the shared list parameters are accepted too
+	isList bool
 
 	paths map[string]string
 
@@ -349,10 +365,12 @@ func newParameter(singular, plural, id string) *parameter {
 		errorMsgs:   make([]string, 0),
 	}
 }
-func (r parameter) Do(sw *generator.SnippetWriter) {
+
+// doShared writes parameters struct name shared by operations.
+func (r parameter) doShared(sw *generator.SnippetWriter, name string, operations []string) {
 	h := newSW(sw)
-	h.line(fmt.Sprintf("swagger:parameters %s", r.operationId))
-	r.do(sw, h)
+	h.line(fmt.Sprintf("swagger:parameters %s", strings.Join(operations, " ")))
+	r.do(sw, h, name)
 }
 
 func getValidType(t *types.Type) *types.Type {
@@ -390,8 +408,8 @@ func (r parameter) getBody() *types.Type {
 	return GetValidType(r.body)
 }
 
-func (r parameter) do(sw *generator.SnippetWriter, h *snippetWriter) {
-	sw.Do(fmt.Sprintf("type %s struct {\n", r.operationId), nil)
+func (r parameter) do(sw *generator.SnippetWriter, h *snippetWriter, name string) {
+	sw.Do(fmt.Sprintf("type %s struct {\n", name), nil)
 	if r.withId {
 		h.line(fmt.Sprintf("The Id or Name of %s", r.singular))
 		h.line("in:path")
@@ -404,7 +422,9 @@ func (r parameter) do(sw *generator.SnippetWriter, h *snippetWriter) {
 		sw.Do(fmt.Sprintf("%s string `json:\"%s\"`\n", strings.Title(k), k), nil)
 	}
 	query := r.getQuery()
-	if query != nil {
+	if r.isList {
+		r.doListQuery(query, sw, h)
+	} else if query != nil {
 		args := getArgs(query)
 		sw.Do("$.type|raw$\n", args)
 	}
@@ -476,12 +496,10 @@ func (r response) bodyStruct(output *types.Type, sw *generator.SnippetWriter) {
 	args := getArgs(output)
 	sw.Do("Body struct {\n", nil)
 	if r.isList {
-		sw.Do(fmt.Sprintf("Output []$.type|raw$ `json:\"%s\"`\n", r.bodyKey), args)
 		if r.isListOffset {
-			sw.Do("Limit int `json:\"limit\"`\n", nil)
-			sw.Do("Total int `json:\"total\"`\n", nil)
-			sw.Do("Offset int `json:\"offset\"`\n", nil)
+			sw.Do(listResultMetaName+"\n", nil)
 		}
+		sw.Do(fmt.Sprintf("Output []$.type|raw$ `json:\"%s\"`\n", r.bodyKey), args)
 	} else {
 		sw.Do(fmt.Sprintf("Output $.type|raw$ `json:\"%s\"`\n", r.bodyKey), args)
 	}
@@ -549,6 +567,7 @@ type SwaggerConfigRoute struct {
 	Method string
 	Path   string
 	Tags   []string
+	Errors map[int][]string
 }
 
 func (c *SwaggerConfigRoute) newRoute(input *parameter, output *response) *route {
@@ -560,6 +579,7 @@ func (c *SwaggerConfigRoute) newRoute(input *parameter, output *response) *route
 		response: map[int]*response{
 			200: output,
 		},
+		errors: c.Errors,
 	}
 	return r
 }
@@ -574,6 +594,10 @@ type SwaggerConfigParam struct {
 func (c *SwaggerConfigParam) newParameter(t *types.Type) *parameter {
 	n := filepath.Base(t.Name.Package)
 	param := newParameter("", "", privateName(n, t.Name.Name))
+	if c == nil {
+		// the route has no parameter tags
+		return param
+	}
 	param.query = c.Query
 	param.body = c.Body
 	param.paths = c.Paths
@@ -592,6 +616,13 @@ type SwaggerConfigResponse struct {
 
 func (c *SwaggerConfigResponse) newResponse(t *types.Type) *response {
 	n := filepath.Base(t.Name.Package)
+	if c == nil {
+		// the route has no response tags
+		return &response{
+			id:        fmt.Sprintf("%sOutput", privateName(n, t.Name.Name)),
+			errorMsgs: make([]string, 0),
+		}
+	}
 	r := &response{
 		id:        fmt.Sprintf("%sOutput", privateName(n, t.Name.Name)),
 		bodyKey:   c.BodyKey,
@@ -611,7 +642,7 @@ type SwaggerConfig struct {
 	Response *SwaggerConfigResponse
 }
 
-func (c *SwaggerConfig) generate(t *types.Type, sw *generator.SnippetWriter) {
+func (c *SwaggerConfig) generate(t *types.Type, e emitter) {
 	param := c.Param.newParameter(t)
 	resp := c.Response.newResponse(t)
 	route := c.Route.newRoute(param, resp)
@@ -631,5 +662,17 @@ func (c *SwaggerConfig) generate(t *types.Type, sw *generator.SnippetWriter) {
 		parameter: param,
 		response:  resp,
 	}
-	cc.Do(sw)
+	e.emit(cc)
+}
+
+// removeTagLines drops the comment tag lines, e.g. +onecloud:swagger-gen-resp-error
+func removeTagLines(lines []string) []string {
+	ret := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(l), "+") {
+			continue
+		}
+		ret = append(ret, l)
+	}
+	return ret
 }
@@ -0,0 +1,247 @@
+package generators
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"k8s.io/gengo/args"
+)
+
+// LintTags parses the sources of input packages and writes the misuse of
+// swagger-gen comment tags with their positions to w, the errors are
+// returned too.
+func LintTags(arguments *args.GeneratorArgs, w io.Writer) ([]*TagError, error) {
+	b, err := arguments.NewBuilder()
+	if err != nil {
+		return nil, err
+	}
+	u, err := b.FindTypes()
+	if err != nil {
+		return nil, err
+	}
+	pkgs := make([]string, 0)
+	for path, pkg := range u {
+		if arguments.InputIncludes(pkg) && pkg.SourcePath != "" {
+			pkgs = append(pkgs, path)
+		}
+	}
+	sort.Strings(pkgs)
+	errs := make([]*TagError, 0)
+	for _, path := range pkgs {
+		pkgErrs, err := lintPackageTags(u[path].SourcePath)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, pkgErrs...)
+	}
+	for _, e := range errs {
+		fmt.Fprintf(w, "%s\n", e)
+	}
+	fmt.Fprintf(w, "%d tag error(s)\n", len(errs))
+	return errs, nil
+}
+
+// lintPackageTags checks the tags of declarations of package in dir.
+func lintPackageTags(dir string) ([]*TagError, error) {
+	fset := token.NewFileSet()
+	filter := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(fset, dir, filter, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(pkgs))
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errs := make([]*TagError, 0)
+	for _, name := range names {
+		files := make([]string, 0)
+		for file := range pkgs[name].Files {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			l := newTagLinter(fset, name, pkgs[name].Files[file])
+			errs = append(errs, l.lint()...)
+		}
+	}
+	return errs, nil
+}
+
+// tagLinter finds the comment groups of declarations as gengo does.
+type tagLinter struct {
+	fset    *token.FileSet
+	pkgName string
+	file    *ast.File
+	// endLines maps the end line of comment groups to them
+	endLines map[int]*ast.CommentGroup
+}
+
+func newTagLinter(fset *token.FileSet, pkgName string, file *ast.File) *tagLinter {
+	l := &tagLinter{
+		fset:     fset,
+		pkgName:  pkgName,
+		file:     file,
+		endLines: make(map[int]*ast.CommentGroup),
+	}
+	for _, c := range file.Comments {
+		l.endLines[fset.Position(c.End()).Line] = c
+	}
+	return l
+}
+
+// priorComment returns the comment group ending lines before pos.
+func (l *tagLinter) priorComment(pos token.Pos, lines int) *ast.CommentGroup {
+	return l.endLines[l.fset.Position(pos).Line-lines]
+}
+
+func (l *tagLinter) tagLines(c *ast.CommentGroup) []TagLine {
+	lines := make([]TagLine, 0)
+	if c == nil {
+		return lines
+	}
+	for _, comment := range c.List {
+		if !strings.HasPrefix(comment.Text, "//") {
+			continue
+		}
+		lines = append(lines, TagLine{
+			Text:     strings.TrimPrefix(comment.Text, "//"),
+			Position: l.fset.Position(comment.Slash).String(),
+		})
+	}
+	return lines
+}
+
+func (l *tagLinter) parse(subject string, target TagTarget, c *ast.CommentGroup, ft *ast.FuncType) []*TagError {
+	d := &TagDecl{
+		Subject: subject,
+		Target:  target,
+		Lines:   l.tagLines(c),
+		Params:  -1,
+		Results: -1,
+	}
+	if ft != nil {
+		d.Params, d.Results = fieldCount(ft.Params), fieldCount(ft.Results)
+	}
+	_, errs := ParseTags(d)
+	return errs
+}
+
+func fieldCount(fields *ast.FieldList) int {
+	if fields == nil {
+		return 0
+	}
+	n := 0
+	for _, f := range fields.List {
+		if len(f.Names) == 0 {
+			n++
+		} else {
+			n += len(f.Names)
+		}
+	}
+	return n
+}
+
+func (l *tagLinter) lint() []*TagError {
+	errs := make([]*TagError, 0)
+	for _, decl := range l.file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				subject := l.pkgName + "." + ts.Name.Name
+				errs = append(errs, l.parse(subject, TagTargetType, l.priorComment(ts.Pos(), 1), nil)...)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					for _, name := range fieldNames(field) {
+						_, fieldErrs := ParseFieldMarkers(subject+"."+name, l.tagLines(field.Doc))
+						errs = append(errs, fieldErrs...)
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				subject := fmt.Sprintf("%s.%s.%s", l.pkgName, receiverName(d.Recv.List[0].Type), d.Name.Name)
+				errs = append(errs, l.parse(subject, TagTargetMethod, l.priorComment(d.Pos(), 1), d.Type)...)
+				continue
+			}
+			errs = append(errs, l.lintFunc(d)...)
+		}
+	}
+	return errs
+}
+
+// lintFunc checks a function, its route tags are read from the comment
+// group separated from the doc comment by a blank line.
+func (l *tagLinter) lintFunc(d *ast.FuncDecl) []*TagError {
+	subject := l.pkgName + "." + d.Name.Name
+	doc := l.priorComment(d.Pos(), 1)
+	var second *ast.CommentGroup
+	if doc == nil {
+		second = l.priorComment(d.Pos(), 2)
+	} else {
+		second = l.priorComment(doc.List[0].Slash, 2)
+	}
+	errs := l.parse(subject, TagTargetFunc, second, d.Type)
+	for _, line := range l.tagLines(doc) {
+		text := strings.TrimSpace(line.Text)
+		if !strings.HasPrefix(text, tagPrefix) || strings.HasPrefix(text[1:], tagIgnoreName) {
+			continue
+		}
+		name := strings.SplitN(text[1:], "=", 2)[0]
+		errs = append(errs, &TagError{
+			Position: line.Position,
+			Subject:  subject,
+			Tag:      name,
+			Message:  "is ignored in the doc comment, route tags must be in the comment block separated from the doc comment by a blank line",
+		})
+	}
+	return errs
+}
+
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+// fieldNames returns names of field, the type name is used for embedded one.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, len(field.Names))
+		for i, n := range field.Names {
+			names[i] = n.Name
+		}
+		return names
+	}
+	t := field.Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch x := t.(type) {
+	case *ast.Ident:
+		return []string{x.Name}
+	case *ast.SelectorExpr:
+		return []string{x.Sel.Name}
+	}
+	return nil
+}
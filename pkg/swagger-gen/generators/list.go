@@ -0,0 +1,262 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/gengo/generator"
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/pkg/util/sets"
+
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+const (
+	// listQueryName is the shared query parameters of list operations
+	listQueryName = "ListQuery"
+	// listResultMetaName is the shared pagination fields of list results
+	listResultMetaName = "ListResultMeta"
+)
+
+var (
+	// listBaseInputTypes are the base list input types embedded by list
+	// query structs, their fields are replaced by the shared parameters.
+	listBaseInputTypes = sets.NewString(
+		"yunion.io/x/onecloud/pkg/apis.BaseListInput",
+	)
+)
+
+// listQueryParam is a query parameter accepted by all list operations.
+type listQueryParam struct {
+	// component is the name of shared OpenAPI parameter
+	component string
+	field     string
+	goType    string
+	name      string
+	desc      string
+	enum      []string
+}
+
+// listQueryParams are handled by the list dispatcher of every resource.
+var listQueryParams = []listQueryParam{
+	{
+		component: "ListLimit",
+		field:     "Limit",
+		goType:    "*int",
+		name:      "limit",
+		desc:      "Max number of returned records, 0 returns all records",
+	},
+	{
+		component: "ListOffset",
+		field:     "Offset",
+		goType:    "*int",
+		name:      "offset",
+		desc:      "Number of records skipped of offset pagination",
+	},
+	{
+		component: "ListMarker",
+		field:     "Marker",
+		goType:    "string",
+		name:      "marker",
+		desc:      "Returns records after the marker of marker pagination",
+	},
+	{
+		component: "ListPagingMarker",
+		field:     "PagingMarker",
+		goType:    "string",
+		name:      "paging_marker",
+		desc:      "The next_marker of previous page of marker pagination",
+	},
+	{
+		component: "ListOrderBy",
+		field:     "OrderBy",
+		goType:    "[]string",
+		name:      "order_by",
+		desc:      "Fields the records are ordered by",
+	},
+	{
+		component: "ListOrder",
+		field:     "Order",
+		goType:    "string",
+		name:      "order",
+		desc:      "Order of records",
+		enum:      []string{"asc", "desc"},
+	},
+	{
+		component: "ListDetails",
+		field:     "Details",
+		goType:    "*bool",
+		name:      "details",
+		desc:      "Returns details of records",
+	},
+	{
+		component: "ListFilter",
+		field:     "Filter",
+		goType:    "[]string",
+		name:      "filter",
+		desc:      "Field filters, e.g. name.contains(web)",
+	},
+	{
+		component: "ListFilterAny",
+		field:     "FilterAny",
+		goType:    "*bool",
+		name:      "filter_any",
+		desc:      "Records matching any of the filters are returned instead of all",
+	},
+	{
+		component: "ListScope",
+		field:     "Scope",
+		goType:    "string",
+		name:      "scope",
+		desc:      "Scope of records",
+		enum:      []string{"system", "domain", "project"},
+	},
+	{
+		component: "ListExportKeys",
+		field:     "ExportKeys",
+		goType:    "string",
+		name:      "export_keys",
+		desc:      "Comma separated fields of exported records",
+	},
+}
+
+// listResultField is a pagination field of list results.
+type listResultField struct {
+	field  string
+	goType string
+	name   string
+	desc   string
+}
+
+var listResultFields = []listResultField{
+	{"Total", "int", "total", "Total number of records"},
+	{"Limit", "int", "limit", "Max number of returned records"},
+	{"Offset", "int", "offset", "Number of records skipped of offset pagination"},
+	{"NextMarker", "string", "next_marker", "Marker of next page of marker pagination, empty if it's the last page"},
+	{"MarkerField", "string", "marker_field", "Field of marker pagination"},
+	{"MarkerOrder", "string", "marker_order", "Order of marker pagination"},
+}
+
+var listQueryParamNames = func() sets.String {
+	ret := sets.NewString()
+	for _, p := range listQueryParams {
+		ret.Insert(p.name)
+	}
+	return ret
+}()
+
+func (p listQueryParam) description() string {
+	if len(p.enum) == 0 {
+		return p.desc
+	}
+	return fmt.Sprintf("%s, one of %s", p.desc, strings.Join(p.enum, ", "))
+}
+
+// goTypeSchema returns the schema of go type expressions of list tables.
+func goTypeSchema(goType string) *openapi.Schema {
+	switch goType {
+	case "int", "*int":
+		return &openapi.Schema{Type: openapi.TypeInteger, Format: "int64"}
+	case "bool", "*bool":
+		return &openapi.Schema{Type: openapi.TypeBoolean}
+	case "[]string":
+		return openapi.ArraySchema(&openapi.Schema{Type: openapi.TypeString})
+	default:
+		return &openapi.Schema{Type: openapi.TypeString}
+	}
+}
+
+func (p listQueryParam) parameter() *openapi.Parameter {
+	s := goTypeSchema(p.goType)
+	for _, e := range p.enum {
+		s.Enum = append(s.Enum, e)
+	}
+	return &openapi.Parameter{
+		Name:        p.name,
+		In:          openapi.ParamInQuery,
+		Description: p.description(),
+		Schema:      s,
+	}
+}
+
+// listDefinition declares the go-swagger types of list query and result,
+// it's generated into doc.go.
+func listDefinition() string {
+	buf := new(strings.Builder)
+	fmt.Fprintf(buf, "\n// %s is the query parameters accepted by all list operations.\n", listQueryName)
+	fmt.Fprintf(buf, "type %s struct {\n", listQueryName)
+	for _, p := range listQueryParams {
+		fmt.Fprintf(buf, "\t// %s\n", p.description())
+		fmt.Fprintf(buf, "\t%s %s `json:\"%s\"`\n", p.field, p.goType, p.name)
+	}
+	buf.WriteString("}\n")
+	fmt.Fprintf(buf, "\n// %s is the pagination fields of list results.\n", listResultMetaName)
+	fmt.Fprintf(buf, "type %s struct {\n", listResultMetaName)
+	for _, f := range listResultFields {
+		fmt.Fprintf(buf, "\t// %s\n", f.desc)
+		fmt.Fprintf(buf, "\t%s %s `json:\"%s\"`\n", f.field, f.goType, f.name)
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// addListComponents registers the shared list parameters and result schema.
+func addListComponents(doc *openapi.Document) {
+	for _, p := range listQueryParams {
+		doc.Components.Parameters[p.component] = p.parameter()
+	}
+	props := make(map[string]*openapi.Schema)
+	for _, f := range listResultFields {
+		s := goTypeSchema(f.goType)
+		s.Description = f.desc
+		props[f.name] = s
+	}
+	doc.Components.Schemas[listResultMetaName] = openapi.ObjectSchema(props)
+}
+
+// listParameterRefs returns references to the shared list parameters.
+func listParameterRefs() []*openapi.Parameter {
+	ret := make([]*openapi.Parameter, 0, len(listQueryParams))
+	for _, p := range listQueryParams {
+		ret = append(ret, &openapi.Parameter{Ref: openapi.ComponentParametersPrefix + p.component})
+	}
+	return ret
+}
+
+// isListQueryMember returns true if member m of list query is covered by
+// the shared list parameters.
+func isListQueryMember(m types.Member) bool {
+	if m.Embedded {
+		return listBaseInputTypes.Has(derefType(m.Type).String())
+	}
+	return listQueryParamNames.Has(memberJSONName(m))
+}
+
+// doListQuery writes fields of list query struct t, the shared parameters
+// are embedded by ListQuery instead.
+func (r parameter) doListQuery(t *types.Type, sw *generator.SnippetWriter, h *snippetWriter) {
+	sw.Do(listQueryName+"\n", nil)
+	if t == nil {
+		return
+	}
+	for _, m := range queryMembers(t, isListQueryMember) {
+		if desc := CommentDescription(m.CommentLines); desc != "" {
+			h.lines(strings.Split(desc, "\n"))
+		}
+		h.lines(MemberMarkers(t, m).goSwaggerLines())
+		sw.Do(fmt.Sprintf("%s $.type|raw$ `json:\"%s\"`\n", m.Name, memberJSONName(m)), getArgs(m.Type))
+	}
+}
+
+// listBodySchema returns the list result schema of items keyed by bodyKey.
+func listBodySchema(bodyKey string, items *openapi.Schema) *openapi.Schema {
+	return &openapi.Schema{
+		AllOf: []*openapi.Schema{
+			openapi.RefSchema(listResultMetaName),
+			openapi.ObjectSchema(map[string]*openapi.Schema{
+				bodyKey: openapi.ArraySchema(items),
+			}),
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package generators
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/gengo/types"
+)
+
+func TestListQueryMembers(t *testing.T) {
+	str := types.String
+	base := &types.Type{
+		Name: types.Name{Package: "yunion.io/x/onecloud/pkg/apis", Name: "BaseListInput"},
+		Kind: types.Struct,
+		Members: []types.Member{
+			{Name: "Scope", Type: str, Tags: `json:"scope"`},
+			{Name: "Tenant", Type: str, Tags: `json:"tenant"`},
+		},
+	}
+	resource := &types.Type{
+		Name: types.Name{Package: "yunion.io/x/onecloud/pkg/apis", Name: "ResourceListInput"},
+		Kind: types.Struct,
+		Members: []types.Member{
+			{Name: "BaseListInput", Type: base, Embedded: true},
+			{Name: "Limit", Type: &types.Type{Kind: types.Pointer, Elem: types.Int}, Tags: `json:"limit"`},
+			{Name: "Names", Type: &types.Type{Kind: types.Slice, Elem: str}, Tags: `json:"name"`},
+		},
+	}
+	query := &types.Type{
+		Name: types.Name{Package: "yunion.io/x/onecloud/pkg/apis/compute", Name: "ServerListInput"},
+		Kind: types.Struct,
+		Members: []types.Member{
+			{Name: "ResourceListInput", Type: resource, Embedded: true},
+			{Name: "Host", Type: str, Tags: `json:"host"`},
+			{Name: "OrderByHost", Type: str, Tags: `json:"order_by_host"`},
+			{Name: "Order", Type: str, Tags: `json:"order"`},
+		},
+	}
+
+	names := func(members []types.Member) []string {
+		ret := make([]string, 0, len(members))
+		for _, m := range members {
+			ret = append(ret, memberJSONName(m))
+		}
+		return ret
+	}
+	got := names(queryMembers(query, isListQueryMember))
+	want := []string{"name", "host", "order_by_host"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("list query members = %v, want %v", got, want)
+	}
+	got = names(queryMembers(query, nil))
+	want = []string{"scope", "tenant", "limit", "name", "host", "order_by_host", "order"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("query members = %v, want %v", got, want)
+	}
+
+	params := newSchemaBuilder(nil).listQueryParameters(query)
+	if len(params) != len(listQueryParams)+3 {
+		t.Fatalf("got %d list parameters", len(params))
+	}
+	if params[0].Ref != "#/components/parameters/ListLimit" {
+		t.Errorf("first parameter = %#v", params[0])
+	}
+}
@@ -0,0 +1,323 @@
+package generators
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+// markerLineRegexp matches the go-swagger style marker lines of field
+// comments, e.g. "enum: running,ready" or "default: 1".
+var markerLineRegexp = regexp.MustCompile(`(?i)^(enum|default|required|minimum|maximum|pattern|example|deprecated)\s*:\s*(.*)$`)
+
+// godocDeprecated is the marker key of godoc deprecation paragraphs.
+const godocDeprecated = "Deprecated"
+
+// markerTags maps the keys of marker lines to the equivalent field tags.
+var markerTags = map[string]string{
+	"enum":       tagFieldEnum,
+	"default":    tagFieldDefault,
+	"required":   tagFieldRequired,
+	"minimum":    tagFieldMinimum,
+	"maximum":    tagFieldMaximum,
+	"pattern":    tagFieldPattern,
+	"example":    tagFieldExample,
+	"deprecated": tagFieldDeprecated,
+}
+
+// IsMarkerLine returns true if comment line is a marker line, e.g.
+// "default: 1".
+func IsMarkerLine(line string) bool {
+	return markerLineRegexp.MatchString(strings.TrimSpace(line))
+}
+
+// FieldMarkers are the documentation and validation constraints of a struct
+// field, given by marker lines or field tags of its comment.
+type FieldMarkers struct {
+	Enum []string
+	// Default and Example are the raw values, they are converted by the type
+	// of field when emitted
+	Default    *string
+	Example    *string
+	Required   bool
+	Minimum    *float64
+	Maximum    *float64
+	Pattern    string
+	Deprecated bool
+}
+
+// markerTagLines converts marker lines to field tags, so both forms are
+// validated by the tag specs. A flag marker, e.g. "required: true", only
+// accepts an empty value, true or false, others are reported and dropped, so
+// prose like "Required: only for KVM hosts" isn't taken as the flag. The
+// godoc "Deprecated: <reason>" paragraph is the exception, it deprecates the
+// field.
+func markerTagLines(subject string, lines []TagLine) ([]TagLine, []*TagError) {
+	ret := make([]TagLine, 0, len(lines))
+	errs := make([]*TagError, 0)
+	for _, line := range lines {
+		m := markerLineRegexp.FindStringSubmatch(strings.TrimSpace(line.Text))
+		if m == nil {
+			ret = append(ret, line)
+			continue
+		}
+		name, value := markerTags[strings.ToLower(m[1])], strings.TrimSpace(m[2])
+		if spec := tagSpecs[name]; spec.Value == TagValueFlag {
+			switch flag := strings.ToLower(value); {
+			case flag == "false":
+				continue
+			case flag == "" || flag == "true" || m[1] == godocDeprecated:
+			default:
+				errs = append(errs, &TagError{
+					Position: line.Position,
+					Subject:  subject,
+					Tag:      name,
+					Message:  fmt.Sprintf("invalid value %q, expect true or false", value),
+				})
+				continue
+			}
+			value = ""
+		}
+		text := "+" + name
+		if value != "" {
+			text += "=" + value
+		}
+		ret = append(ret, TagLine{Text: text, Position: line.Position})
+	}
+	return ret, errs
+}
+
+// ParseFieldMarkers parses the markers of comment lines of field subject and
+// checks they are consistent, e.g. the default is a member of enum.
+func ParseFieldMarkers(subject string, lines []TagLine) (*FieldMarkers, []*TagError) {
+	tagLines, markerErrs := markerTagLines(subject, lines)
+	tags, errs := ParseTags(&TagDecl{
+		Subject: subject,
+		Target:  TagTargetField,
+		Lines:   tagLines,
+		Params:  -1,
+		Results: -1,
+	})
+	errs = append(markerErrs, errs...)
+	m := &FieldMarkers{
+		Required:   tags.Has(tagFieldRequired),
+		Pattern:    tags.value(tagFieldPattern),
+		Deprecated: tags.Has(tagFieldDeprecated),
+	}
+	if tags.Has(tagFieldEnum) {
+		m.Enum = splitValues(tags.value(tagFieldEnum))
+	}
+	if v := tags.first(tagFieldDefault); v != nil {
+		m.Default = &v.raw
+	}
+	if v := tags.first(tagFieldExample); v != nil {
+		m.Example = &v.raw
+	}
+	if v := tags.first(tagFieldMinimum); v != nil {
+		m.Minimum = &v.number
+	}
+	if v := tags.first(tagFieldMaximum); v != nil {
+		m.Maximum = &v.number
+	}
+	fail := func(tag string, format string, args ...interface{}) {
+		errs = append(errs, &TagError{
+			Position: tags.first(tag).line.Position,
+			Subject:  subject,
+			Tag:      tag,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+	if m.Default != nil && len(m.Enum) > 0 {
+		for _, d := range splitValues(*m.Default) {
+			if !containsString(m.Enum, d) {
+				fail(tagFieldDefault, "default %q isn't a member of enum %s", d, strings.Join(m.Enum, ","))
+			}
+		}
+	}
+	if m.Minimum != nil && m.Maximum != nil && *m.Minimum > *m.Maximum {
+		fail(tagFieldMinimum, "minimum %v is greater than maximum %v", *m.Minimum, *m.Maximum)
+	}
+	return m, errs
+}
+
+// MemberMarkers returns the markers of member m of struct t, errors are
+// logged.
+func MemberMarkers(t *types.Type, m types.Member) *FieldMarkers {
+	markers, errs := ParseFieldMarkers(fmt.Sprintf("%s.%s", t.String(), m.Name), commentTagLines(m.CommentLines))
+	logTagErrors(errs)
+	return markers
+}
+
+func splitValues(s string) []string {
+	ret := make([]string, 0)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+// ValueType is the JSON type of a field schema, e.g. integer, marker values
+// are converted to it. Values of an unknown type, e.g. a referred schema,
+// are kept as string.
+type ValueType struct {
+	Type string
+	// Items is the type of array items, nil if unknown
+	Items *ValueType
+}
+
+// MarkerValues are the default, example and enum markers converted to the
+// type of field.
+type MarkerValues struct {
+	Default interface{}
+	Example interface{}
+	// Enum are the values of the field, or of its items if it's an array
+	Enum []interface{}
+}
+
+func typedValue(t *ValueType, raw string) (interface{}, error) {
+	if t == nil {
+		return raw, nil
+	}
+	switch t.Type {
+	case openapi.TypeInteger:
+		return strconv.ParseInt(raw, 10, 64)
+	case openapi.TypeNumber:
+		return strconv.ParseFloat(raw, 64)
+	case openapi.TypeBoolean:
+		return strconv.ParseBool(raw)
+	case openapi.TypeArray:
+		if t.Items == nil {
+			return raw, nil
+		}
+		vals := splitValues(raw)
+		ret := make([]interface{}, 0, len(vals))
+		for _, v := range vals {
+			tv, err := typedValue(t.Items, v)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, tv)
+		}
+		return ret, nil
+	}
+	return raw, nil
+}
+
+// Values converts the markers to values of field of type t, it's shared by
+// the generators of schemas.
+func (m *FieldMarkers) Values(t *ValueType) (*MarkerValues, error) {
+	ret := &MarkerValues{}
+	if m.Default != nil {
+		v, err := typedValue(t, *m.Default)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default %q: %v", *m.Default, err)
+		}
+		ret.Default = v
+	}
+	if m.Example != nil {
+		v, err := typedValue(t, *m.Example)
+		if err != nil {
+			return nil, fmt.Errorf("invalid example %q: %v", *m.Example, err)
+		}
+		ret.Example = v
+	}
+	enumType := t
+	if t != nil && t.Type == openapi.TypeArray && t.Items != nil {
+		enumType = t.Items
+	}
+	for _, e := range m.Enum {
+		v, err := typedValue(enumType, e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid enum %q: %v", e, err)
+		}
+		ret.Enum = append(ret.Enum, v)
+	}
+	return ret, nil
+}
+
+// schemaValueType returns the value type of openapi schema s.
+func schemaValueType(s *openapi.Schema) *ValueType {
+	if s == nil {
+		return nil
+	}
+	return &ValueType{Type: s.Type, Items: schemaValueType(s.Items)}
+}
+
+// IsEmpty returns true if no marker is given.
+func (m *FieldMarkers) IsEmpty() bool {
+	return len(m.Enum) == 0 && m.Default == nil && m.Example == nil && !m.Required &&
+		m.Minimum == nil && m.Maximum == nil && m.Pattern == "" && !m.Deprecated
+}
+
+// Apply returns schema s of the field with the markers except required,
+// which belongs to the parent object. Values not of the type of s are
+// reported.
+func (m *FieldMarkers) Apply(s *openapi.Schema) (*openapi.Schema, error) {
+	if m.IsEmpty() {
+		return s, nil
+	}
+	var ret *openapi.Schema
+	if s.IsRef() {
+		// sibling keywords of $ref are ignored
+		ret = &openapi.Schema{AllOf: []*openapi.Schema{s}}
+	} else {
+		copied := *s
+		ret = &copied
+	}
+	vals, err := m.Values(schemaValueType(ret))
+	if err != nil {
+		return s, err
+	}
+	ret.Deprecated = m.Deprecated
+	ret.Default = vals.Default
+	ret.Example = vals.Example
+	// constraints of array apply to its items
+	target := ret
+	if ret.Type == openapi.TypeArray && ret.Items != nil && (len(m.Enum) > 0 || m.Minimum != nil || m.Maximum != nil || m.Pattern != "") {
+		items := *ret.Items
+		if items.IsRef() {
+			items = openapi.Schema{AllOf: []*openapi.Schema{ret.Items}}
+		}
+		ret.Items = &items
+		target = &items
+	}
+	target.Enum = vals.Enum
+	target.Minimum = m.Minimum
+	target.Maximum = m.Maximum
+	target.Pattern = m.Pattern
+	return ret, nil
+}
+
+// goSwaggerLines returns the markers as go-swagger comment lines.
+func (m *FieldMarkers) goSwaggerLines() []string {
+	lines := make([]string, 0)
+	if len(m.Enum) > 0 {
+		lines = append(lines, "enum: "+strings.Join(m.Enum, ","))
+	}
+	if m.Default != nil {
+		lines = append(lines, "default: "+*m.Default)
+	}
+	if m.Required {
+		lines = append(lines, "required: true")
+	}
+	if m.Minimum != nil {
+		lines = append(lines, fmt.Sprintf("minimum: %v", *m.Minimum))
+	}
+	if m.Maximum != nil {
+		lines = append(lines, fmt.Sprintf("maximum: %v", *m.Maximum))
+	}
+	if m.Pattern != "" {
+		lines = append(lines, "pattern: "+m.Pattern)
+	}
+	if m.Example != nil {
+		lines = append(lines, "example: "+*m.Example)
+	}
+	return lines
+}
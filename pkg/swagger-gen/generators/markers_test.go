@@ -0,0 +1,134 @@
+package generators
+
+import (
+	"reflect"
+	"testing"
+
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+func markerLines(lines ...string) []TagLine {
+	ret := make([]TagLine, len(lines))
+	for i, l := range lines {
+		ret[i] = TagLine{Text: " " + l}
+	}
+	return ret
+}
+
+func TestParseFieldMarkers(t *testing.T) {
+	cases := []struct {
+		name   string
+		lines  []string
+		errors []string
+	}{
+		{
+			name: "markers and tags",
+			lines: []string{
+				"The status of server",
+				"enum: running,ready",
+				"default: ready",
+				"+onecloud:swagger-gen-field-required",
+			},
+		},
+		{
+			name:   "default not in enum",
+			lines:  []string{"enum: running,ready", "default: stopped"},
+			errors: []string{`models.Subject: tag onecloud:swagger-gen-field-default: default "stopped" isn't a member of enum running,ready`},
+		},
+		{
+			name:   "minimum greater than maximum",
+			lines:  []string{"+onecloud:swagger-gen-field-minimum=10", "maximum: 1"},
+			errors: []string{"models.Subject: tag onecloud:swagger-gen-field-minimum: minimum 10 is greater than maximum 1"},
+		},
+		{
+			name:   "invalid number",
+			lines:  []string{"minimum: one"},
+			errors: []string{`models.Subject: tag onecloud:swagger-gen-field-minimum: invalid number "one"`},
+		},
+		{
+			name:   "invalid pattern",
+			lines:  []string{"pattern: [a-z"},
+			errors: []string{"models.Subject: tag onecloud:swagger-gen-field-pattern: invalid pattern \"[a-z\": error parsing regexp: missing closing ]: `[a-z`"},
+		},
+		{
+			name:  "godoc deprecation",
+			lines: []string{"Deprecated: use the boot index of disks instead."},
+		},
+		{
+			name:   "prose of flag marker",
+			lines:  []string{"Required: only for KVM hosts", "deprecated: yes"},
+			errors: []string{`models.Subject: tag onecloud:swagger-gen-field-required: invalid value "only for KVM hosts", expect true or false`, `models.Subject: tag onecloud:swagger-gen-field-deprecated: invalid value "yes", expect true or false`},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			markers, errs := ParseFieldMarkers("models.Subject", markerLines(c.lines...))
+			got := errorMessages(errs)
+			if len(c.errors) > 0 && (markers.Required || markers.Deprecated) {
+				t.Errorf("required = %v, deprecated = %v of invalid markers", markers.Required, markers.Deprecated)
+			}
+			if len(got) == 0 && len(c.errors) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, c.errors) {
+				t.Errorf("errors = %q, want %q", got, c.errors)
+			}
+		})
+	}
+}
+
+func TestFieldMarkersApply(t *testing.T) {
+	markers, errs := ParseFieldMarkers("models.Subject", markerLines(
+		"enum: 1,2,4",
+		"default: 2",
+		"required: true",
+		"deprecated: false",
+	))
+	if len(errs) > 0 {
+		t.Fatalf("errors = %v", errs)
+	}
+	if !markers.Required || markers.Deprecated {
+		t.Errorf("required = %v, deprecated = %v", markers.Required, markers.Deprecated)
+	}
+
+	s, err := markers.Apply(&openapi.Schema{Type: openapi.TypeInteger})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{int64(1), int64(2), int64(4)}; !reflect.DeepEqual(s.Enum, want) {
+		t.Errorf("enum = %#v, want %#v", s.Enum, want)
+	}
+	if s.Default != int64(2) {
+		t.Errorf("default = %#v", s.Default)
+	}
+
+	array, err := markers.Apply(&openapi.Schema{Type: openapi.TypeArray, Items: &openapi.Schema{Type: openapi.TypeInteger}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(array.Enum) != 0 || len(array.Items.Enum) != 3 {
+		t.Errorf("enum of array = %v, items = %v, want the enum of items", array.Enum, array.Items.Enum)
+	}
+
+	ref := openapi.RefSchema("Status")
+	wrapped, err := markers.Apply(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wrapped.AllOf) != 1 || wrapped.AllOf[0] != ref || wrapped.Default != "2" {
+		t.Errorf("schema of ref = %#v, want the ref wrapped by allOf", wrapped)
+	}
+
+	if _, err := markers.Apply(&openapi.Schema{Type: openapi.TypeBoolean}); err == nil {
+		t.Errorf("default 2 of boolean should be invalid")
+	}
+
+	// values of array of unknown items are kept as string
+	vals, err := markers.Values(&ValueType{Type: openapi.TypeArray})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{"1", "2", "4"}; vals.Default != "2" || !reflect.DeepEqual(vals.Enum, want) {
+		t.Errorf("values = %#v, want raw values", vals)
+	}
+}
@@ -0,0 +1,255 @@
+package generators
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/gengo/generator"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+// ConflictKind is the kind of a conflict found when operations of input
+// packages are put into one spec.
+type ConflictKind string
+
+const (
+	// ConflictRoute means a method and path is declared more than once, the
+	// later operation is dropped
+	ConflictRoute ConflictKind = "route"
+	// ConflictOperationID means an operationId is used more than once, the
+	// later one is prefixed by its package
+	ConflictOperationID ConflictKind = "operation-id"
+	// ConflictResponse means packages declare different component responses
+	// of the same id, the later one is prefixed by its package
+	ConflictResponse ConflictKind = "response"
+)
+
+// SpecConflict is a conflict of a generated spec.
+type SpecConflict struct {
+	Kind ConflictKind `json:"kind"`
+	// Name is the conflicting route, operationId or response id
+	Name string `json:"name"`
+	// Packages are the package declared first and the conflicting one
+	Packages []string `json:"packages"`
+	Message  string   `json:"message"`
+}
+
+func (c *SpecConflict) String() string {
+	return fmt.Sprintf("%s conflict %s of %s: %s", c.Kind, c.Name, strings.Join(c.Packages, ", "), c.Message)
+}
+
+// packageIdent returns the short name of source package used by file names
+// and prefixes of renamed operations.
+func packageIdent(sourcePackage string) string {
+	return filepath.Base(strings.TrimSuffix(sourcePackage, "/models"))
+}
+
+func routeKey(method, path string) string {
+	return fmt.Sprintf("%s %s", strings.ToUpper(method), path)
+}
+
+func uniqueStrings(vals []string) []string {
+	if len(vals) == 0 {
+		return vals
+	}
+	ret := make([]string, 0, len(vals))
+	seen := make(map[string]bool)
+	for _, v := range vals {
+		if !seen[v] {
+			seen[v] = true
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func (s *openapiSpec) conflict(kind ConflictKind, name, owner string, format string, args ...interface{}) {
+	s.conflicts = append(s.conflicts, &SpecConflict{
+		Kind:     kind,
+		Name:     name,
+		Packages: []string{owner, s.source},
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// prefixed returns name prefixed by the ident of source package, a number is
+// appended if it's used too.
+func (s *openapiSpec) prefixed(name string, used func(string) bool) string {
+	ret := fmt.Sprintf("%s_%s", packageIdent(s.source), name)
+	for i := 2; used(ret); i++ {
+		ret = fmt.Sprintf("%s_%s%d", packageIdent(s.source), name, i)
+	}
+	return ret
+}
+
+// operationID returns an unused operationId for id.
+func (s *openapiSpec) operationID(id string) string {
+	owner, ok := s.operations[id]
+	if !ok {
+		return id
+	}
+	ret := s.prefixed(id, func(name string) bool {
+		_, ok := s.operations[name]
+		return ok
+	})
+	s.conflict(ConflictOperationID, id, owner, "operationId is used already, renamed to %s", ret)
+	return ret
+}
+
+// responseID returns the component id of response resp. Packages share
+// equal responses, a different one declared by another package is renamed.
+func (s *openapiSpec) responseID(id string, resp *openapi.Response) string {
+	owner, ok := s.responses[id]
+	if !ok || owner == s.source {
+		s.responses[id] = s.source
+		return id
+	}
+	if reflect.DeepEqual(s.doc.Components.Responses[id], resp) {
+		return id
+	}
+	ret := s.prefixed(id, func(name string) bool {
+		_, ok := s.doc.Components.Responses[name]
+		return ok
+	})
+	s.conflict(ConflictResponse, id, owner, "response differs from the declared one, renamed to %s", ret)
+	s.responses[ret] = s.source
+	return ret
+}
+
+// addRouteTags lists all tags of operations once in the document, the
+// described ones of spec meta come first.
+func (s *openapiSpec) addRouteTags() {
+	tags := make([]*openapi.Tag, 0)
+	seen := make(map[string]bool)
+	for _, t := range s.doc.Tags {
+		if !seen[t.Name] {
+			seen[t.Name] = true
+			tags = append(tags, t)
+		}
+	}
+	names := make([]string, 0)
+	for _, item := range s.doc.Paths {
+		for _, op := range item.Operations() {
+			for _, name := range op.Tags {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tags = append(tags, &openapi.Tag{Name: name})
+	}
+	s.doc.Tags = tags
+}
+
+// logConflicts reports the conflicts found when the spec is built.
+func (s *openapiSpec) logConflicts() {
+	for _, c := range s.conflicts {
+		klog.Warningf("%s", c)
+	}
+}
+
+// WriteConflictReport writes conflicts as JSON to file.
+func WriteConflictReport(file string, conflicts []*SpecConflict) error {
+	if conflicts == nil {
+		conflicts = []*SpecConflict{}
+	}
+	out, err := json.MarshalIndent(conflicts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, append(out, '\n'), 0644)
+}
+
+// mergedGen emits operations of all input packages into one OpenAPI spec of
+// the service. Packages are merged in order of import path, so the operation
+// of the former one wins a conflict.
+type mergedGen struct {
+	generator.DefaultGen
+	sourcePackages []string
+	// gens are the swagger generators keyed by source package
+	gens map[string]*swaggerGen
+	// pkgTypes are the filtered types keyed by source package
+	pkgTypes map[string][]*types.Type
+	spec     *openapiSpec
+	// reportFile is the file conflicts are written to if not empty
+	reportFile string
+}
+
+// NewMergedOpenAPIGen returns a generator emitting one spec of format named
+// by sanitizedName and service for the source packages.
+func NewMergedOpenAPIGen(sanitizedName, service string, sourcePackages []string, pkgTypes []*types.Type, patternConf *PatternConfig, meta *MetaConfig, format, reportFile string) generator.Generator {
+	gen := &mergedGen{
+		DefaultGen: generator.DefaultGen{
+			OptionalName: fmt.Sprintf("%s_%s", sanitizedName, service),
+		},
+		sourcePackages: sourcePackages,
+		gens:           make(map[string]*swaggerGen),
+		pkgTypes:       make(map[string][]*types.Type),
+		spec:           newOpenAPISpec(meta, format),
+		reportFile:     reportFile,
+	}
+	for _, pkg := range sourcePackages {
+		gen.gens[pkg] = NewSwaggerGen(sanitizedName, pkg, pkgTypes, patternConf.Patterns(pkg)).(*swaggerGen)
+	}
+	return gen
+}
+
+func (g *mergedGen) Filename() string {
+	return g.OptionalName + openapi.Extension(g.spec.format)
+}
+
+func (g *mergedGen) FileType() string {
+	return openapiFileType
+}
+
+func (g *mergedGen) Filter(c *generator.Context, t *types.Type) bool {
+	gen, ok := g.gens[t.Name.Package]
+	return ok && gen.Filter(c, t)
+}
+
+// GenerateType collects t, types of different packages may have the same
+// name and the order of context can't tell which one comes first.
+func (g *mergedGen) GenerateType(c *generator.Context, t *types.Type, w io.Writer) error {
+	g.pkgTypes[t.Name.Package] = append(g.pkgTypes[t.Name.Package], t)
+	return nil
+}
+
+func (g *mergedGen) Finalize(c *generator.Context, w io.Writer) error {
+	for _, pkg := range g.sourcePackages {
+		pkgTypes := g.pkgTypes[pkg]
+		sort.Slice(pkgTypes, func(i, j int) bool {
+			return pkgTypes[i].String() < pkgTypes[j].String()
+		})
+		g.spec.source = pkg
+		for _, t := range pkgTypes {
+			klog.V(2).Infof("Generating merged api model for type %s", t)
+			g.gens[pkg].generate(t, g.spec)
+		}
+	}
+	g.spec.addRouteTags()
+	g.spec.logConflicts()
+	if g.reportFile != "" {
+		if err := WriteConflictReport(g.reportFile, g.spec.conflicts); err != nil {
+			return err
+		}
+	}
+	out, err := g.spec.Marshal()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
@@ -0,0 +1,216 @@
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"yunion.io/x/log"
+
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+// MetaConfig is the service level information of generated spec, it's
+// loaded from the YAML or JSON file given by --meta-config.
+type MetaConfig struct {
+	Title           string                             `yaml:"title"`
+	Description     string                             `yaml:"description"`
+	Version         string                             `yaml:"version"`
+	Host            string                             `yaml:"host"`
+	BasePath        string                             `yaml:"basePath"`
+	Schemes         []string                           `yaml:"schemes"`
+	Contact         *openapi.Contact                   `yaml:"contact"`
+	License         *openapi.License                   `yaml:"license"`
+	SecuritySchemes map[string]*openapi.SecurityScheme `yaml:"securitySchemes"`
+	// Tags describe the tags of routes, e.g. the resource singular names
+	Tags         []*openapi.Tag        `yaml:"tags"`
+	ExternalDocs *openapi.ExternalDocs `yaml:"externalDocs"`
+}
+
+// NewDefaultMetaConfig returns the meta of onecloud service.
+func NewDefaultMetaConfig(service string) *MetaConfig {
+	return &MetaConfig{
+		Title:    fmt.Sprintf("%s API", strings.Title(service)),
+		Version:  "1.0",
+		Host:     "127.0.0.1:8889",
+		BasePath: "/",
+		Schemes:  []string{"https", "http"},
+		License: &openapi.License{
+			Name: "Apache 2.0",
+			URL:  "http://www.apache.org/licenses/LICENSE-2.0.html",
+		},
+		SecuritySchemes: map[string]*openapi.SecurityScheme{
+			securityKeystone: {
+				Type: "apiKey",
+				Name: "X-Auth-Token",
+				In:   openapi.ParamInHeader,
+			},
+		},
+	}
+}
+
+// LoadMetaConfig loads meta config file of service, the top level fields
+// not set in file take the default values, e.g. an empty securitySchemes
+// removes the default keystone scheme while a missing one keeps it.
+// Defaults are returned if file is empty.
+func LoadMetaConfig(file string, service string) (*MetaConfig, error) {
+	if file == "" {
+		return NewDefaultMetaConfig(service), nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	meta := &MetaConfig{}
+	// JSON is YAML too
+	if err := yaml.Unmarshal(data, meta); err != nil {
+		return nil, fmt.Errorf("parse meta config %s: %v", file, err)
+	}
+	meta.setDefaults(NewDefaultMetaConfig(service))
+	return meta, nil
+}
+
+// setDefaults sets the unset fields of m to the ones of def.
+func (m *MetaConfig) setDefaults(def *MetaConfig) {
+	if m.Title == "" {
+		m.Title = def.Title
+	}
+	if m.Description == "" {
+		m.Description = def.Description
+	}
+	if m.Version == "" {
+		m.Version = def.Version
+	}
+	if m.Host == "" {
+		m.Host = def.Host
+	}
+	if m.BasePath == "" {
+		m.BasePath = def.BasePath
+	}
+	if m.Schemes == nil {
+		m.Schemes = def.Schemes
+	}
+	if m.Contact == nil {
+		m.Contact = def.Contact
+	}
+	if m.License == nil {
+		m.License = def.License
+	}
+	if m.SecuritySchemes == nil {
+		m.SecuritySchemes = def.SecuritySchemes
+	}
+	if m.Tags == nil {
+		m.Tags = def.Tags
+	}
+	if m.ExternalDocs == nil {
+		m.ExternalDocs = def.ExternalDocs
+	}
+}
+
+// securitySchemeNames returns the sorted names of security schemes.
+func (m *MetaConfig) securitySchemeNames() []string {
+	names := make([]string, 0, len(m.SecuritySchemes))
+	for name := range m.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Servers returns the OpenAPI servers of every scheme.
+func (m *MetaConfig) Servers() []*openapi.Server {
+	servers := make([]*openapi.Server, 0, len(m.Schemes))
+	for _, scheme := range m.Schemes {
+		servers = append(servers, &openapi.Server{
+			URL: fmt.Sprintf("%s://%s%s", scheme, m.Host, m.BasePath),
+		})
+	}
+	return servers
+}
+
+// Info returns the OpenAPI info object.
+func (m *MetaConfig) Info() *openapi.Info {
+	return &openapi.Info{
+		Title:       m.Title,
+		Description: m.Description,
+		Version:     m.Version,
+		Contact:     m.Contact,
+		License:     m.License,
+	}
+}
+
+const swaggerMeta = `
+// {{.Title}}
+{{- with .Description}}
+//
+{{- range lines .}}
+// {{.}}
+{{- end}}
+{{- end}}
+//
+//     Schemes: {{join .Schemes ", "}}
+//     BasePath: {{.BasePath}}
+//     Version: {{.Version}}
+//     Host: "{{.Host}}"
+{{- with .Contact}}
+//     Contact: {{.Name}}<{{.Email}}>{{with .URL}} {{.}}{{end}}
+{{- end}}
+{{- with .License}}
+//     License: {{.Name}}{{with .URL}} {{.}}{{end}}
+{{- end}}
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+{{- if .SecuritySchemes}}
+//
+//     SecurityDefinitions:
+{{- range $name := .SecuritySchemeNames}}
+{{- with index $.SecuritySchemes $name}}
+//     {{$name}}:
+{{- with .Name}}
+//       name: {{.}}
+{{- end}}
+//       type: {{.Type}}
+{{- with .In}}
+//       in: {{.}}
+{{- end}}
+{{- with .Description}}
+//       description: {{.}}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- end}}
+//
+// swagger:meta
+`
+
+// goSwaggerMeta renders the swagger:meta comment of go-swagger.
+func (m *MetaConfig) goSwaggerMeta() (string, error) {
+	if len(m.Tags) != 0 || m.ExternalDocs != nil {
+		log.Warningf("tags and externalDocs of meta config are only supported by --spec-format")
+	}
+	t := template.Must(template.New("swagger_meta").Funcs(template.FuncMap{
+		"join":  strings.Join,
+		"lines": func(s string) []string { return strings.Split(strings.TrimSpace(s), "\n") },
+	}).Parse(swaggerMeta))
+	out := new(bytes.Buffer)
+	err := t.Execute(out, struct {
+		*MetaConfig
+		SecuritySchemeNames []string
+	}{
+		MetaConfig:          m,
+		SecuritySchemeNames: m.securitySchemeNames(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
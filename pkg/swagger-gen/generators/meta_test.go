@@ -0,0 +1,82 @@
+package generators
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadMetaConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "meta.yaml")
+	content := `
+title: Compute Service
+host: api.example.com
+basePath: /api/v1
+schemes: [https]
+`
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := LoadMetaConfig(file, "compute")
+	if err != nil {
+		t.Fatalf("LoadMetaConfig: %v", err)
+	}
+	if meta.Title != "Compute Service" {
+		t.Errorf("title = %q", meta.Title)
+	}
+	// not set in file, keep default
+	if meta.Version != "1.0" || meta.License == nil {
+		t.Errorf("defaults are not kept: %#v", meta)
+	}
+	servers := meta.Servers()
+	if len(servers) != 1 || servers[0].URL != "https://api.example.com/api/v1" {
+		t.Errorf("servers = %#v", servers)
+	}
+
+	overrides := filepath.Join(t.TempDir(), "overrides.yaml")
+	content = `
+license:
+  name: MIT
+securitySchemes: {}
+`
+	if err := ioutil.WriteFile(overrides, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err = LoadMetaConfig(overrides, "compute")
+	if err != nil {
+		t.Fatalf("LoadMetaConfig: %v", err)
+	}
+	// set in file, replace the default as a whole
+	if meta.License.Name != "MIT" || meta.License.URL != "" {
+		t.Errorf("license = %#v", meta.License)
+	}
+	if meta.SecuritySchemes == nil || len(meta.SecuritySchemes) != 0 {
+		t.Errorf("security schemes = %#v, want empty", meta.SecuritySchemes)
+	}
+	if meta.Contact != nil {
+		t.Errorf("contact = %#v, want none", meta.Contact)
+	}
+
+	if _, err := LoadMetaConfig(filepath.Join(t.TempDir(), "missing.yaml"), "compute"); err == nil {
+		t.Errorf("expect error of missing file")
+	}
+}
+
+func TestGoSwaggerMeta(t *testing.T) {
+	out, err := NewDefaultMetaConfig("compute").goSwaggerMeta()
+	if err != nil {
+		t.Fatalf("goSwaggerMeta: %v", err)
+	}
+	for _, want := range []string{
+		"// Compute API\n",
+		"//     Schemes: https, http\n",
+		"//     Host: \"127.0.0.1:8889\"\n",
+		"//     keystone:\n//       name: X-Auth-Token\n//       type: apiKey\n//       in: header\n",
+		"// swagger:meta\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
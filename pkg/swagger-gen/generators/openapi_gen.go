@@ -0,0 +1,255 @@
+package generators
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"k8s.io/gengo/generator"
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/log"
+
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+const (
+	// openapiFileType is the gengo file type of OpenAPI spec files, the
+	// body is written as is without go header and formatting.
+	openapiFileType = "openapi"
+
+	securityKeystone = "keystone"
+)
+
+// registerOpenAPIFileType makes context able to assemble OpenAPI spec files.
+func registerOpenAPIFileType(c *generator.Context) {
+	c.FileTypes[openapiFileType] = generator.DefaultFileType{
+		Format: func(src []byte) ([]byte, error) {
+			return src, nil
+		},
+		Assemble: func(w io.Writer, f *generator.File) {
+			w.Write(f.Body.Bytes())
+		},
+	}
+}
+
+// emitter outputs the assembled route, parameter and response of an operation.
+type emitter interface {
+	emit(c *commenter)
+}
+
+// openapiSpec builds an OpenAPI 3.0 document in memory.
+type openapiSpec struct {
+	format  string
+	doc     *openapi.Document
+	schemas *schemaBuilder
+	// defs names the shared request bodies and responses
+	defs *definitions
+
+	// source is the package of operations being emitted
+	source string
+	// routes and operations map method with path and operationId to the
+	// package declaring them, responses map component response id to it
+	routes     map[string]string
+	operations map[string]string
+	responses  map[string]string
+	conflicts  []*SpecConflict
+}
+
+func newOpenAPISpec(meta *MetaConfig, format string) *openapiSpec {
+	doc := openapi.NewDocument(meta.Info())
+	doc.Servers = meta.Servers()
+	for _, name := range meta.securitySchemeNames() {
+		doc.Components.SecuritySchemes[name] = meta.SecuritySchemes[name]
+		doc.Security = append(doc.Security, openapi.SecurityRequirement{name: []string{}})
+	}
+	doc.Tags = meta.Tags
+	doc.ExternalDocs = meta.ExternalDocs
+	addErrorComponents(doc)
+	addListComponents(doc)
+	return &openapiSpec{
+		format:     format,
+		doc:        doc,
+		schemas:    newSchemaBuilder(doc.Components.Schemas),
+		defs:       newDefinitions(),
+		routes:     make(map[string]string),
+		operations: make(map[string]string),
+		responses:  make(map[string]string),
+	}
+}
+
+func (s *openapiSpec) emit(c *commenter) {
+	r := c.route
+	key := routeKey(r.action, r.path)
+	if owner, ok := s.routes[key]; ok {
+		s.conflict(ConflictRoute, key, owner, "operation %s is dropped, the route is declared already", c.parameter.operationId)
+		return
+	}
+	c.parameter.operationId = s.operationID(c.parameter.operationId)
+	op := &openapi.Operation{
+		OperationID: c.parameter.operationId,
+		Tags:        uniqueStrings(r.tags),
+		Summary:     r.summary,
+		Description: strings.Join(r.description, "\n"),
+		Parameters:  s.parameters(c.parameter),
+		RequestBody: s.requestBody(c.parameter),
+		Responses:   make(map[string]*openapi.Response),
+	}
+	for code, resp := range r.response {
+		op.Responses[fmt.Sprintf("%d", code)] = s.response(resp)
+	}
+	for code, resp := range r.errorResponses() {
+		op.Responses[code] = resp
+	}
+	item, ok := s.doc.Paths[r.path]
+	if !ok {
+		item = new(openapi.PathItem)
+		s.doc.Paths[r.path] = item
+	}
+	if !item.SetOperation(r.action, op) {
+		log.Warningf("unsupported http method %q of operation %s", r.action, op.OperationID)
+		return
+	}
+	s.routes[key] = s.source
+	s.operations[op.OperationID] = s.source
+}
+
+func (s *openapiSpec) parameters(p *parameter) []*openapi.Parameter {
+	params := make([]*openapi.Parameter, 0)
+	if p.withId {
+		params = append(params, &openapi.Parameter{
+			Name:        "id",
+			In:          openapi.ParamInPath,
+			Description: fmt.Sprintf("The Id or Name of %s", p.singular),
+			Required:    true,
+			Schema:      &openapi.Schema{Type: openapi.TypeString},
+		})
+	}
+	keys := make([]string, 0, len(p.paths))
+	for k := range p.paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		params = append(params, &openapi.Parameter{
+			Name:        k,
+			In:          openapi.ParamInPath,
+			Description: p.paths[k],
+			Required:    true,
+			Schema:      &openapi.Schema{Type: openapi.TypeString},
+		})
+	}
+	if p.isList {
+		params = append(params, s.schemas.listQueryParameters(p.getQuery())...)
+	} else if query := p.getQuery(); query != nil {
+		params = append(params, s.schemas.queryParameters(query)...)
+	}
+	return params
+}
+
+func (s *openapiSpec) requestBody(p *parameter) *openapi.RequestBody {
+	body := p.getBody()
+	if p.body == nil || body == nil {
+		return nil
+	}
+	schema := s.schemas.schemaOf(body)
+	if schema == nil {
+		return nil
+	}
+	if p.singular != "" {
+		props := map[string]*openapi.Schema{
+			p.singular: schema,
+		}
+		if p.bodyWithCount {
+			props["count"] = &openapi.Schema{
+				Type:        openapi.TypeInteger,
+				Description: fmt.Sprintf("The create count of %s", p.singular),
+				Default:     1,
+			}
+		}
+		schema = openapi.ObjectSchema(props)
+	}
+	name := s.defs.name(p.bodyFingerprint(), p.bodyDefinitionNames()...)
+	s.doc.Components.RequestBodies[name] = &openapi.RequestBody{
+		Required: true,
+		Content:  openapi.JSONContent(schema),
+	}
+	return &openapi.RequestBody{Ref: openapi.ComponentRequestBodiesPrefix + name}
+}
+
+// response registers r as component response and returns a reference to it,
+// responses of the same shape share one component.
+func (s *openapiSpec) response(r *response) *openapi.Response {
+	resp := &openapi.Response{
+		Description: "OK",
+	}
+	if len(r.headers) != 0 {
+		resp.Headers = make(map[string]*openapi.Header)
+		for k, v := range r.headers {
+			resp.Headers[k] = &openapi.Header{
+				Description: v,
+				Schema:      &openapi.Schema{Type: openapi.TypeString},
+			}
+		}
+	}
+	if output := r.getOutput(); output != nil {
+		if schema := s.schemas.schemaOf(output); schema != nil {
+			if r.bodyKey != "" {
+				schema = r.bodySchema(schema)
+			}
+			resp.Content = openapi.JSONContent(schema)
+		}
+	}
+	r.id = s.responseID(s.defs.name(r.fingerprint(), r.definitionNames()...), resp)
+	s.doc.Components.Responses[r.id] = resp
+	return &openapi.Response{Ref: openapi.ComponentResponsesPrefix + r.id}
+}
+
+func (r response) bodySchema(output *openapi.Schema) *openapi.Schema {
+	if !r.isList {
+		return openapi.ObjectSchema(map[string]*openapi.Schema{
+			r.bodyKey: output,
+		})
+	}
+	if r.isListOffset {
+		return listBodySchema(r.bodyKey, output)
+	}
+	return openapi.ObjectSchema(map[string]*openapi.Schema{
+		r.bodyKey: openapi.ArraySchema(output),
+	})
+}
+
+func (s *openapiSpec) Marshal() ([]byte, error) {
+	return openapi.Marshal(s.doc, s.format)
+}
+
+// BuildOpenAPIDocument builds the OpenAPI document of packages pkgPaths of
+// universe u in memory as --spec-format does, other tools use it to inspect
+// the routes of a source tree without writing files.
+func BuildOpenAPIDocument(u types.Universe, pkgPaths []string, meta *MetaConfig, patternConf *PatternConfig) *openapi.Document {
+	spec := newOpenAPISpec(meta, openapi.FormatYAML)
+	for _, path := range pkgPaths {
+		pkg := u[path]
+		if pkg == nil {
+			continue
+		}
+		pkgTypes := make([]*types.Type, 0, len(pkg.Types)+len(pkg.Functions))
+		for _, t := range pkg.Types {
+			pkgTypes = append(pkgTypes, t)
+		}
+		for _, t := range pkg.Functions {
+			pkgTypes = append(pkgTypes, t)
+		}
+		sort.Slice(pkgTypes, func(i, j int) bool {
+			return pkgTypes[i].String() < pkgTypes[j].String()
+		})
+		gen := NewSwaggerGen("", path, pkgTypes, patternConf.Patterns(path)).(*swaggerGen)
+		for _, t := range pkgTypes {
+			if gen.Filter(nil, t) {
+				gen.generate(t, spec)
+			}
+		}
+	}
+	return spec.doc
+}
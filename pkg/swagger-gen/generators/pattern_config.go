@@ -0,0 +1,108 @@
+package generators
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PatternConfig enables or disables method patterns, it's loaded from the
+// YAML or JSON file given by --pattern-config.
+//
+//	disable: [get-property]
+//	packages:
+//	  yunion.io/x/onecloud/pkg/compute/models:
+//	    enable: [get-property]
+//	    disable: [perform-class-action]
+type PatternConfig struct {
+	// Disable are the patterns disabled for all packages
+	Disable []string `yaml:"disable"`
+	// Packages overrides the patterns of input packages
+	Packages map[string]*PackagePatternConfig `yaml:"packages"`
+}
+
+// PackagePatternConfig enables or disables method patterns of a package.
+type PackagePatternConfig struct {
+	Enable  []string `yaml:"enable"`
+	Disable []string `yaml:"disable"`
+}
+
+// LoadPatternConfig loads pattern config file, all patterns are enabled if
+// file is empty.
+func LoadPatternConfig(file string) (*PatternConfig, error) {
+	conf := new(PatternConfig)
+	if file == "" {
+		return conf, nil
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("parse pattern config %s: %v", file, err)
+	}
+	if err := conf.validate(); err != nil {
+		return nil, fmt.Errorf("pattern config %s: %v", file, err)
+	}
+	return conf, nil
+}
+
+func (c *PatternConfig) validate() error {
+	check := func(names []string) error {
+		for _, name := range names {
+			if getMethodPattern(name) == nil {
+				return fmt.Errorf("unknown method pattern %q", name)
+			}
+		}
+		return nil
+	}
+	if err := check(c.Disable); err != nil {
+		return err
+	}
+	for pkg, pc := range c.Packages {
+		if pc == nil {
+			continue
+		}
+		for _, names := range [][]string{pc.Enable, pc.Disable} {
+			if err := check(names); err != nil {
+				return fmt.Errorf("package %s: %v", pkg, err)
+			}
+		}
+	}
+	return nil
+}
+
+func containsString(vals []string, s string) bool {
+	for _, v := range vals {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled returns true if pattern name is enabled for package pkg, the
+// config of package takes precedence over the global one.
+func (c *PatternConfig) Enabled(pkg, name string) bool {
+	if pc := c.Packages[pkg]; pc != nil {
+		if containsString(pc.Disable, name) {
+			return false
+		}
+		if containsString(pc.Enable, name) {
+			return true
+		}
+	}
+	return !containsString(c.Disable, name)
+}
+
+// Patterns returns the registered patterns enabled for package pkg.
+func (c *PatternConfig) Patterns(pkg string) []MethodPattern {
+	ret := make([]MethodPattern, 0)
+	for _, p := range MethodPatterns() {
+		if c.Enabled(pkg, p.Name()) {
+			ret = append(ret, p)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,327 @@
+package generators
+
+import (
+	"fmt"
+	"sync"
+
+	"yunion.io/x/log"
+)
+
+// PatternReceiver is the type owning the methods of a pattern.
+type PatternReceiver string
+
+const (
+	ReceiverModel   PatternReceiver = "model"
+	ReceiverManager PatternReceiver = "manager"
+)
+
+const (
+	// names of builtin method patterns
+	PatternGet                = "get"
+	PatternCreate             = "create"
+	PatternList               = "list"
+	PatternUpdate             = "update"
+	PatternDelete             = "delete"
+	PatternGetSpec            = "get-spec"
+	PatternPerform            = "perform"
+	PatternGetProperty        = "get-property"
+	PatternPerformClassAction = "perform-class-action"
+)
+
+type (
+	// RouteFactory, ParameterFactory and ResponseFactory build the parts
+	// of the operation of a matched method.
+	RouteFactory     = routeFactory
+	ParameterFactory = paramterFactory
+	ResponseFactory  = responseFactory
+
+	Route     = route
+	Parameter = parameter
+	Response  = response
+)
+
+// MethodPattern is a dispatcher convention of onecloud, e.g. methods of
+// model prefixed by Perform are POST /<plural>/{id}/<action>.
+type MethodPattern interface {
+	// Name is the unique name of pattern used by pattern config file
+	Name() string
+	// Prefix is the prefix of method names
+	Prefix() string
+	// Receiver is the type methods are looked up
+	Receiver() PatternReceiver
+	// Multiple returns true if all matched methods are generated,
+	// otherwise only the first one is.
+	Multiple() bool
+	// Match returns true if the signature of m follows the pattern
+	Match(m *Method) bool
+
+	// Parameter returns the input of the operation of m
+	Parameter(f *ParameterFactory) *Parameter
+	// Response returns the output of the operation of m, the operation
+	// is skipped if nil is returned.
+	Response(f *ResponseFactory, res *ResourceMethods) *Response
+	// Route returns the route of the operation of m
+	Route(f *RouteFactory, input *Parameter, output *Response) *Route
+}
+
+// FuncMethodPattern implements MethodPattern by functions.
+type FuncMethodPattern struct {
+	PatternName     string
+	MethodPrefix    string
+	MethodReceiver  PatternReceiver
+	MultipleMethods bool
+
+	// ParamsLen and ResultsLen are the arity of matched methods
+	ParamsLen  int
+	ResultsLen int
+	// MatchFunc is the extra signature check after arity, optional
+	MatchFunc func(m *Method) bool
+
+	ParameterFunc func(f *ParameterFactory) *Parameter
+	ResponseFunc  func(f *ResponseFactory, res *ResourceMethods) *Response
+	RouteFunc     func(f *RouteFactory, input *Parameter, output *Response) *Route
+}
+
+func (p *FuncMethodPattern) Name() string {
+	return p.PatternName
+}
+
+func (p *FuncMethodPattern) Prefix() string {
+	return p.MethodPrefix
+}
+
+func (p *FuncMethodPattern) Receiver() PatternReceiver {
+	return p.MethodReceiver
+}
+
+func (p *FuncMethodPattern) Multiple() bool {
+	return p.MultipleMethods
+}
+
+func (p *FuncMethodPattern) Match(m *Method) bool {
+	sig := m.Signature()
+	if sig == nil || len(sig.Parameters) != p.ParamsLen || len(sig.Results) != p.ResultsLen {
+		return false
+	}
+	if p.MatchFunc != nil {
+		return p.MatchFunc(m)
+	}
+	return true
+}
+
+func (p *FuncMethodPattern) Parameter(f *ParameterFactory) *Parameter {
+	return p.ParameterFunc(f)
+}
+
+func (p *FuncMethodPattern) Response(f *ResponseFactory, res *ResourceMethods) *Response {
+	return p.ResponseFunc(f, res)
+}
+
+func (p *FuncMethodPattern) Route(f *RouteFactory, input *Parameter, output *Response) *Route {
+	return p.RouteFunc(f, input, output)
+}
+
+type patternRegistry struct {
+	lock     sync.Mutex
+	patterns []MethodPattern
+}
+
+var methodPatterns = new(patternRegistry)
+
+// RegisterMethodPattern registers pattern p, operations of patterns are
+// generated in registration order.
+func RegisterMethodPattern(p MethodPattern) error {
+	methodPatterns.lock.Lock()
+	defer methodPatterns.lock.Unlock()
+	for _, rp := range methodPatterns.patterns {
+		if rp.Name() == p.Name() {
+			return fmt.Errorf("method pattern %q is already registered", p.Name())
+		}
+	}
+	switch p.Receiver() {
+	case ReceiverModel, ReceiverManager:
+	default:
+		return fmt.Errorf("method pattern %q: invalid receiver %q", p.Name(), p.Receiver())
+	}
+	methodPatterns.patterns = append(methodPatterns.patterns, p)
+	return nil
+}
+
+// MethodPatterns returns the registered patterns.
+func MethodPatterns() []MethodPattern {
+	methodPatterns.lock.Lock()
+	defer methodPatterns.lock.Unlock()
+	ret := make([]MethodPattern, len(methodPatterns.patterns))
+	copy(ret, methodPatterns.patterns)
+	return ret
+}
+
+func getMethodPattern(name string) MethodPattern {
+	for _, p := range MethodPatterns() {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// resultByGetMethod returns the output of operations responding the
+// details of resource, nil if the resource can't be fetched.
+func resultByGetMethod(f *ResponseFactory, res *ResourceMethods) *Response {
+	if res.Get == nil {
+		return nil
+	}
+	return f.ResultByGetMethod(res.Get)
+}
+
+func init() {
+	for _, p := range []*FuncMethodPattern{
+		{
+			// FetchCustomizeColumns(ctx context.Context, userCred mcclient.TokenCredential, query jsonutils.JSONObject, objs []interface{}, fields stringutils2.SSortedStrings, isList bool) []api.ScriptApplyRecordDetails
+			PatternName:    PatternGet,
+			MethodPrefix:   Get,
+			MethodReceiver: ReceiverManager,
+			ParamsLen:      6,
+			ResultsLen:     1,
+			ParameterFunc:  (*ParameterFactory).Get,
+			ResponseFunc: func(f *ResponseFactory, _ *ResourceMethods) *Response {
+				return f.FirstSingularResult()
+			},
+			RouteFunc: (*RouteFactory).Get,
+		},
+		{
+			// ValidateCreateData(context.Context, mcclient.TokenCredential, mcclient.IIdentityProvider, query jsonutils.JSONObject, data *jsonutils.JSONDict) (Object, error)
+			PatternName:    PatternCreate,
+			MethodPrefix:   Create,
+			MethodReceiver: ReceiverManager,
+			ParamsLen:      5,
+			ResultsLen:     2,
+			ParameterFunc:  (*ParameterFactory).Create,
+			ResponseFunc:   resultByGetMethod,
+			RouteFunc:      (*RouteFactory).Create,
+		},
+		{
+			// ListItemFilter(context.Context, *sqlchemy.SQuery, mcclient.TokenCredential, query jsonutils.JSONObject) (*sqlchemy.SQuery, error)
+			PatternName:    PatternList,
+			MethodPrefix:   List,
+			MethodReceiver: ReceiverManager,
+			ParamsLen:      4,
+			ResultsLen:     2,
+			ParameterFunc:  (*ParameterFactory).List,
+			ResponseFunc: func(f *ResponseFactory, res *ResourceMethods) *Response {
+				if res.Get == nil {
+					return nil
+				}
+				return f.ListResult(res.Get)
+			},
+			RouteFunc: (*RouteFactory).List,
+		},
+		{
+			// ValidateUpdateData(context.Context, mcclient.TokenCredential, query Object, data Object) (Object, error)
+			PatternName:    PatternUpdate,
+			MethodPrefix:   Update,
+			MethodReceiver: ReceiverModel,
+			ParamsLen:      4,
+			ResultsLen:     2,
+			ParameterFunc:  (*ParameterFactory).Update,
+			ResponseFunc:   resultByGetMethod,
+			RouteFunc:      (*RouteFactory).Update,
+		},
+		{
+			// CustomizeDelete(context.Context, mcclient.TokenCredential, query Object, body Object) error
+			PatternName:    PatternDelete,
+			MethodPrefix:   Delete,
+			MethodReceiver: ReceiverModel,
+			ParamsLen:      4,
+			ResultsLen:     1,
+			ParameterFunc:  (*ParameterFactory).Delete,
+			ResponseFunc:   resultByGetMethod,
+			RouteFunc:      (*RouteFactory).Delete,
+		},
+		{
+			// GetDetailsXxx(context.Context, mcclient.TokenCredential, query Object) (Object, error)
+			PatternName:     PatternGetSpec,
+			MethodPrefix:    GetSpec,
+			MethodReceiver:  ReceiverModel,
+			MultipleMethods: true,
+			ParamsLen:       3,
+			ResultsLen:      2,
+			MatchFunc: func(m *Method) bool {
+				if err := isValidType(m.Resutls(0)); err != nil {
+					log.Warningf("method %s: output type is invalid: %v", m.String(), err)
+				}
+				return true
+			},
+			ParameterFunc: (*ParameterFactory).GetSpec,
+			ResponseFunc: func(f *ResponseFactory, _ *ResourceMethods) *Response {
+				return f.FirstSingularResult()
+			},
+			RouteFunc: (*RouteFactory).GetSpec,
+		},
+		{
+			// PerformXxx(context.Context, mcclient.TokenCredential, query Object, body Object) (Object, error)
+			PatternName:     PatternPerform,
+			MethodPrefix:    Perform,
+			MethodReceiver:  ReceiverModel,
+			MultipleMethods: true,
+			ParamsLen:       4,
+			ResultsLen:      2,
+			MatchFunc: func(m *Method) bool {
+				if m.Params(1).Name.Name != "TokenCredential" {
+					return false
+				}
+				warnInvalidInputOutput(m)
+				return true
+			},
+			ParameterFunc: (*ParameterFactory).PerformAction,
+			ResponseFunc: func(f *ResponseFactory, _ *ResourceMethods) *Response {
+				return f.FirstSingularResultNoError()
+			},
+			RouteFunc: (*RouteFactory).PerformAction,
+		},
+		{
+			// GetPropertyXxx(ctx context.Context, userCred mcclient.TokenCredential, query jsonutils.JSONObject) (Object, error)
+			PatternName:     PatternGetProperty,
+			MethodPrefix:    GetProperty,
+			MethodReceiver:  ReceiverManager,
+			MultipleMethods: true,
+			ParamsLen:       3,
+			ResultsLen:      2,
+			ParameterFunc:   (*ParameterFactory).GetProperty,
+			ResponseFunc: func(f *ResponseFactory, _ *ResourceMethods) *Response {
+				return f.FirstSingularResultNoError()
+			},
+			RouteFunc: (*RouteFactory).GetProperty,
+		},
+		{
+			// PerformXxx of manager(context.Context, mcclient.TokenCredential, query Object, body Object) (Object, error)
+			PatternName:     PatternPerformClassAction,
+			MethodPrefix:    Perform,
+			MethodReceiver:  ReceiverManager,
+			MultipleMethods: true,
+			ParamsLen:       4,
+			ResultsLen:      2,
+			MatchFunc: func(m *Method) bool {
+				warnInvalidInputOutput(m)
+				return true
+			},
+			ParameterFunc: (*ParameterFactory).PerformClassAction,
+			ResponseFunc: func(f *ResponseFactory, _ *ResourceMethods) *Response {
+				return f.FirstSingularResultNoError()
+			},
+			RouteFunc: (*RouteFactory).PerformClassAction,
+		},
+	} {
+		if err := RegisterMethodPattern(p); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// warnInvalidInputOutput warns if input body and output of m are not struct
+// pointers.
+func warnInvalidInputOutput(m *Method) {
+	if err := validInputOutput(m.Params(3), m.Resutls(0)); err != nil {
+		log.Warningf("validInputOutput for method %s: %v", m.String(), err)
+	}
+}
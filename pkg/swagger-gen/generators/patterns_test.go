@@ -0,0 +1,112 @@
+package generators
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"yunion.io/x/code-generator/pkg/common/golden"
+	swaggerargs "yunion.io/x/code-generator/pkg/swagger-gen/args"
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+func TestRegisterMethodPattern(t *testing.T) {
+	if err := RegisterMethodPattern(&FuncMethodPattern{PatternName: PatternGet, MethodReceiver: ReceiverManager}); err == nil {
+		t.Errorf("expect error of duplicated pattern")
+	}
+	if err := RegisterMethodPattern(&FuncMethodPattern{PatternName: "allow", MethodReceiver: "object"}); err == nil {
+		t.Errorf("expect error of invalid receiver")
+	}
+	names := make([]string, 0)
+	for _, p := range MethodPatterns() {
+		names = append(names, p.Name())
+	}
+	if len(names) != 9 || names[0] != PatternGet || names[8] != PatternPerformClassAction {
+		t.Errorf("builtin patterns = %v", names)
+	}
+}
+
+func TestPatternConfig(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "patterns.yaml")
+	content := `
+disable: [get-property, perform-class-action]
+packages:
+  yunion.io/x/onecloud/pkg/compute/models:
+    enable: [get-property]
+    disable: [perform]
+`
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	conf, err := LoadPatternConfig(file)
+	if err != nil {
+		t.Fatalf("LoadPatternConfig: %v", err)
+	}
+	compute := "yunion.io/x/onecloud/pkg/compute/models"
+	image := "yunion.io/x/onecloud/pkg/image/models"
+	for _, c := range []struct {
+		pkg     string
+		pattern string
+		want    bool
+	}{
+		{compute, PatternGetProperty, true},
+		{compute, PatternPerform, false},
+		{compute, PatternPerformClassAction, false},
+		{compute, PatternGet, true},
+		{image, PatternGetProperty, false},
+		{image, PatternPerform, true},
+	} {
+		if got := conf.Enabled(c.pkg, c.pattern); got != c.want {
+			t.Errorf("Enabled(%s, %s) = %v, want %v", c.pkg, c.pattern, got, c.want)
+		}
+	}
+	if got := len(conf.Patterns(image)); got != 7 {
+		t.Errorf("got %d patterns of %s", got, image)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("disable: [allow]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPatternConfig(file); err == nil {
+		t.Errorf("expect error of unknown pattern")
+	}
+}
+
+func TestGetPatternDisabled(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "patterns.yaml")
+	if err := ioutil.WriteFile(file, []byte("disable: [get]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	arguments, customArgs := swaggerargs.NewDefaults()
+	arguments.InputDirs = []string{"yunion.io/x/onecloud/pkg/compute/models"}
+	arguments.OutputPackagePath = "yunion.io/x/onecloud/pkg/generated/swagger/compute"
+	customArgs.SpecFormat = openapi.FormatYAML
+	customArgs.PatternConfig = file
+	dir := golden.Run(t, arguments, NameSystems(), DefaultNameSystem(), Packages)
+	data, err := ioutil.ReadFile(filepath.Join(dir, arguments.OutputPackagePath, "zz_generated.swagger_spec_compute.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Unmarshal(data, openapi.FormatYAML)
+	if err != nil {
+		t.Fatalf("parse spec: %v", err)
+	}
+
+	if item := doc.Paths["/servers/{id}"]; item != nil && item.Get != nil {
+		t.Errorf("get route is emitted while the pattern is disabled")
+	}
+	for _, c := range []struct {
+		path string
+		op   func(*openapi.PathItem) *openapi.Operation
+	}{
+		{"/servers", func(p *openapi.PathItem) *openapi.Operation { return p.Get }},
+		{"/servers", func(p *openapi.PathItem) *openapi.Operation { return p.Post }},
+		{"/servers/{id}", func(p *openapi.PathItem) *openapi.Operation { return p.Put }},
+	} {
+		item := doc.Paths[c.path]
+		if item == nil || c.op(item) == nil {
+			t.Errorf("operation of %s is dropped without the get pattern", c.path)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package generators
+
+import (
+	"sort"
+
+	"k8s.io/gengo/types"
+)
+
+// ResourceMethods are the restful methods discovered from a model and its
+// manager, other generators use it to share the discovery of swagger-gen.
+type ResourceMethods struct {
+	Singular string
+	Plural   string
+
+	Get    *Method
+	Create *Method
+	List   *Method
+	Update *Method
+	Delete *Method
+
+	GetSpecs       []*Method
+	Performs       []*Method
+	GetProperties  []*Method
+	PerformClasses []*Method
+
+	// Methods are the matched methods keyed by pattern name
+	Methods map[string][]*Method
+}
+
+// ParseResourceMethods discovers the restful methods of model and manager
+// by all registered patterns, methods of each kind are sorted by name.
+func ParseResourceMethods(manager *types.Type, model *types.Type) *ResourceMethods {
+	return parseResourceMethods(manager, model, MethodPatterns())
+}
+
+func parseResourceMethods(manager *types.Type, model *types.Type, patterns []MethodPattern) *ResourceMethods {
+	p := newTypeParser(manager, model)
+	res := &ResourceMethods{
+		Singular: p.singular,
+		Plural:   p.plural,
+		Methods:  make(map[string][]*Method),
+	}
+	for _, pattern := range patterns {
+		res.Methods[pattern.Name()] = p.patternMethods(pattern)
+	}
+	first := func(name string) *Method {
+		if ms := res.Methods[name]; len(ms) > 0 {
+			return ms[0]
+		}
+		return nil
+	}
+	res.Get = first(PatternGet)
+	if _, ok := res.Methods[PatternGet]; !ok {
+		// the get method gives the responses of create, list, update and
+		// delete, it's resolved even if the get route isn't emitted
+		if ms := p.patternMethods(getMethodPattern(PatternGet)); len(ms) > 0 {
+			res.Get = ms[0]
+		}
+	}
+	res.Create = first(PatternCreate)
+	res.List = first(PatternList)
+	res.Update = first(PatternUpdate)
+	res.Delete = first(PatternDelete)
+	res.GetSpecs = res.Methods[PatternGetSpec]
+	res.Performs = res.Methods[PatternPerform]
+	res.GetProperties = res.Methods[PatternGetProperty]
+	res.PerformClasses = res.Methods[PatternPerformClassAction]
+	return res
+}
+
+func sortMethods(methods []*Method) []*Method {
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Name() < methods[j].Name()
+	})
+	return methods
+}
@@ -0,0 +1,354 @@
+package generators
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/log"
+	"yunion.io/x/pkg/util/reflectutils"
+
+	"yunion.io/x/code-generator/pkg/common"
+	"yunion.io/x/code-generator/pkg/swagger-gen/openapi"
+)
+
+// schemaBuilder converts gengo types to OpenAPI schemas, named structs and
+// aliases are registered once as component schemas and referred by $ref.
+type schemaBuilder struct {
+	components map[string]*openapi.Schema
+	// names maps type full name to component name
+	names map[string]string
+	// owners maps component name to type full name
+	owners map[string]string
+}
+
+func newSchemaBuilder(components map[string]*openapi.Schema) *schemaBuilder {
+	return &schemaBuilder{
+		components: components,
+		names:      make(map[string]string),
+		owners:     make(map[string]string),
+	}
+}
+
+func isTimeType(t *types.Type) bool {
+	return t.Name.Package == "time" && t.Name.Name == "Time"
+}
+
+func isTriStateType(t *types.Type) bool {
+	return t.Kind == types.Alias && t.Name.Name == "TriState"
+}
+
+func isNamedType(t *types.Type) bool {
+	return t.Name.Package != ""
+}
+
+func builtinSchema(t *types.Type) *openapi.Schema {
+	switch t.Name.Name {
+	case "string":
+		return &openapi.Schema{Type: openapi.TypeString}
+	case "bool":
+		return &openapi.Schema{Type: openapi.TypeBoolean}
+	case "int", "int64", "uint", "uint64", "uintptr":
+		return &openapi.Schema{Type: openapi.TypeInteger, Format: "int64"}
+	case "int8", "int16", "int32", "uint8", "uint16", "uint32", "byte", "rune":
+		return &openapi.Schema{Type: openapi.TypeInteger, Format: "int32"}
+	case "float32":
+		return &openapi.Schema{Type: openapi.TypeNumber, Format: "float"}
+	case "float64":
+		return &openapi.Schema{Type: openapi.TypeNumber, Format: "double"}
+	default:
+		// error, interface{} and other predeclared types
+		return &openapi.Schema{}
+	}
+}
+
+// componentName returns an unique component name of named type t.
+func (b *schemaBuilder) componentName(t *types.Type) string {
+	key := t.String()
+	if name, ok := b.names[key]; ok {
+		return name
+	}
+	name := t.Name.Name
+	if owner, ok := b.owners[name]; ok && owner != key {
+		name = fmt.Sprintf("%s.%s", filepath.Base(t.Name.Package), t.Name.Name)
+	}
+	b.names[key] = name
+	b.owners[name] = key
+	return name
+}
+
+// schemaOf returns the schema of t, it returns nil if t can't be represented
+// by json, e.g. func or chan.
+func (b *schemaBuilder) schemaOf(t *types.Type) *openapi.Schema {
+	if t == nil {
+		return nil
+	}
+	switch t.Kind {
+	case types.Builtin:
+		return builtinSchema(t)
+	case types.Pointer:
+		return openapi.Nullable(b.schemaOf(t.Elem))
+	case types.Slice, types.Array:
+		if t.Elem.Kind == types.Builtin && t.Elem.Name.Name == "byte" {
+			return &openapi.Schema{Type: openapi.TypeString, Format: "byte"}
+		}
+		items := b.schemaOf(t.Elem)
+		if items == nil {
+			return nil
+		}
+		return openapi.ArraySchema(items)
+	case types.Map:
+		s := &openapi.Schema{Type: openapi.TypeObject}
+		if elem := b.schemaOf(t.Elem); elem != nil {
+			s.AdditionalProperties = elem
+		}
+		return s
+	case types.Interface:
+		return &openapi.Schema{}
+	case types.Alias:
+		if isTriStateType(t) {
+			return &openapi.Schema{Type: openapi.TypeBoolean, Nullable: true}
+		}
+		if underlyingType(t).Kind == types.Builtin {
+			return b.schemaOf(underlyingType(t))
+		}
+		return b.namedSchema(t, func() *openapi.Schema {
+			return b.schemaOf(t.Underlying)
+		})
+	case types.Struct:
+		if isTimeType(t) {
+			return &openapi.Schema{Type: openapi.TypeString, Format: "date-time"}
+		}
+		if common.IsJSONObject(t) {
+			return &openapi.Schema{Type: openapi.TypeObject}
+		}
+		if !isNamedType(t) {
+			return b.structSchema(t)
+		}
+		return b.namedSchema(t, func() *openapi.Schema {
+			return b.structSchema(t)
+		})
+	default:
+		log.Warningf("skip schema of unsupported type %s, kind is %s", t.String(), t.Kind)
+		return nil
+	}
+}
+
+func underlyingType(t *types.Type) *types.Type {
+	for t.Kind == types.Alias {
+		t = t.Underlying
+	}
+	return t
+}
+
+// namedSchema registers the component schema of named type t and returns a
+// reference to it.
+func (b *schemaBuilder) namedSchema(t *types.Type, build func() *openapi.Schema) *openapi.Schema {
+	_, visited := b.names[t.String()]
+	name := b.componentName(t)
+	if !visited {
+		// occupy the name before building to break recursive types
+		b.components[name] = &openapi.Schema{}
+		s := build()
+		if s == nil {
+			s = &openapi.Schema{}
+		}
+		if s.Description == "" {
+			s.Description = CommentDescription(t.CommentLines)
+		}
+		b.components[name] = s
+	}
+	return openapi.RefSchema(name)
+}
+
+// CommentDescription returns the doc of comment lines without comment tags
+// and field markers.
+func CommentDescription(lines []string) string {
+	ret := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(l), "+") || IsMarkerLine(l) {
+			// skip comment tags and markers
+			continue
+		}
+		ret = append(ret, l)
+	}
+	return strings.TrimSpace(strings.Join(ret, "\n"))
+}
+
+// describe sets description of schema, sibling keywords of $ref are ignored
+// so the reference is wrapped by allOf.
+func describe(s *openapi.Schema, desc string) *openapi.Schema {
+	if desc == "" {
+		return s
+	}
+	if s.IsRef() {
+		return &openapi.Schema{AllOf: []*openapi.Schema{s}, Description: desc}
+	}
+	ret := *s
+	ret.Description = desc
+	return &ret
+}
+
+// markedSchema returns schema s of member m of struct t with markers, the
+// invalid ones are logged and dropped.
+func markedSchema(t *types.Type, m types.Member, markers *FieldMarkers, s *openapi.Schema) *openapi.Schema {
+	ret, err := markers.Apply(s)
+	if err != nil {
+		logTagErrors([]*TagError{{Subject: fmt.Sprintf("%s.%s", t.String(), m.Name), Message: err.Error()}})
+	}
+	return ret
+}
+
+func memberJSONInfo(m types.Member) reflectutils.SStructFieldInfo {
+	return reflectutils.ParseFieldJsonInfo(m.Name, reflect.StructTag(m.Tags))
+}
+
+func memberJSONName(m types.Member) string {
+	info := memberJSONInfo(m)
+	return info.MarshalName()
+}
+
+// isInlineEmbedded returns true if the embedded member's fields are
+// promoted to parent json object.
+func isInlineEmbedded(m types.Member) bool {
+	if !m.Embedded {
+		return false
+	}
+	t := m.Type
+	if t.Kind == types.Pointer {
+		t = t.Elem
+	}
+	return underlyingType(t).Kind == types.Struct && !isTimeType(t)
+}
+
+func (b *schemaBuilder) structSchema(t *types.Type) *openapi.Schema {
+	obj := openapi.ObjectSchema(make(map[string]*openapi.Schema))
+	allOf := make([]*openapi.Schema, 0)
+	for _, m := range t.Members {
+		if common.IsPrivateStruct(m.Name) && !m.Embedded {
+			continue
+		}
+		info := memberJSONInfo(m)
+		if info.Ignore || common.GetFieldOptions(m.CommentLines).Skip {
+			continue
+		}
+		if isInlineEmbedded(m) {
+			et := m.Type
+			if et.Kind == types.Pointer {
+				et = et.Elem
+			}
+			if s := b.schemaOf(et); s != nil {
+				allOf = append(allOf, s)
+			}
+			continue
+		}
+		s := b.schemaOf(common.MemberType(m))
+		if s == nil {
+			continue
+		}
+		// a renamed field keeps its json name
+		name := info.MarshalName()
+		markers := MemberMarkers(t, m)
+		obj.Properties[name] = markedSchema(t, m, markers, describe(s, CommentDescription(m.CommentLines)))
+		if markers.Required {
+			obj.Required = append(obj.Required, name)
+		}
+	}
+	if len(allOf) == 0 {
+		return obj
+	}
+	if len(obj.Properties) > 0 {
+		allOf = append(allOf, obj)
+	}
+	return &openapi.Schema{AllOf: allOf}
+}
+
+// queryParameters expands members of query struct t as query parameters.
+func (b *schemaBuilder) queryParameters(t *types.Type) []*openapi.Parameter {
+	return b.memberParameters(t, queryMembers(t, nil))
+}
+
+// listQueryParameters returns the shared list parameters and the members
+// of list query struct t not covered by them.
+func (b *schemaBuilder) listQueryParameters(t *types.Type) []*openapi.Parameter {
+	params := listParameterRefs()
+	if t != nil {
+		params = append(params, b.memberParameters(t, queryMembers(t, isListQueryMember))...)
+	}
+	return params
+}
+
+func (b *schemaBuilder) memberParameters(t *types.Type, members []types.Member) []*openapi.Parameter {
+	params := make([]*openapi.Parameter, 0, len(members))
+	for _, m := range members {
+		s := b.schemaOf(common.MemberType(m))
+		if s == nil {
+			continue
+		}
+		markers := MemberMarkers(t, m)
+		s = markedSchema(t, m, markers, s)
+		p := &openapi.Parameter{
+			Name:        memberJSONName(m),
+			In:          openapi.ParamInQuery,
+			Description: CommentDescription(m.CommentLines),
+			Required:    markers.Required,
+			Deprecated:  markers.Deprecated,
+			Schema:      s,
+		}
+		if ut := underlyingType(derefType(common.MemberType(m))); ut.Kind == types.Struct || ut.Kind == types.Map {
+			explode := true
+			p.Style = "deepObject"
+			p.Explode = &explode
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// queryMembers flattens members of query struct t, members of inline
+// embedded structs are promoted and members of duplicated names or skipped
+// by skip are dropped.
+func queryMembers(t *types.Type, skip func(types.Member) bool) []types.Member {
+	members := make([]types.Member, 0)
+	collectQueryMembers(t, skip, make(map[string]bool), &members)
+	return members
+}
+
+func collectQueryMembers(t *types.Type, skip func(types.Member) bool, seen map[string]bool, members *[]types.Member) {
+	t = underlyingType(t)
+	if t.Kind != types.Struct || common.IsJSONObject(t) {
+		return
+	}
+	for _, m := range t.Members {
+		if common.IsPrivateStruct(m.Name) && !m.Embedded {
+			continue
+		}
+		info := memberJSONInfo(m)
+		if info.Ignore || common.GetFieldOptions(m.CommentLines).Skip {
+			continue
+		}
+		if skip != nil && skip(m) {
+			continue
+		}
+		if isInlineEmbedded(m) {
+			collectQueryMembers(derefType(m.Type), skip, seen, members)
+			continue
+		}
+		name := info.MarshalName()
+		if mt := common.MemberType(m); seen[name] || mt.Kind == types.Func || mt.Kind == types.Chan {
+			continue
+		}
+		seen[name] = true
+		*members = append(*members, m)
+	}
+}
+
+func derefType(t *types.Type) *types.Type {
+	for t.Kind == types.Pointer {
+		t = t.Elem
+	}
+	return t
+}
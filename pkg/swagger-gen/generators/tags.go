@@ -0,0 +1,472 @@
+package generators
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/log"
+)
+
+// tagPrefix is the prefix of all swagger-gen comment tags
+const tagPrefix = "+onecloud:swagger-gen-"
+
+// TagTarget is the kind of declarations a comment tag can be put on.
+type TagTarget int
+
+const (
+	// TagTargetType is a model or model manager struct
+	TagTargetType TagTarget = 1 << iota
+	// TagTargetField is a member of struct, e.g. an embedded member of model
+	// or a field of apis input
+	TagTargetField
+	// TagTargetMethod is a method of model or model manager
+	TagTargetMethod
+	// TagTargetFunc is a function declaring a route by route tags
+	TagTargetFunc
+)
+
+var tagTargetNames = []struct {
+	target TagTarget
+	name   string
+}{
+	{TagTargetType, "type"},
+	{TagTargetField, "field"},
+	{TagTargetMethod, "method"},
+	{TagTargetFunc, "func"},
+}
+
+func (t TagTarget) String() string {
+	names := make([]string, 0)
+	for _, n := range tagTargetNames {
+		if t&n.target != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// TagValueType is the value syntax of a comment tag.
+type TagValueType string
+
+const (
+	// TagValueFlag is set by presence, the value is ignored
+	TagValueFlag TagValueType = "flag"
+	// TagValueString is a non-empty string
+	TagValueString TagValueType = "string"
+	// TagValueIndex is an index of function parameters or results
+	TagValueIndex TagValueType = "index"
+	// TagValuePair is key:value, or the legacy form giving the key and the
+	// value by two tags in order
+	TagValuePair TagValueType = "pair"
+	// TagValueError is <code>,<class> of a documented error
+	TagValueError TagValueType = "error"
+	// TagValueNumber is an integer or float number
+	TagValueNumber TagValueType = "number"
+	// TagValuePattern is a regular expression
+	TagValuePattern TagValueType = "pattern"
+)
+
+// TagSpec describes a comment tag supported by swagger-gen.
+type TagSpec struct {
+	Name     string
+	Value    TagValueType
+	Multiple bool
+	Targets  TagTarget
+	// Enum are the allowed values compared case insensitively, any value
+	// is allowed if empty
+	Enum []string
+	// Requires are the tags must be given together with this one
+	Requires []string
+}
+
+var tagSpecs = map[string]*TagSpec{}
+
+func init() {
+	for _, spec := range []*TagSpec{
+		{Name: tagIgnoreName, Value: TagValueFlag, Targets: TagTargetType | TagTargetField | TagTargetMethod | TagTargetFunc},
+		{Name: tagRouteMethod, Value: TagValueString, Targets: TagTargetFunc, Enum: []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD"}, Requires: []string{tagRoutePath, tagRouteTag}},
+		{Name: tagRoutePath, Value: TagValueString, Targets: TagTargetFunc, Requires: []string{tagRouteMethod}},
+		{Name: tagRouteTag, Value: TagValueString, Multiple: true, Targets: TagTargetFunc, Requires: []string{tagRouteMethod}},
+		{Name: tagParamPath, Value: TagValuePair, Multiple: true, Targets: TagTargetFunc, Requires: []string{tagRouteMethod}},
+		{Name: tagParamQueryIdx, Value: TagValueIndex, Targets: TagTargetFunc, Requires: []string{tagRouteMethod}},
+		{Name: tagParamBodyIdx, Value: TagValueIndex, Targets: TagTargetFunc, Requires: []string{tagRouteMethod}},
+		{Name: tagParamBodyKey, Value: TagValueString, Targets: TagTargetFunc, Requires: []string{tagParamBodyIdx}},
+		{Name: tagRespHeader, Value: TagValuePair, Multiple: true, Targets: TagTargetFunc, Requires: []string{tagRespIdx}},
+		{Name: tagRespIdx, Value: TagValueIndex, Targets: TagTargetFunc, Requires: []string{tagRouteMethod}},
+		{Name: tagRespBodyKey, Value: TagValueString, Targets: TagTargetFunc, Requires: []string{tagRespIdx}},
+		{Name: tagRespBodyList, Value: TagValueFlag, Targets: TagTargetFunc, Requires: []string{tagRespIdx}},
+		{Name: tagRespBodyListOffset, Value: TagValueFlag, Targets: TagTargetFunc, Requires: []string{tagRespBodyList}},
+		{Name: tagModelSingular, Value: TagValueString, Targets: TagTargetType},
+		{Name: tagModelPlural, Value: TagValueString, Targets: TagTargetType},
+		{Name: tagRespError, Value: TagValueError, Multiple: true, Targets: TagTargetMethod | TagTargetFunc},
+		{Name: tagFieldEnum, Value: TagValueString, Targets: TagTargetField},
+		{Name: tagFieldDefault, Value: TagValueString, Targets: TagTargetField},
+		{Name: tagFieldRequired, Value: TagValueFlag, Targets: TagTargetField},
+		{Name: tagFieldMinimum, Value: TagValueNumber, Targets: TagTargetField},
+		{Name: tagFieldMaximum, Value: TagValueNumber, Targets: TagTargetField},
+		{Name: tagFieldPattern, Value: TagValuePattern, Targets: TagTargetField},
+		{Name: tagFieldExample, Value: TagValueString, Targets: TagTargetField},
+		{Name: tagFieldDeprecated, Value: TagValueFlag, Targets: TagTargetField},
+	} {
+		tagSpecs[spec.Name] = spec
+	}
+}
+
+// TagSpecs returns the specs of all supported tags sorted by name.
+func TagSpecs() []*TagSpec {
+	ret := make([]*TagSpec, 0, len(tagSpecs))
+	for _, spec := range tagSpecs {
+		ret = append(ret, spec)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// TagLine is a comment line of a declaration.
+type TagLine struct {
+	Text string
+	// Position is file:line:column of the line, empty if unknown
+	Position string
+}
+
+func commentTagLines(comments []string) []TagLine {
+	lines := make([]TagLine, len(comments))
+	for i, c := range comments {
+		lines[i] = TagLine{Text: c}
+	}
+	return lines
+}
+
+// TagError is a misuse of a comment tag.
+type TagError struct {
+	Position string `json:"position,omitempty"`
+	// Subject is the declaration the tag is put on
+	Subject string `json:"subject"`
+	Tag     string `json:"tag,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *TagError) Error() string {
+	s := e.Subject + ": "
+	if e.Tag != "" {
+		s += fmt.Sprintf("tag %s: ", e.Tag)
+	}
+	s += e.Message
+	if e.Position != "" {
+		s = e.Position + ": " + s
+	}
+	return s
+}
+
+// TagDecl is a declaration with comment tags.
+type TagDecl struct {
+	// Subject names the declaration in errors, e.g. models.SServer.PerformStart
+	Subject string
+	Target  TagTarget
+	Lines   []TagLine
+	// Params and Results are the counts of function parameters and results
+	// index tags are checked against, -1 if unknown.
+	Params  int
+	Results int
+}
+
+// tagValue is a parsed value of a tag.
+type tagValue struct {
+	line  TagLine
+	raw   string
+	index int
+	// key and value of pair tags
+	key   string
+	value string
+	// code and class of error tags
+	code  int
+	class string
+	// number of number tags
+	number float64
+}
+
+// ParsedTags are the valid tag values of a declaration keyed by tag name.
+type ParsedTags map[string][]*tagValue
+
+// Has returns true if tag name is given.
+func (p ParsedTags) Has(name string) bool {
+	return len(p[name]) > 0
+}
+
+func (p ParsedTags) first(name string) *tagValue {
+	if vals := p[name]; len(vals) > 0 {
+		return vals[0]
+	}
+	return nil
+}
+
+// value returns the value of single string tag name.
+func (p ParsedTags) value(name string) string {
+	if v := p.first(name); v != nil {
+		return v.raw
+	}
+	return ""
+}
+
+// pairs returns the key value pairs of pair tag name.
+func (p ParsedTags) pairs(name string) map[string]string {
+	vals := p[name]
+	if len(vals) == 0 {
+		return nil
+	}
+	ret := make(map[string]string)
+	for _, v := range vals {
+		ret[v.key] = v.value
+	}
+	return ret
+}
+
+// errors returns the error classes of resp error tags keyed by code.
+func (p ParsedTags) errors() map[int][]string {
+	vals := p[tagRespError]
+	if len(vals) == 0 {
+		return nil
+	}
+	ret := make(map[int][]string)
+	for _, v := range vals {
+		classes := ret[v.code]
+		if v.class != "" {
+			classes = append(classes, v.class)
+		}
+		ret[v.code] = classes
+	}
+	return ret
+}
+
+var pairKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// ParseTags parses and validates the swagger-gen tags of declaration d.
+// Invalid tags are reported and dropped, the valid ones are returned.
+func ParseTags(d *TagDecl) (ParsedTags, []*TagError) {
+	tags := make(ParsedTags)
+	errs := make([]*TagError, 0)
+	fail := func(line TagLine, tag string, format string, args ...interface{}) {
+		errs = append(errs, &TagError{
+			Position: line.Position,
+			Subject:  d.Subject,
+			Tag:      tag,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+	// pending is the key of legacy pair given by two tags
+	pending := make(map[string]*tagValue)
+	for _, line := range d.Lines {
+		text := strings.TrimSpace(line.Text)
+		if !strings.HasPrefix(text, tagPrefix) {
+			continue
+		}
+		name, raw := text[1:], ""
+		if idx := strings.Index(name, "="); idx >= 0 {
+			name, raw = name[:idx], strings.TrimSpace(name[idx+1:])
+		}
+		spec, ok := tagSpecs[name]
+		if !ok {
+			fail(line, name, "unknown tag")
+			continue
+		}
+		if spec.Targets&d.Target == 0 {
+			fail(line, name, "can't be put on %s, only on %s", d.Target, spec.Targets)
+			continue
+		}
+		if !spec.Multiple && tags.Has(name) {
+			fail(line, name, "is given more than once")
+			continue
+		}
+		v := &tagValue{line: line, raw: raw}
+		switch spec.Value {
+		case TagValueString:
+			if raw == "" {
+				fail(line, name, "value is empty")
+				continue
+			}
+			if len(spec.Enum) > 0 && !containsFold(spec.Enum, raw) {
+				fail(line, name, "invalid value %q, must be one of %s", raw, strings.Join(spec.Enum, ", "))
+				continue
+			}
+		case TagValueIndex:
+			idx, err := strconv.Atoi(raw)
+			if err != nil || idx < 0 {
+				fail(line, name, "invalid index %q", raw)
+				continue
+			}
+			limit := d.Params
+			what := "parameters"
+			if name == tagRespIdx {
+				limit, what = d.Results, "results"
+			}
+			if limit >= 0 && idx >= limit {
+				fail(line, name, "index %d is out of range, only %d %s", idx, limit, what)
+				continue
+			}
+			v.index = idx
+		case TagValuePair:
+			if p := pending[name]; p != nil {
+				// the second tag of legacy form is the value
+				p.value = raw
+				delete(pending, name)
+				continue
+			}
+			if idx := strings.Index(raw, ":"); idx > 0 && pairKeyRegexp.MatchString(raw[:idx]) {
+				v.key, v.value = raw[:idx], strings.TrimSpace(raw[idx+1:])
+			} else if pairKeyRegexp.MatchString(raw) {
+				v.key, v.value = raw, raw
+				pending[name] = v
+			} else {
+				fail(line, name, "invalid value %q, must be key:value", raw)
+				continue
+			}
+		case TagValueError:
+			parts := strings.SplitN(raw, ",", 2)
+			code, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+			if err != nil || http.StatusText(code) == "" {
+				fail(line, name, "invalid value %q: invalid http status code", raw)
+				continue
+			}
+			v.code = code
+			if len(parts) == 2 {
+				v.class = strings.TrimSpace(parts[1])
+			}
+		case TagValueNumber:
+			number, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				fail(line, name, "invalid number %q", raw)
+				continue
+			}
+			v.number = number
+		case TagValuePattern:
+			if raw == "" {
+				fail(line, name, "value is empty")
+				continue
+			}
+			if _, err := regexp.Compile(raw); err != nil {
+				fail(line, name, "invalid pattern %q: %v", raw, err)
+				continue
+			}
+		}
+		tags[name] = append(tags[name], v)
+	}
+	for _, spec := range TagSpecs() {
+		if !tags.Has(spec.Name) {
+			continue
+		}
+		for _, req := range spec.Requires {
+			if !tags.Has(req) {
+				fail(tags.first(spec.Name).line, spec.Name, "requires tag %s", req)
+			}
+		}
+	}
+	return tags, errs
+}
+
+func containsFold(vals []string, s string) bool {
+	for _, v := range vals {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func logTagErrors(errs []*TagError) {
+	for _, err := range errs {
+		log.Errorf("invalid tag: %v", err)
+	}
+}
+
+// parseCommentTags parses the tags of comments of subject, errors are
+// logged.
+func parseCommentTags(subject string, target TagTarget, comments []string, sig *types.Signature) ParsedTags {
+	d := &TagDecl{
+		Subject: subject,
+		Target:  target,
+		Lines:   commentTagLines(comments),
+		Params:  -1,
+		Results: -1,
+	}
+	if sig != nil {
+		d.Params, d.Results = len(sig.Parameters), len(sig.Results)
+	}
+	tags, errs := ParseTags(d)
+	logTagErrors(errs)
+	return tags
+}
+
+// swaggerConfig returns the config of route declared by function of
+// signature sig, nil is returned if the route isn't complete.
+func (p ParsedTags) swaggerConfig(sig *types.Signature) *SwaggerConfig {
+	route := p.swaggerRoute()
+	if route == nil {
+		return nil
+	}
+	return &SwaggerConfig{
+		Route:    route,
+		Param:    p.swaggerParam(sig),
+		Response: p.swaggerResponse(sig),
+	}
+}
+
+func (p ParsedTags) swaggerRoute() *SwaggerConfigRoute {
+	if !p.Has(tagRouteMethod) || !p.Has(tagRoutePath) || !p.Has(tagRouteTag) {
+		return nil
+	}
+	route := &SwaggerConfigRoute{
+		Method: p.value(tagRouteMethod),
+		Path:   p.value(tagRoutePath),
+		Errors: p.errors(),
+	}
+	for _, v := range p[tagRouteTag] {
+		route.Tags = append(route.Tags, v.raw)
+	}
+	return route
+}
+
+func (p ParsedTags) indexType(name string, ts []*types.Type) *types.Type {
+	v := p.first(name)
+	if v == nil || v.index >= len(ts) {
+		return nil
+	}
+	return ts[v.index]
+}
+
+func (p ParsedTags) swaggerParam(sig *types.Signature) *SwaggerConfigParam {
+	paths := p.pairs(tagParamPath)
+	query := p.indexType(tagParamQueryIdx, sig.Parameters)
+	body := p.indexType(tagParamBodyIdx, sig.Parameters)
+	if paths == nil && query == nil && body == nil {
+		return nil
+	}
+	return &SwaggerConfigParam{
+		Query: query,
+		Body:  body,
+		Paths: paths,
+		Key:   p.value(tagParamBodyKey),
+	}
+}
+
+func (p ParsedTags) swaggerResponse(sig *types.Signature) *SwaggerConfigResponse {
+	output := p.indexType(tagRespIdx, sig.Results)
+	if output == nil {
+		return nil
+	}
+	resp := &SwaggerConfigResponse{
+		Output:  output,
+		BodyKey: p.value(tagRespBodyKey),
+		Headers: p.pairs(tagRespHeader),
+		IsList:  p.Has(tagRespBodyList),
+	}
+	resp.IsListOffset = resp.IsList && p.Has(tagRespBodyListOffset)
+	return resp
+}
@@ -0,0 +1,166 @@
+package generators
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func tagDecl(target TagTarget, params, results int, lines ...string) *TagDecl {
+	d := &TagDecl{
+		Subject: "models.Subject",
+		Target:  target,
+		Params:  params,
+		Results: results,
+	}
+	for _, l := range lines {
+		d.Lines = append(d.Lines, TagLine{Text: " " + l})
+	}
+	return d
+}
+
+func errorMessages(errs []*TagError) []string {
+	ret := make([]string, 0, len(errs))
+	for _, e := range errs {
+		ret = append(ret, e.Error())
+	}
+	return ret
+}
+
+func TestParseTags(t *testing.T) {
+	route := []string{
+		"+onecloud:swagger-gen-route-method=GET",
+		"+onecloud:swagger-gen-route-path=/servers/{id}",
+		"+onecloud:swagger-gen-route-tag=server",
+	}
+	cases := []struct {
+		name   string
+		decl   *TagDecl
+		errors []string
+	}{
+		{
+			name: "valid route",
+			decl: tagDecl(TagTargetFunc, 3, 2, append(route,
+				"+onecloud:swagger-gen-param-path=id:The server id",
+				"+onecloud:swagger-gen-param-query-index=2",
+				"+onecloud:swagger-gen-resp-index=0",
+				"+onecloud:swagger-gen-resp-error=404,NotFoundError",
+			)...),
+		},
+		{
+			name:   "unknown tag",
+			decl:   tagDecl(TagTargetType, -1, -1, "+onecloud:swagger-gen-model-singulr=server"),
+			errors: []string{"models.Subject: tag onecloud:swagger-gen-model-singulr: unknown tag"},
+		},
+		{
+			name:   "wrong target",
+			decl:   tagDecl(TagTargetMethod, -1, -1, "+onecloud:swagger-gen-route-method=GET"),
+			errors: []string{"models.Subject: tag onecloud:swagger-gen-route-method: can't be put on method, only on func"},
+		},
+		{
+			name:   "duplicated",
+			decl:   tagDecl(TagTargetType, -1, -1, "+onecloud:swagger-gen-model-plural=servers", "+onecloud:swagger-gen-model-plural=vms"),
+			errors: []string{"models.Subject: tag onecloud:swagger-gen-model-plural: is given more than once"},
+		},
+		{
+			name: "invalid method and index",
+			decl: tagDecl(TagTargetFunc, 2, 2,
+				"+onecloud:swagger-gen-route-method=FETCH",
+				"+onecloud:swagger-gen-route-path=/servers",
+				"+onecloud:swagger-gen-route-tag=server",
+				"+onecloud:swagger-gen-param-body-index=x",
+				"+onecloud:swagger-gen-resp-index=2",
+			),
+			errors: []string{
+				"models.Subject: tag onecloud:swagger-gen-route-method: invalid value \"FETCH\", must be one of GET, POST, PUT, DELETE, PATCH, HEAD",
+				"models.Subject: tag onecloud:swagger-gen-param-body-index: invalid index \"x\"",
+				"models.Subject: tag onecloud:swagger-gen-resp-index: index 2 is out of range, only 2 results",
+				"models.Subject: tag onecloud:swagger-gen-route-path: requires tag onecloud:swagger-gen-route-method",
+				"models.Subject: tag onecloud:swagger-gen-route-tag: requires tag onecloud:swagger-gen-route-method",
+			},
+		},
+		{
+			name:   "invalid error code",
+			decl:   tagDecl(TagTargetMethod, -1, -1, "+onecloud:swagger-gen-resp-error=600,Bad"),
+			errors: []string{"models.Subject: tag onecloud:swagger-gen-resp-error: invalid value \"600,Bad\": invalid http status code"},
+		},
+		{
+			name:   "missing requires",
+			decl:   tagDecl(TagTargetFunc, -1, -1, "+onecloud:swagger-gen-resp-body-list"),
+			errors: []string{"models.Subject: tag onecloud:swagger-gen-resp-body-list: requires tag onecloud:swagger-gen-resp-index"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, errs := ParseTags(c.decl)
+			got := errorMessages(errs)
+			if len(c.errors) == 0 && len(got) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, c.errors) {
+				t.Errorf("errors = %q, want %q", got, c.errors)
+			}
+		})
+	}
+}
+
+func TestParseTagsPair(t *testing.T) {
+	d := tagDecl(TagTargetFunc, -1, -1,
+		"+onecloud:swagger-gen-route-method=GET",
+		"+onecloud:swagger-gen-route-path=/servers/{id}/disks/{disk_id}",
+		"+onecloud:swagger-gen-route-tag=server",
+		"+onecloud:swagger-gen-param-path=id:The server id",
+		// legacy form gives key and value by two tags
+		"+onecloud:swagger-gen-param-path=disk_id",
+		"+onecloud:swagger-gen-param-path=The disk id",
+		"+onecloud:swagger-gen-param-path=name",
+	)
+	tags, errs := ParseTags(d)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := map[string]string{
+		"id":      "The server id",
+		"disk_id": "The disk id",
+		"name":    "name",
+	}
+	if got := tags.pairs(tagParamPath); !reflect.DeepEqual(got, want) {
+		t.Errorf("pairs = %v, want %v", got, want)
+	}
+	route := tags.swaggerRoute()
+	if route == nil || route.Method != "GET" || !reflect.DeepEqual(route.Tags, []string{"server"}) {
+		t.Errorf("route = %#v", route)
+	}
+}
+
+func TestLintFunc(t *testing.T) {
+	src := `package models
+
+// +onecloud:swagger-gen-route-method=GET
+// +onecloud:swagger-gen-route-path=/ping
+// +onecloud:swagger-gen-route-tag=misc
+
+// Ping replies pong
+func Ping() {}
+
+// Pong replies ping
+// +onecloud:swagger-gen-route-method=GET
+func Pong() {}
+`
+	file := filepath.Join(t.TempDir(), "ping.go")
+	if err := ioutil.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	errs, err := lintPackageTags(filepath.Dir(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("errors = %v, want only the tag in doc comment of Pong", errs)
+	}
+	if e := errs[0]; e.Subject != "models.Pong" || e.Tag != tagRouteMethod || !strings.HasSuffix(e.Position, "ping.go:11:1") {
+		t.Errorf("error = %v", e)
+	}
+}
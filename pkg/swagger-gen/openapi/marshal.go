@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// FormatOfFile returns the document format by file extension.
+func FormatOfFile(fileName string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		return FormatJSON
+	default:
+		return FormatYAML
+	}
+}
+
+// Extension returns the file extension of format.
+func Extension(format string) string {
+	if format == FormatJSON {
+		return ".json"
+	}
+	return ".yaml"
+}
+
+// Marshal encodes document to format.
+func Marshal(doc *Document, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		out, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(out, '\n'), nil
+	case FormatYAML:
+		buf := new(bytes.Buffer)
+		enc := yaml.NewEncoder(buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported openapi format %q", format)
+	}
+}
+
+// Unmarshal decodes document from format.
+func Unmarshal(data []byte, format string) (*Document, error) {
+	doc := new(Document)
+	var err error
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, doc)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, doc)
+	default:
+		err = fmt.Errorf("unsupported openapi format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
@@ -0,0 +1,274 @@
+// Package openapi is the subset of the OpenAPI 3.0 document model emitted
+// by swagger-gen.
+package openapi
+
+const (
+	Version = "3.0.3"
+
+	MediaTypeJSON = "application/json"
+
+	ParamInPath   = "path"
+	ParamInQuery  = "query"
+	ParamInHeader = "header"
+
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeArray   = "array"
+	TypeObject  = "object"
+
+	ComponentSchemasPrefix       = "#/components/schemas/"
+	ComponentResponsesPrefix     = "#/components/responses/"
+	ComponentParametersPrefix    = "#/components/parameters/"
+	ComponentRequestBodiesPrefix = "#/components/requestBodies/"
+)
+
+type Document struct {
+	OpenAPI      string                `json:"openapi" yaml:"openapi"`
+	Info         *Info                 `json:"info" yaml:"info"`
+	Servers      []*Server             `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Security     []SecurityRequirement `json:"security,omitempty" yaml:"security,omitempty"`
+	Tags         []*Tag                `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Paths        map[string]*PathItem  `json:"paths" yaml:"paths"`
+	Components   *Components           `json:"components,omitempty" yaml:"components,omitempty"`
+	ExternalDocs *ExternalDocs         `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+}
+
+func NewDocument(info *Info) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      make(map[string]*PathItem),
+		Components: NewComponents(),
+	}
+}
+
+type Info struct {
+	Title       string   `json:"title" yaml:"title"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string   `json:"version" yaml:"version"`
+	Contact     *Contact `json:"contact,omitempty" yaml:"contact,omitempty"`
+	License     *License `json:"license,omitempty" yaml:"license,omitempty"`
+}
+
+type Contact struct {
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	URL   string `json:"url,omitempty" yaml:"url,omitempty"`
+	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+}
+
+type License struct {
+	Name string `json:"name" yaml:"name"`
+	URL  string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+type Server struct {
+	URL         string `json:"url" yaml:"url"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+type Tag struct {
+	Name         string        `json:"name" yaml:"name"`
+	Description  string        `json:"description,omitempty" yaml:"description,omitempty"`
+	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+}
+
+type ExternalDocs struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	URL         string `json:"url" yaml:"url"`
+}
+
+// SecurityRequirement maps a security scheme name to its required scopes.
+type SecurityRequirement map[string][]string
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+	Responses       map[string]*Response       `json:"responses,omitempty" yaml:"responses,omitempty"`
+	Parameters      map[string]*Parameter      `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBodies   map[string]*RequestBody    `json:"requestBodies,omitempty" yaml:"requestBodies,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+}
+
+func NewComponents() *Components {
+	return &Components{
+		Schemas:         make(map[string]*Schema),
+		Responses:       make(map[string]*Response),
+		Parameters:      make(map[string]*Parameter),
+		RequestBodies:   make(map[string]*RequestBody),
+		SecuritySchemes: make(map[string]*SecurityScheme),
+	}
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type" yaml:"type"`
+	Description  string `json:"description,omitempty" yaml:"description,omitempty"`
+	Name         string `json:"name,omitempty" yaml:"name,omitempty"`
+	In           string `json:"in,omitempty" yaml:"in,omitempty"`
+	Scheme       string `json:"scheme,omitempty" yaml:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
+}
+
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty" yaml:"get,omitempty"`
+	Put    *Operation `json:"put,omitempty" yaml:"put,omitempty"`
+	Post   *Operation `json:"post,omitempty" yaml:"post,omitempty"`
+	Delete *Operation `json:"delete,omitempty" yaml:"delete,omitempty"`
+	Patch  *Operation `json:"patch,omitempty" yaml:"patch,omitempty"`
+	Head   *Operation `json:"head,omitempty" yaml:"head,omitempty"`
+}
+
+// Operations returns the operations of the path item keyed by lower case
+// http method.
+func (p *PathItem) Operations() map[string]*Operation {
+	ret := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		"get":    p.Get,
+		"put":    p.Put,
+		"post":   p.Post,
+		"delete": p.Delete,
+		"patch":  p.Patch,
+		"head":   p.Head,
+	} {
+		if op != nil {
+			ret[method] = op
+		}
+	}
+	return ret
+}
+
+// SetOperation sets operation of http method, it returns false if the
+// method is not supported.
+func (p *PathItem) SetOperation(method string, op *Operation) bool {
+	switch method {
+	case "get", "GET":
+		p.Get = op
+	case "put", "PUT":
+		p.Put = op
+	case "post", "POST":
+		p.Post = op
+	case "delete", "DELETE":
+		p.Delete = op
+	case "patch", "PATCH":
+		p.Patch = op
+	case "head", "HEAD":
+		p.Head = op
+	default:
+		return false
+	}
+	return true
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Tags        []string             `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses" yaml:"responses"`
+	Deprecated  bool                 `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+}
+
+type Parameter struct {
+	Ref         string  `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Name        string  `json:"name,omitempty" yaml:"name,omitempty"`
+	In          string  `json:"in,omitempty" yaml:"in,omitempty"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool    `json:"required,omitempty" yaml:"required,omitempty"`
+	Deprecated  bool    `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Style       string  `json:"style,omitempty" yaml:"style,omitempty"`
+	Explode     *bool   `json:"explode,omitempty" yaml:"explode,omitempty"`
+	Schema      *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+type RequestBody struct {
+	Ref         string                `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty" yaml:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type Response struct {
+	Ref         string                `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Description string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Headers     map[string]*Header    `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Schema      *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// JSONContent returns the content map of a single application/json media
+// type described by schema.
+func JSONContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{
+		MediaTypeJSON: {Schema: schema},
+	}
+}
+
+type Schema struct {
+	Ref         string `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string `json:"format,omitempty" yaml:"format,omitempty"`
+	Title       string `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Nullable    bool   `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+
+	AllOf []*Schema `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	OneOf []*Schema `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
+
+	Enum    []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Default interface{}   `json:"default,omitempty" yaml:"default,omitempty"`
+	Example interface{}   `json:"example,omitempty" yaml:"example,omitempty"`
+
+	Minimum    *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum    *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	Pattern    string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Deprecated bool     `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+}
+
+// RefSchema returns a schema refer to component schema name.
+func RefSchema(name string) *Schema {
+	return &Schema{Ref: ComponentSchemasPrefix + name}
+}
+
+// ArraySchema returns an array schema of items.
+func ArraySchema(items *Schema) *Schema {
+	return &Schema{Type: TypeArray, Items: items}
+}
+
+// ObjectSchema returns an object schema with properties.
+func ObjectSchema(props map[string]*Schema) *Schema {
+	return &Schema{Type: TypeObject, Properties: props}
+}
+
+// IsRef returns true if the schema only refers to another schema.
+func (s *Schema) IsRef() bool {
+	return s != nil && s.Ref != ""
+}
+
+// Nullable marks schema nullable. A $ref can't carry sibling keywords in
+// OpenAPI 3.0, so the reference is wrapped by allOf.
+func Nullable(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	if s.IsRef() {
+		return &Schema{AllOf: []*Schema{s}, Nullable: true}
+	}
+	s.Nullable = true
+	return s
+}
@@ -0,0 +1,264 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// pathParamRegexp matches the parameters of path templates, e.g. {id}.
+	pathParamRegexp = regexp.MustCompile(`\{([^{}]+)\}`)
+	// responseCodeRegexp matches the http status codes of responses, e.g.
+	// 200 or 4XX.
+	responseCodeRegexp = regexp.MustCompile(`^[1-5]([0-9]{2}|XX)$`)
+)
+
+// IsVersion3_0 returns true if version is an OpenAPI 3.0.x version, the
+// versions Validate checks.
+func IsVersion3_0(version string) bool {
+	return version == "3.0" || strings.HasPrefix(version, "3.0.")
+}
+
+// Validate checks the rules of OpenAPI 3.0 the document model describes:
+// the required fields, path templates and their parameters, unique
+// operationIds and $refs to components. All problems are reported in the
+// returned error, sorted.
+func Validate(doc *Document) error {
+	v := &validator{doc: doc}
+	v.validate()
+	if len(v.errs) == 0 {
+		return nil
+	}
+	sort.Strings(v.errs)
+	return fmt.Errorf("%s", strings.Join(v.errs, "; "))
+}
+
+type validator struct {
+	doc  *Document
+	errs []string
+}
+
+func (v *validator) errorf(format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) validate() {
+	doc := v.doc
+	if !IsVersion3_0(doc.OpenAPI) {
+		v.errorf("openapi version %q isn't 3.0.x", doc.OpenAPI)
+	}
+	if doc.Info == nil {
+		v.errorf("info is required")
+	} else {
+		if doc.Info.Title == "" {
+			v.errorf("info.title is required")
+		}
+		if doc.Info.Version == "" {
+			v.errorf("info.version is required")
+		}
+	}
+	if doc.Paths == nil {
+		v.errorf("paths is required")
+	}
+	paths := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	operationIds := make(map[string]string)
+	for _, p := range paths {
+		if !strings.HasPrefix(p, "/") {
+			v.errorf("path %s must begin with /", p)
+		}
+		item := doc.Paths[p]
+		if item == nil {
+			continue
+		}
+		ops := item.Operations()
+		methods := make([]string, 0, len(ops))
+		for m := range ops {
+			methods = append(methods, m)
+		}
+		sort.Strings(methods)
+		for _, m := range methods {
+			op := ops[m]
+			at := fmt.Sprintf("%s %s", strings.ToUpper(m), p)
+			if op.OperationID != "" {
+				if prev, ok := operationIds[op.OperationID]; ok {
+					v.errorf("%s: operationId %s is used by %s already", at, op.OperationID, prev)
+				}
+				operationIds[op.OperationID] = at
+			}
+			v.operation(at, p, op)
+		}
+	}
+	if c := doc.Components; c != nil {
+		for name, s := range c.Schemas {
+			v.schema("components.schemas."+name, s)
+		}
+		for name, p := range c.Parameters {
+			v.parameter("components.parameters."+name, p)
+		}
+		for name, r := range c.Responses {
+			v.response("components.responses."+name, r)
+		}
+		for name, b := range c.RequestBodies {
+			v.requestBody("components.requestBodies."+name, b)
+		}
+	}
+}
+
+func (v *validator) operation(at, path string, op *Operation) {
+	if len(op.Responses) == 0 {
+		v.errorf("%s: responses are required", at)
+	}
+	pathParams := make(map[string]bool)
+	for _, p := range op.Parameters {
+		v.parameter(at, p)
+		if p = v.resolveParameter(p); p != nil && p.In == ParamInPath {
+			pathParams[p.Name] = true
+		}
+	}
+	for _, m := range pathParamRegexp.FindAllStringSubmatch(path, -1) {
+		if !pathParams[m[1]] {
+			v.errorf("%s: path parameter %s isn't declared", at, m[1])
+		}
+	}
+	if op.RequestBody != nil {
+		v.requestBody(at, op.RequestBody)
+	}
+	for code, r := range op.Responses {
+		if code != "default" && !responseCodeRegexp.MatchString(code) {
+			v.errorf("%s: invalid response code %q", at, code)
+		}
+		v.response(at, r)
+	}
+}
+
+func (v *validator) ref(at, ref, prefix string, exists func(string) bool) {
+	if !strings.HasPrefix(ref, prefix) {
+		v.errorf("%s: $ref %s isn't a reference of %s", at, ref, prefix)
+		return
+	}
+	if !exists(strings.TrimPrefix(ref, prefix)) {
+		v.errorf("%s: $ref %s isn't found", at, ref)
+	}
+}
+
+func (v *validator) components() *Components {
+	if v.doc.Components == nil {
+		return &Components{}
+	}
+	return v.doc.Components
+}
+
+func (v *validator) resolveParameter(p *Parameter) *Parameter {
+	if p == nil || p.Ref == "" {
+		return p
+	}
+	return v.components().Parameters[strings.TrimPrefix(p.Ref, ComponentParametersPrefix)]
+}
+
+func (v *validator) parameter(at string, p *Parameter) {
+	if p == nil {
+		return
+	}
+	if p.Ref != "" {
+		v.ref(at, p.Ref, ComponentParametersPrefix, func(name string) bool {
+			_, ok := v.components().Parameters[name]
+			return ok
+		})
+		return
+	}
+	if p.Name == "" {
+		v.errorf("%s: name of parameter is required", at)
+	}
+	switch p.In {
+	case ParamInPath:
+		if !p.Required {
+			v.errorf("%s: path parameter %s must be required", at, p.Name)
+		}
+	case ParamInQuery, ParamInHeader, "cookie":
+	default:
+		v.errorf("%s: invalid location %q of parameter %s", at, p.In, p.Name)
+	}
+	v.schema(at, p.Schema)
+}
+
+func (v *validator) requestBody(at string, b *RequestBody) {
+	if b.Ref != "" {
+		v.ref(at, b.Ref, ComponentRequestBodiesPrefix, func(name string) bool {
+			_, ok := v.components().RequestBodies[name]
+			return ok
+		})
+		return
+	}
+	if len(b.Content) == 0 {
+		v.errorf("%s: content of request body is required", at)
+	}
+	for _, mt := range b.Content {
+		if mt != nil {
+			v.schema(at, mt.Schema)
+		}
+	}
+}
+
+func (v *validator) response(at string, r *Response) {
+	if r == nil {
+		return
+	}
+	if r.Ref != "" {
+		v.ref(at, r.Ref, ComponentResponsesPrefix, func(name string) bool {
+			_, ok := v.components().Responses[name]
+			return ok
+		})
+		return
+	}
+	if r.Description == "" {
+		v.errorf("%s: description of response is required", at)
+	}
+	for _, h := range r.Headers {
+		if h != nil {
+			v.schema(at, h.Schema)
+		}
+	}
+	for _, mt := range r.Content {
+		if mt != nil {
+			v.schema(at, mt.Schema)
+		}
+	}
+}
+
+func (v *validator) schema(at string, s *Schema) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		v.ref(at, s.Ref, ComponentSchemasPrefix, func(name string) bool {
+			_, ok := v.components().Schemas[name]
+			return ok
+		})
+		return
+	}
+	switch s.Type {
+	case "", TypeString, TypeInteger, TypeNumber, TypeBoolean, TypeObject:
+	case TypeArray:
+		if s.Items == nil {
+			v.errorf("%s: items of array schema are required", at)
+		}
+	default:
+		v.errorf("%s: invalid schema type %q", at, s.Type)
+	}
+	v.schema(at, s.Items)
+	v.schema(at, s.AdditionalProperties)
+	for _, p := range s.Properties {
+		v.schema(at, p)
+	}
+	for _, subs := range [][]*Schema{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, sub := range subs {
+			v.schema(at, sub)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package openapi
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	// the spec swagger-gen generates over the fixture
+	data, err := ioutil.ReadFile(filepath.Join("..", "generators", "testdata", "golden", "openapi", "yunion.io", "x", "onecloud", "pkg", "generated", "swagger", "compute", "zz_generated.swagger_spec_compute.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Unmarshal(data, FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(doc); err != nil {
+		t.Errorf("generated spec is invalid: %v", err)
+	}
+
+	invalid := NewDocument(&Info{Title: "compute"})
+	invalid.OpenAPI = "3.1.0"
+	invalid.Paths["servers/{id}"] = &PathItem{
+		Get: &Operation{
+			OperationID: "get",
+			Responses: map[string]*Response{
+				"200": {Description: "ok", Content: JSONContent(RefSchema("ServerDetails"))},
+				"ok":  {Ref: ComponentResponsesPrefix + "Error"},
+			},
+		},
+		Put: &Operation{
+			OperationID: "get",
+			Parameters:  []*Parameter{{Name: "id", In: ParamInPath}},
+			Responses:   map[string]*Response{"200": {}},
+		},
+	}
+	want := []string{
+		`GET servers/{id}: $ref #/components/responses/Error isn't found`,
+		`GET servers/{id}: $ref #/components/schemas/ServerDetails isn't found`,
+		`GET servers/{id}: invalid response code "ok"`,
+		`GET servers/{id}: path parameter id isn't declared`,
+		`PUT servers/{id}: description of response is required`,
+		`PUT servers/{id}: operationId get is used by GET servers/{id} already`,
+		`PUT servers/{id}: path parameter id must be required`,
+		`info.version is required`,
+		`openapi version "3.1.0" isn't 3.0.x`,
+		`path servers/{id} must begin with /`,
+	}
+	err = Validate(invalid)
+	if err == nil {
+		t.Fatal("invalid spec is valid")
+	}
+	if got := strings.Split(err.Error(), "; "); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,21 @@
+package generators
+
+import (
+	"path/filepath"
+	"testing"
+
+	"k8s.io/gengo/args"
+
+	"yunion.io/x/code-generator/pkg/common/golden"
+)
+
+func TestGolden(t *testing.T) {
+	arguments := args.Default().WithoutDefaultFlagParsing()
+	arguments.InputDirs = []string{
+		"yunion.io/x/onecloud/pkg/compute/models",
+		"yunion.io/x/onecloud/pkg/image/models",
+	}
+	arguments.OutputPackagePath = "yunion.io/x/onecloud/pkg/generated/typescript"
+	dir := golden.Run(t, arguments, NameSystems(), DefaultNameSystem(), Packages)
+	golden.Compare(t, dir, filepath.Join("testdata", "golden"))
+}
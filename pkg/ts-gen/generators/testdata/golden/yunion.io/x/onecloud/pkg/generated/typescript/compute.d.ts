@@ -0,0 +1,150 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by generators.test. DO NOT EDIT.
+
+/**
+ * SDisk is a disk attached to server
+ *
+ * Deprecated: disks are listed by the disk resource.
+ */
+export interface SDisk {
+  /**
+   * 磁盘大小, 单位MB
+   */
+  disk_size: number;
+  /**
+   * Id of the storage, resolved by the scheduler cache
+   */
+  storage: string;
+  /**
+   * Passphrase of the encrypted disk
+   */
+  encrypt_password: string;
+}
+
+/**
+ * SServer is a virtual machine
+ */
+export interface SServer extends SStandaloneResourceBase {
+  /**
+   * Cpu count
+   */
+  vcpu_count: number;
+  /**
+   * Memory size in MB
+   */
+  vmem_size: number;
+  /**
+   * Boot order of devices, e.g. cdn
+   *
+   * Deprecated: use the boot index of disks instead.
+   */
+  boot_order: string;
+  /**
+   * 虚拟机状态
+   */
+  status: ServerStatus;
+  /**
+   * 是否禁用
+   */
+  disabled?: boolean | undefined;
+  /**
+   * 最近一次启动时间
+   */
+  last_start_at: string;
+  /**
+   * Start the server with its host
+   */
+  auto_start: string;
+  /**
+   * Id of the backup host
+   */
+  backup_host_id: string;
+  pending_deleted: boolean;
+  /**
+   * Metadata of the image, kept by the image cache
+   */
+  image_meta: { [key: string]: string };
+  /**
+   * Timeout of the guest agent
+   */
+  agent_timeout?: Duration;
+  tags: ServerTags;
+  disks: SDisk[];
+}
+
+export interface ServerDetails extends StandaloneResourceDetails, SServer {
+  /**
+   * 宿主机名称
+   */
+  host: string;
+  /**
+   * Disks grouped by storage
+   */
+  storage_disks: { [key: string]: SDisk[] };
+  /**
+   * Password of the vnc console
+   */
+  vnc_password: string;
+}
+
+/**
+ * ServerStatus is the status of a server
+ */
+export type ServerStatus = string;
+
+/**
+ * ServerTags are the user tags of a server, cached by the region service
+ */
+export type ServerTags = { [key: string]: string };
+
+/**
+ * TriState is true, false or none
+ */
+export type TriState = string;
+
+export interface SStandaloneResourceBase extends SResourceBase {
+  /**
+   * 资源UUID
+   */
+  id: string;
+  /**
+   * 资源名称
+   */
+  name: string;
+  /**
+   * 资源描述信息
+   */
+  description: string;
+}
+
+export type Duration = number;
+
+export interface StandaloneResourceDetails {
+  /**
+   * 资源是否可以删除
+   */
+  can_delete: boolean;
+}
+
+export interface SResourceBase {
+  /**
+   * 资源创建时间
+   */
+  created_at: string;
+  /**
+   * 资源更新时间
+   */
+  updated_at: string;
+}
+
@@ -0,0 +1,398 @@
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/gengo/args"
+	"k8s.io/gengo/generator"
+	"k8s.io/gengo/namer"
+	"k8s.io/gengo/types"
+	"k8s.io/klog"
+
+	"yunion.io/x/pkg/util/reflectutils"
+	"yunion.io/x/pkg/util/sets"
+
+	"yunion.io/x/code-generator/pkg/common"
+	apigen "yunion.io/x/code-generator/pkg/model-api-gen/generators"
+	"yunion.io/x/code-generator/pkg/swagger-gen/generators"
+)
+
+const (
+	// typescriptFileType is the gengo file type of .d.ts files
+	typescriptFileType = "typescript"
+)
+
+// NameSystems returns the name system used by the generators in this package.
+func NameSystems() namer.NameSystems {
+	return namer.NameSystems{
+		"public": namer.NewPublicNamer(0),
+		"raw":    namer.NewRawNamer("", nil),
+	}
+}
+
+// DefaultNameSystem returns the default name system for ordering the types to be
+// processed by the generators in this package.
+func DefaultNameSystem() string {
+	return "public"
+}
+
+func registerTypeScriptFileType(c *generator.Context) {
+	c.FileTypes[typescriptFileType] = generator.DefaultFileType{
+		Format: func(src []byte) ([]byte, error) {
+			return src, nil
+		},
+		Assemble: func(w io.Writer, f *generator.File) {
+			w.Write(f.Header)
+			w.Write(f.Body.Bytes())
+		},
+	}
+}
+
+// ModuleName returns the .d.ts module name of source package, e.g.
+// yunion.io/x/onecloud/pkg/compute/models is compute.
+func ModuleName(pkgPath string) string {
+	return filepath.Base(strings.TrimSuffix(pkgPath, "/models"))
+}
+
+// Packages makes the ts-gen package definition.
+func Packages(ctx *generator.Context, arguments *args.GeneratorArgs) generator.Packages {
+	boilerplate, err := arguments.LoadGoBoilerplate()
+	if err != nil {
+		klog.Fatalf("Failed loading boilerplate: %v", err)
+	}
+	registerTypeScriptFileType(ctx)
+	if arguments.VerifyOnly {
+		common.RegisterVerifyFileTypes(ctx, os.Stdout)
+	}
+
+	inputs := sets.NewString(ctx.Inputs...)
+	packages := generator.Packages{}
+	for i := range inputs {
+		pkg := ctx.Universe[i]
+		if pkg == nil {
+			continue
+		}
+		klog.Infof("Considering pkg %q", pkg.Path)
+		outPkgName := strings.Split(filepath.Base(arguments.OutputPackagePath), ".")[0]
+		packages = append(packages,
+			&generator.DefaultPackage{
+				PackageName: outPkgName,
+				PackagePath: arguments.OutputPackagePath,
+				HeaderText:  boilerplate,
+				GeneratorFunc: func(c *generator.Context) []generator.Generator {
+					return []generator.Generator{
+						NewTSGen(ModuleName(pkg.Path), pkg.Path, inputs, ctx.Order),
+					}
+				},
+			})
+	}
+	return packages
+}
+
+type tsGen struct {
+	generator.DefaultGen
+	sourcePackage string
+	// inputPackages are imported by module name instead of inlined
+	inputPackages sets.String
+	// modelTypes record all model types in source package
+	modelTypes sets.String
+	// modelDependTypes record all model required types
+	modelDependTypes sets.String
+
+	// imports maps module name to imported interface names
+	imports map[string]sets.String
+	// externals are types of non input packages inlined into this file
+	externals    []*types.Type
+	externalSeen sets.String
+	// names maps type full name to its name in this file
+	names map[string]string
+	// owners maps name in this file to type full name
+	owners map[string]string
+	body   *bytes.Buffer
+}
+
+func NewTSGen(moduleName, sourcePackage string, inputPackages sets.String, pkgTypes []*types.Type) generator.Generator {
+	modelTypes, dependTypes := apigen.CollectTypes(sourcePackage, pkgTypes)
+	g := &tsGen{
+		DefaultGen: generator.DefaultGen{
+			OptionalName: moduleName,
+		},
+		sourcePackage:    sourcePackage,
+		inputPackages:    inputPackages,
+		modelTypes:       modelTypes,
+		modelDependTypes: dependTypes,
+		imports:          make(map[string]sets.String),
+		externalSeen:     sets.NewString(),
+		names:            make(map[string]string),
+		owners:           make(map[string]string),
+		body:             new(bytes.Buffer),
+	}
+	// types of source package keep their names
+	for _, key := range modelTypes.Union(dependTypes).List() {
+		idx := strings.LastIndex(key, ".")
+		g.nameOf(key[:idx], key[idx+1:])
+	}
+	return g
+}
+
+func (g *tsGen) Filename() string {
+	return g.OptionalName + ".d.ts"
+}
+
+func (g *tsGen) FileType() string {
+	return typescriptFileType
+}
+
+func (g *tsGen) Filter(c *generator.Context, t *types.Type) bool {
+	if generators.IncludeIgnoreTag(t) {
+		return false
+	}
+	return g.modelTypes.Has(t.String()) || g.modelDependTypes.Has(t.String())
+}
+
+func (g *tsGen) GenerateType(c *generator.Context, t *types.Type, w io.Writer) error {
+	klog.V(1).Infof("Generating typescript definition for type %s", t.String())
+	g.generateType(t, g.body)
+	return nil
+}
+
+func (g *tsGen) Finalize(c *generator.Context, w io.Writer) error {
+	// inline types of non input packages, which may refer to more
+	for i := 0; i < len(g.externals); i++ {
+		g.generateType(g.externals[i], g.body)
+	}
+	modules := make([]string, 0, len(g.imports))
+	for m := range g.imports {
+		modules = append(modules, m)
+	}
+	sort.Strings(modules)
+	for _, m := range modules {
+		fmt.Fprintf(w, "import type { %s } from './%s';\n", strings.Join(g.imports[m].List(), ", "), m)
+	}
+	if len(modules) > 0 {
+		fmt.Fprintln(w)
+	}
+	_, err := w.Write(g.body.Bytes())
+	return err
+}
+
+func writeDoc(w io.Writer, indent string, lines []string) {
+	doc := make([]string, 0, len(lines))
+	for _, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(l), "+") {
+			continue
+		}
+		doc = append(doc, l)
+	}
+	for len(doc) > 0 && strings.TrimSpace(doc[len(doc)-1]) == "" {
+		doc = doc[:len(doc)-1]
+	}
+	if len(doc) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s/**\n", indent)
+	for _, l := range doc {
+		fmt.Fprintln(w, strings.TrimRight(fmt.Sprintf("%s * %s", indent, strings.TrimSpace(l)), " "))
+	}
+	fmt.Fprintf(w, "%s */\n", indent)
+}
+
+func (g *tsGen) generateType(t *types.Type, w io.Writer) {
+	writeDoc(w, "", t.CommentLines)
+	switch t.Kind {
+	case types.Struct:
+		g.generateInterface(g.nameOf(t.Name.Package, t.Name.Name), t, w)
+	case types.Alias:
+		fmt.Fprintf(w, "export type %s = %s;\n\n", g.nameOf(t.Name.Package, t.Name.Name), g.typeOf(t.Underlying))
+	default:
+		klog.Warningf("Skip unsupported type %s, kind is %s", t.String(), t.Kind)
+	}
+}
+
+func isModelBase(t *types.Type) bool {
+	return t.Name.Name == apigen.SModelBase
+}
+
+func (g *tsGen) generateInterface(name string, t *types.Type, w io.Writer) {
+	extends := make([]string, 0)
+	fields := new(bytes.Buffer)
+	for _, m := range t.Members {
+		mt := m.Type
+		if mt.Kind == types.Pointer && m.Embedded {
+			mt = mt.Elem
+		}
+		if isModelBase(mt) {
+			continue
+		}
+		if m.Embedded && mt.Kind == types.Struct && mt.Name.Package != "" {
+			extends = append(extends, g.typeOf(mt))
+			continue
+		}
+		if common.IsPrivateStruct(m.Name) {
+			continue
+		}
+		info := reflectutils.ParseFieldJsonInfo(m.Name, reflect.StructTag(m.Tags))
+		if info.Ignore {
+			continue
+		}
+		if val, ok := info.Tags["ignore"]; ok && val == "true" {
+			continue
+		}
+		optional := ""
+		if isOmitEmpty(m) || m.Type.Kind == types.Pointer || isTriState(m.Type) {
+			optional = "?"
+		}
+		writeDoc(fields, "  ", m.CommentLines)
+		fmt.Fprintf(fields, "  %s%s: %s;\n", propertyName(info.MarshalName()), optional, g.typeOf(m.Type))
+	}
+	fmt.Fprintf(w, "export interface %s", name)
+	if len(extends) > 0 {
+		fmt.Fprintf(w, " extends %s", strings.Join(extends, ", "))
+	}
+	fmt.Fprintf(w, " {\n%s}\n\n", fields.String())
+}
+
+func isOmitEmpty(m types.Member) bool {
+	tag, ok := reflect.StructTag(m.Tags).Lookup("json")
+	if !ok {
+		return false
+	}
+	for _, opt := range strings.Split(tag, ",")[1:] {
+		if opt == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
+
+func isTriState(t *types.Type) bool {
+	return t.Kind == types.Alias && t.Name.Name == "TriState"
+}
+
+func propertyName(name string) string {
+	for _, r := range name {
+		if !(r == '_' || r == '$' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')) {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	return name
+}
+
+func builtinType(name string) string {
+	switch name {
+	case "string":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"byte", "rune", "float32", "float64":
+		return "number"
+	default:
+		return "any"
+	}
+}
+
+// typeOf renders the typescript type expression of t.
+func (g *tsGen) typeOf(t *types.Type) string {
+	switch t.Kind {
+	case types.Builtin:
+		return builtinType(t.Name.Name)
+	case types.Pointer:
+		return g.typeOf(t.Elem)
+	case types.Slice, types.Array:
+		if t.Elem.Kind == types.Builtin && t.Elem.Name.Name == "byte" {
+			return "string"
+		}
+		elem := g.typeOf(t.Elem)
+		if strings.Contains(elem, "|") {
+			elem = fmt.Sprintf("(%s)", elem)
+		}
+		return elem + "[]"
+	case types.Map:
+		return fmt.Sprintf("{ [key: string]: %s }", g.typeOf(t.Elem))
+	case types.Interface:
+		return "any"
+	case types.Alias:
+		if isTriState(t) {
+			return "boolean | undefined"
+		}
+		return g.reference(t)
+	case types.Struct:
+		if t.Name.Package == "time" && t.Name.Name == "Time" {
+			return "string"
+		}
+		if common.IsJSONObject(t) {
+			return "any"
+		}
+		if t.Name.Package == "" {
+			// anonymous struct
+			fields := new(bytes.Buffer)
+			g.generateInterface("", t, fields)
+			body := fields.String()
+			return strings.TrimSpace(body[strings.Index(body, "{"):])
+		}
+		return g.reference(t)
+	default:
+		klog.Warningf("Unsupported type %s, kind is %s, use any", t.String(), t.Kind)
+		return "any"
+	}
+}
+
+// nameOf returns an unique name in this file of type name of package pkg,
+// the name is qualified by module name of pkg if another type has it.
+func (g *tsGen) nameOf(pkg, name string) string {
+	key := pkg + "." + name
+	if ret, ok := g.names[key]; ok {
+		return ret
+	}
+	ret := name
+	if owner, ok := g.owners[ret]; ok && owner != key {
+		prefix := strings.Map(func(r rune) rune {
+			if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+				return r
+			}
+			return -1
+		}, ModuleName(pkg))
+		ret = strings.Title(prefix) + name
+		for i := 2; g.owners[ret] != ""; i++ {
+			ret = fmt.Sprintf("%s%s%d", strings.Title(prefix), name, i)
+		}
+	}
+	g.names[key] = ret
+	g.owners[ret] = key
+	return ret
+}
+
+// reference returns the interface name of named type t and records where
+// its definition comes from.
+func (g *tsGen) reference(t *types.Type) string {
+	pkg := t.Name.Package
+	name := g.nameOf(pkg, t.Name.Name)
+	switch {
+	case g.modelTypes.Has(t.String()) || g.modelDependTypes.Has(t.String()):
+	case pkg != g.sourcePackage && g.inputPackages.Has(pkg):
+		module := ModuleName(pkg)
+		if _, ok := g.imports[module]; !ok {
+			g.imports[module] = sets.NewString()
+		}
+		if name != t.Name.Name {
+			g.imports[module].Insert(fmt.Sprintf("%s as %s", t.Name.Name, name))
+		} else {
+			g.imports[module].Insert(name)
+		}
+	default:
+		if !g.externalSeen.Has(t.String()) {
+			g.externalSeen.Insert(t.String())
+			g.externals = append(g.externals, t)
+		}
+	}
+	return name
+}
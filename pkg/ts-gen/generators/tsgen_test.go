@@ -0,0 +1,48 @@
+package generators
+
+import (
+	"bytes"
+	"testing"
+
+	"k8s.io/gengo/types"
+
+	"yunion.io/x/pkg/util/sets"
+)
+
+func TestSameNamedTypes(t *testing.T) {
+	source := "example.com/svc/compute/models"
+	named := func(pkg, name string, kind types.Kind, underlying *types.Type) *types.Type {
+		return &types.Type{Name: types.Name{Package: pkg, Name: name}, Kind: kind, Underlying: underlying}
+	}
+	int64Type := &types.Type{Name: types.Name{Name: "int64"}, Kind: types.Builtin}
+	stringType := &types.Type{Name: types.Name{Name: "string"}, Kind: types.Builtin}
+	server := named(source, "SServer", types.Struct, nil)
+	server.Members = []types.Member{
+		{Name: "Timeout", Type: named("time", "Duration", types.Alias, int64Type), Tags: `json:"timeout"`},
+		{Name: "Window", Type: named("example.com/svc/units", "Duration", types.Alias, stringType), Tags: `json:"window"`},
+		{Name: "Retention", Type: named("example.com/svc/image/models", "Duration", types.Struct, nil), Tags: `json:"retention"`},
+	}
+
+	g := NewTSGen("compute", source, sets.NewString(source, "example.com/svc/image/models"), nil).(*tsGen)
+	g.generateType(server, g.body)
+	out := new(bytes.Buffer)
+	if err := g.Finalize(nil, out); err != nil {
+		t.Fatal(err)
+	}
+	want := `import type { Duration as ImageDuration } from './image';
+
+export interface SServer {
+  timeout: Duration;
+  window: UnitsDuration;
+  retention: ImageDuration;
+}
+
+export type Duration = number;
+
+export type UnitsDuration = string;
+
+`
+	if got := out.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package apis
+
+import (
+	"fmt"
+	"time"
+)
+
+type ResourceBase struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type StandaloneResourceBase struct {
+	ResourceBase
+
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type StandaloneResourceDetails struct {
+	// 资源是否可以删除
+	CanDelete bool `json:"can_delete"`
+}
+
+func (in *StandaloneResourceDetails) DeepCopyInto(out *StandaloneResourceDetails) {
+	*out = *in
+}
+
+func (in *StandaloneResourceDetails) DeepCopy() *StandaloneResourceDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(StandaloneResourceDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *StandaloneResourceDetails) Equal(other *StandaloneResourceDetails) bool {
+	if in == nil || other == nil {
+		return in == other
+	}
+	return in.CanDelete == other.CanDelete
+}
+
+func (in *StandaloneResourceDetails) String() string {
+	if in == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("{CanDelete:%+v}", in.CanDelete)
+}
@@ -0,0 +1,115 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by model-api-gen. DO NOT EDIT.
+
+package apis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeepCopyInto copies the receiver into out, in must be non-nil.
+func (in *SResourceBase) DeepCopyInto(out *SResourceBase) {
+	*out = *in
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SResourceBase) DeepCopy() *SResourceBase {
+	if in == nil {
+		return nil
+	}
+	out := new(SResourceBase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// Equal reports whether the receiver and other are deeply equal, nil and
+// empty slices or maps are equal.
+func (in *SResourceBase) Equal(other *SResourceBase) bool {
+	if in == nil || other == nil {
+		return in == other
+	}
+	if !in.CreatedAt.Equal(other.CreatedAt) {
+		return false
+	}
+	if !in.UpdatedAt.Equal(other.UpdatedAt) {
+		return false
+	}
+	return true
+}
+
+// String returns the fields of the receiver, the sensitive ones are
+// redacted.
+func (in *SResourceBase) String() string {
+	if in == nil {
+		return "<nil>"
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "{CreatedAt:%+v", in.CreatedAt)
+	fmt.Fprintf(b, " UpdatedAt:%+v", in.UpdatedAt)
+	b.WriteString("}")
+	return b.String()
+}
+
+// DeepCopyInto copies the receiver into out, in must be non-nil.
+func (in *SStandaloneResourceBase) DeepCopyInto(out *SStandaloneResourceBase) {
+	*out = *in
+	in.SResourceBase.DeepCopyInto(&out.SResourceBase)
+}
+
+// DeepCopy returns a deep copy of the receiver.
+func (in *SStandaloneResourceBase) DeepCopy() *SStandaloneResourceBase {
+	if in == nil {
+		return nil
+	}
+	out := new(SStandaloneResourceBase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// Equal reports whether the receiver and other are deeply equal, nil and
+// empty slices or maps are equal.
+func (in *SStandaloneResourceBase) Equal(other *SStandaloneResourceBase) bool {
+	if in == nil || other == nil {
+		return in == other
+	}
+	if !in.SResourceBase.Equal(&other.SResourceBase) {
+		return false
+	}
+	if in.Id != other.Id {
+		return false
+	}
+	if in.Name != other.Name {
+		return false
+	}
+	if in.Description != other.Description {
+		return false
+	}
+	return true
+}
+
+// String returns the fields of the receiver, the sensitive ones are
+// redacted.
+func (in *SStandaloneResourceBase) String() string {
+	if in == nil {
+		return "<nil>"
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "{SResourceBase:%s", in.SResourceBase.String())
+	fmt.Fprintf(b, " Id:%+v", in.Id)
+	fmt.Fprintf(b, " Name:%+v", in.Name)
+	fmt.Fprintf(b, " Description:%+v", in.Description)
+	b.WriteString("}")
+	return b.String()
+}
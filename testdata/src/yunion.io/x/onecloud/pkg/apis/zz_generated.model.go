@@ -0,0 +1,38 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by model-api-gen. DO NOT EDIT.
+
+package apis
+
+import (
+	time "time"
+)
+
+// SResourceBase is an autogenerated struct via yunion.io/x/onecloud/pkg/cloudcommon/db.SResourceBase.
+type SResourceBase struct {
+	// 资源创建时间
+	CreatedAt time.Time `json:"created_at"`
+	// 资源更新时间
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SStandaloneResourceBase is an autogenerated struct via yunion.io/x/onecloud/pkg/cloudcommon/db.SStandaloneResourceBase.
+type SStandaloneResourceBase struct {
+	SResourceBase
+	// 资源UUID
+	Id string `json:"id"`
+	// 资源名称
+	Name string `json:"name"`
+	// 资源描述信息
+	Description string `json:"description"`
+}
@@ -0,0 +1,70 @@
+// Package db is a trimmed copy of the resource model bases of
+// yunion.io/x/onecloud/pkg/cloudcommon/db used by the golden tests.
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type TokenCredential interface {
+	GetUserId() string
+}
+
+type SModelBase struct{}
+
+type SModelBaseManager struct{}
+
+type SResourceBase struct {
+	SModelBase
+
+	// 资源创建时间
+	CreatedAt time.Time `nullable:"false" created_at:"true" list:"user"`
+	// 资源更新时间
+	UpdatedAt time.Time `nullable:"false" updated_at:"true" list:"user"`
+	// 资源被删除时间
+	DeletedAt time.Time `json:"-"`
+	// 资源是否被删除
+	Deleted bool `json:"-"`
+}
+
+type SResourceBaseManager struct {
+	SModelBaseManager
+}
+
+type SStandaloneResourceBase struct {
+	SResourceBase
+
+	// 资源UUID
+	Id string `width:"128" charset:"ascii" primary:"true" list:"user"`
+	// 资源名称
+	Name string `width:"128" charset:"utf8" nullable:"false" list:"user" update:"user" create:"required"`
+	// 资源描述信息
+	Description string `width:"256" charset:"utf8" get:"user" list:"user" update:"user" create:"optional"`
+}
+
+type SStandaloneResourceBaseManager struct {
+	SResourceBaseManager
+}
+
+type StandaloneResourceListInput struct {
+	// 查询限制量
+	Limit *int `json:"limit"`
+	// 以资源名称过滤列表
+	Names []string `json:"name"`
+}
+
+type StandaloneResourceDetails struct {
+	// 资源是否可以删除
+	CanDelete bool `json:"can_delete"`
+}
+
+func (manager *SStandaloneResourceBaseManager) ListItemFilter(ctx context.Context, q interface{}, userCred TokenCredential, query StandaloneResourceListInput) (interface{}, error) {
+	return q, nil
+}
+
+// 删除资源
+// +onecloud:swagger-gen-ignore
+func (model *SStandaloneResourceBase) CustomizeDelete(ctx context.Context, userCred TokenCredential, query interface{}, data interface{}) error {
+	return nil
+}
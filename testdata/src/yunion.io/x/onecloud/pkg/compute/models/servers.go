@@ -0,0 +1,179 @@
+// Package models is a small resource model package for the golden tests of
+// model-api-gen and swagger-gen.
+package models
+
+import (
+	"context"
+	"time"
+
+	"yunion.io/x/onecloud/pkg/cloudcommon/db"
+)
+
+// TriState is true, false or none
+type TriState string
+
+// ServerStatus is the status of a server
+type ServerStatus string
+
+// ServerTags are the user tags of a server, cached by the region service
+// +onecloud:model-api-gen-doc=false
+type ServerTags map[string]string
+
+type SServerManager struct {
+	db.SStandaloneResourceBaseManager
+}
+
+// SServer is a virtual machine
+type SServer struct {
+	db.SStandaloneResourceBase
+
+	// Cpu count
+	VcpuCount int `nullable:"false" default:"1" list:"user" create:"optional"`
+	// Memory size in MB
+	VmemSize int `nullable:"false" list:"user" create:"required"`
+	// Boot order of devices, e.g. cdn
+	//
+	// Deprecated: use the boot index of disks instead.
+	BootOrder string `list:"user"`
+	// 虚拟机状态
+	Status ServerStatus `width:"36" charset:"ascii" list:"user"`
+	// 是否禁用
+	Disabled TriState `list:"user"`
+	// 最近一次启动时间
+	LastStartAt time.Time `list:"user"`
+	// Start the server with its host
+	// +onecloud:model-api-gen-field=type:*bool
+	// +onecloud:model-api-gen-field=omitempty
+	AutoStart string `list:"user"`
+	// Id of the backup host
+	// +onecloud:model-api-gen-field=rename:BackupHostID
+	BackupHostId string `list:"user"`
+	// +onecloud:model-api-gen-field=skip
+	PendingDeleted bool `list:"user"`
+	// Metadata of the image, kept by the image cache
+	// +onecloud:model-api-gen-field=include
+	ImageMeta map[string]string `json:"image_meta"`
+	// Timeout of the guest agent
+	AgentTimeout *time.Duration `list:"user"`
+	Tags         ServerTags
+	Disks        []SDisk `json:"disks"`
+	Secret       string  `json:"-"`
+	hostId       string
+}
+
+// SDisk is a disk attached to server
+//
+// Deprecated: disks are listed by the disk resource.
+type SDisk struct {
+	// 磁盘大小, 单位MB
+	DiskSize int `json:"disk_size"`
+	// +onecloud:model-api-gen-doc=false
+	// Id of the storage, resolved by the scheduler cache
+	Storage string `json:"storage"`
+	// Passphrase of the encrypted disk
+	EncryptPassword string `json:"encrypt_password"`
+}
+
+type ServerDetails struct {
+	db.StandaloneResourceDetails
+	SServer
+
+	// 宿主机名称
+	Host string `json:"host"`
+	// Disks grouped by storage
+	StorageDisks map[string][]*SDisk `json:"storage_disks"`
+	// Password of the vnc console
+	VncPassword string `json:"vnc_password"`
+}
+
+type ServerListInput struct {
+	db.StandaloneResourceListInput
+
+	// 以宿主机过滤
+	Host string `json:"host"`
+	// 以状态过滤
+	// enum: running,ready
+	Status []string `json:"status"`
+}
+
+type ServerCreateInput struct {
+	// +onecloud:swagger-gen-field-required
+	// +onecloud:swagger-gen-field-pattern=^[a-z][a-z0-9-]*$
+	Name string `json:"name"`
+	// default: 1
+	// minimum: 1
+	// maximum: 64
+	VcpuCount int `json:"vcpu_count"`
+}
+
+type ServerUpdateInput struct {
+	Description string `json:"description"`
+}
+
+type ServerStartInput struct {
+	// 自动调度
+	AutoPrepare bool `json:"auto_prepare"`
+}
+
+type ServerVncOutput struct {
+	Url string `json:"url"`
+}
+
+type ServerStatistics struct {
+	Count map[string]int `json:"count"`
+}
+
+func (manager *SServerManager) FetchCustomizeColumns(ctx context.Context, userCred db.TokenCredential, query interface{}, objs []interface{}, fields []string, isList bool) []ServerDetails {
+	return nil
+}
+
+func (manager *SServerManager) ValidateCreateData(ctx context.Context, userCred db.TokenCredential, ownerId interface{}, query interface{}, input ServerCreateInput) (ServerCreateInput, error) {
+	return input, nil
+}
+
+// 虚拟机列表
+func (manager *SServerManager) ListItemFilter(ctx context.Context, q interface{}, userCred db.TokenCredential, query ServerListInput) (interface{}, error) {
+	return q, nil
+}
+
+// 批量启动虚拟机
+func (manager *SServerManager) PerformBatchStart(ctx context.Context, userCred db.TokenCredential, query interface{}, input *ServerStartInput) (*ServerStartInput, error) {
+	return nil, nil
+}
+
+// 获取虚拟机统计信息
+func (manager *SServerManager) GetPropertyStatistics(ctx context.Context, userCred db.TokenCredential, query ServerListInput) (*ServerStatistics, error) {
+	return nil, nil
+}
+
+func (server *SServer) ValidateUpdateData(ctx context.Context, userCred db.TokenCredential, query interface{}, input ServerUpdateInput) (ServerUpdateInput, error) {
+	return input, nil
+}
+
+// 启动虚拟机
+// +onecloud:swagger-gen-resp-error=400,InvalidStatusError
+func (server *SServer) PerformStart(ctx context.Context, userCred db.TokenCredential, query interface{}, input *ServerStartInput) (*ServerStartInput, error) {
+	return nil, nil
+}
+
+// 重置虚拟机密码, 内部方法
+// +onecloud:swagger-gen-ignore
+func (server *SServer) PerformResetPassword(ctx context.Context, userCred db.TokenCredential, query interface{}, input *ServerStartInput) (*ServerStartInput, error) {
+	return nil, nil
+}
+
+// 获取虚拟机VNC地址, 形如 $host:$port
+func (server *SServer) GetDetailsVnc(ctx context.Context, userCred db.TokenCredential, query interface{}) (*ServerVncOutput, error) {
+	return nil, nil
+}
+
+// +onecloud:swagger-gen-route-method=GET
+// +onecloud:swagger-gen-route-path=/ping
+// +onecloud:swagger-gen-route-tag=ping
+// +onecloud:swagger-gen-param-query-index=1
+// +onecloud:swagger-gen-resp-index=0
+
+// 检查服务状态
+func Ping(ctx context.Context, query ServerListInput) (*ServerVncOutput, error) {
+	return nil, nil
+}
@@ -0,0 +1,33 @@
+// Package models of image declares routes by function tags, it's merged
+// with the compute models into one spec by the golden tests of swagger-gen.
+package models
+
+import (
+	"context"
+)
+
+// PingOutput is the reply of health check
+type PingOutput struct {
+	Status string `json:"status"`
+	Uptime int64  `json:"uptime"`
+}
+
+// +onecloud:swagger-gen-route-method=GET
+// +onecloud:swagger-gen-route-path=/healthz
+// +onecloud:swagger-gen-route-tag=ping
+// +onecloud:swagger-gen-resp-index=0
+
+// 检查服务健康状态
+func Ping(ctx context.Context) (*PingOutput, error) {
+	return nil, nil
+}
+
+// +onecloud:swagger-gen-route-method=GET
+// +onecloud:swagger-gen-route-path=/ping
+// +onecloud:swagger-gen-route-tag=version
+// +onecloud:swagger-gen-resp-index=0
+
+// 获取服务版本
+func Version(ctx context.Context) (*PingOutput, error) {
+	return nil, nil
+}